@@ -17,31 +17,62 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 )
 
 const (
 	// ProgramName is the name of the program.
-	ProgramName           = "minkapi"
-	DefaultWatchQueueSize = 100
+	ProgramName = "minkapi"
+	// DefaultWatchQueueSize is the default number of events queued per watcher before it is terminated for lagging
+	// behind.
+	DefaultWatchQueueSize = 1000
 	DefaultWatchTimeout   = 5 * time.Minute
+	// DefaultWatchHistorySize is the default number of most recent changes retained per resource store for watches
+	// starting from a past resource version.
+	DefaultWatchHistorySize = 1000
+	// DefaultWatchBookmarkInterval is the default interval at which bookmark events are sent to watchers that allow them.
+	DefaultWatchBookmarkInterval = 1 * time.Minute
 	// DefaultKubeConfigPath is the default kubeconfig path if none is specified.
 	DefaultKubeConfigPath = "/tmp/minkapi.yaml"
 	// DefaultBasePrefix is the default path prefix for the base minkapi server
 	DefaultBasePrefix = "base"
+	// DefaultEventMaxCount is the default maximum number of events retained by the EventSink of a view.
+	DefaultEventMaxCount = 10000
+	// DefaultEventTTL is the default duration for which an event is retained after it was last recorded, which is the
+	// default event TTL of the kube-apiserver.
+	DefaultEventTTL = 1 * time.Hour
 )
 
 // WatchConfig holds config parameters relevant for watchers.
 type WatchConfig struct {
-	// QueueSize is the maximum number of events to queue per watcher
+	// QueueSize is the maximum number of events to queue per watcher. A watcher lagging behind by more events is
+	// terminated with a 410 Expired error, so that it relists instead of stalling the store and its other watchers.
 	QueueSize int
 	// Timeout represents the timeout for watches following which MinKAPI service will close the connection and ends the watch.
 	Timeout time.Duration
+	// HistorySize is the maximum number of most recent changes retained per resource store. Watches starting from a
+	// resource version older than the retained changes fail with a 410 Expired error.
+	HistorySize int
+	// BookmarkInterval is the interval at which watch.Bookmark events are sent to watchers that allow bookmarks.
+	BookmarkInterval time.Duration
+}
+
+// EventConfig holds the retention policy of the events recorded in the EventSink of each view. Repeated events are
+// aggregated into a single event whose series count is incremented, so that only distinct events count towards the
+// MaxCount.
+type EventConfig struct {
+	// MaxCount is the maximum number of events retained. The least recently recorded events are evicted once exceeded.
+	MaxCount int
+	// TTL is the duration for which an event is retained after it was last recorded.
+	TTL time.Duration
 }
 
 // Config holds the configuration for MinKAPI.
@@ -51,6 +82,84 @@ type Config struct {
 	BasePrefix string
 	commontypes.ServerConfig
 	WatchConfig WatchConfig
+	// EventConfig holds the retention policy of the events recorded in the EventSink of each view.
+	EventConfig EventConfig
+	// LoadSnapshotPath is the optional path of a ViewArchive that is loaded into the base View on startup.
+	LoadSnapshotPath string
+	// SaveOnExitPath is the optional path to which the base View is saved as a ViewArchive on shutdown.
+	SaveOnExitPath string
+	// AuditLogPath is the optional path of the file to which the mutations made through all views are appended as
+	// AuditEntry JSON lines.
+	AuditLogPath string
+	// ReplayAuditLogPath is the optional path of an audit log whose mutations are replayed into the base View on
+	// startup, after loading the LoadSnapshotPath if any.
+	ReplayAuditLogPath string
+	// ReplayAuditLogViews restricts the replay of the ReplayAuditLogPath to the entries of the views with these names.
+	// All entries are replayed if empty.
+	ReplayAuditLogViews []string
+	// SecurityConfig holds the TLS and authentication configuration. The minkapi service serves plain HTTP without
+	// authentication by default.
+	SecurityConfig SecurityConfig
+	// MirrorConfig holds the configuration of the mirror mode, in which the base View is kept in sync with a source
+	// cluster. The mirror mode is disabled by default.
+	MirrorConfig MirrorConfig
+	// IndexedLabelKeys are the label keys by whose value the objects of all kinds are indexed in the resource stores of
+	// all views, in addition to the indexes of each kind.
+	IndexedLabelKeys []string
+	// Clock is the source of the time used by all views for timestamps, graceful deletion and watch timeouts.
+	// Optional - defaults to the real clock. Tests and simulations can set a fake clock to control the passage of time.
+	Clock clock.WithTickerAndDelayedExecution
+}
+
+// MirrorConfig holds the configuration of the mirror mode of the minkapi service, in which informers on a source
+// cluster keep the objects of the base View continuously in sync with those of the source cluster.
+type MirrorConfig struct {
+	// SourceKubeConfigPath is the path of the kubeconfig of the source cluster. The mirror mode is enabled if set.
+	SourceKubeConfigPath string
+	// Kinds restricts the mirrored kinds to those with these resource names or kinds (Ex: pods or Pod). All built-in
+	// kinds are mirrored if empty. Custom resources are not mirrored.
+	Kinds []string
+	// Namespaces restricts the mirrored objects of namespaced kinds to those in these namespaces. The objects of all
+	// namespaces are mirrored if empty.
+	Namespaces []string
+	// ResyncPeriod is the interval at which the informers on the source cluster resync the base View. Objects are only
+	// synced on changes if zero.
+	ResyncPeriod time.Duration
+}
+
+// Enabled returns whether the mirror mode is enabled.
+func (c MirrorConfig) Enabled() bool {
+	return c.SourceKubeConfigPath != ""
+}
+
+// AuthMode is the mode in which the minkapi service authenticates clients.
+type AuthMode string
+
+const (
+	// AuthModeNone accepts requests from all clients.
+	AuthModeNone AuthMode = "none"
+	// AuthModeToken accepts requests carrying the bearer token of the minkapi service.
+	AuthModeToken AuthMode = "token"
+	// AuthModeClientCert accepts requests from clients presenting a certificate issued by the CA of the minkapi service.
+	// Requires TLS.
+	AuthModeClientCert AuthMode = "client-cert"
+)
+
+// SecurityConfig holds the TLS and authentication configuration of the minkapi service.
+type SecurityConfig struct {
+	// TLSEnabled makes the minkapi service serve HTTPS with a server certificate issued by its CA. The CA is loaded from
+	// CertDir if present there, otherwise a self-signed CA is generated and written to CertDir.
+	TLSEnabled bool
+	// CertDir is the directory holding the CA, server and client certificates and keys of the minkapi service. It must
+	// be owned by the current user and not writable by other users, and the CA key must not be accessible by them.
+	// Defaults to a minkapi directory in the user cache dir.
+	CertDir string
+	// AuthMode is the mode in which clients are authenticated. Defaults to AuthModeNone.
+	AuthMode AuthMode
+	// TokenFile is the optional path of a file holding the bearer token used with AuthModeToken. If it does not exist,
+	// a random token is generated and written to it. Like the CA key, it must be owned by the current user and not be
+	// accessible by other users. Defaults to a file named token in CertDir.
+	TokenFile string
 }
 
 // Resettable defines types that can reset their state to a default or initial configuration.
@@ -60,6 +169,61 @@ type Resettable interface {
 
 type WatchEventCallback func(watch.Event) (err error)
 
+// WatchOptions holds the options for watching objects in a ResourceStore or a View.
+type WatchOptions struct {
+	// MatchCriteria restricts the watch to matching objects. Limit and Continue are ignored.
+	MatchCriteria
+	// StartVersion is the resource version after which changes are sent. If not positive, the watch begins with
+	// synthetic watch.Added events for all objects currently matching the criteria.
+	StartVersion int64
+	// SendInitialEvents requests synthetic watch.Added events for all objects currently matching the criteria followed
+	// by a watch.Bookmark event annotated with metav1.InitialEventsAnnotationKey, regardless of the StartVersion.
+	SendInitialEvents bool
+	// AllowBookmarks permits periodic watch.Bookmark events carrying the resource version up to which changes have been sent.
+	AllowBookmarks bool
+}
+
+// PatchOptions holds the options for a patch of an object in a View.
+type PatchOptions struct {
+	// FieldManager is the name of the actor making the patch. If set, an Update entry of the field manager is recorded in
+	// the managedFields of the patched object like in the kube-apiserver.
+	FieldManager string
+}
+
+// ApplyOptions holds the options for a server-side apply of an object in a View.
+type ApplyOptions struct {
+	// FieldManager is the name of the actor making the apply. Required.
+	FieldManager string
+	// Force makes the apply take ownership of fields owned by other field managers instead of failing with a 409 Conflict.
+	Force bool
+}
+
+// DeleteOptions holds the options for deleting an object in a ResourceStore or a View.
+//
+// Like in the kube-apiserver, an object with finalizers is only marked for deletion by setting its deletionTimestamp.
+// It is removed once its finalizers are cleared by an update or patch.
+type DeleteOptions struct {
+	// Preconditions must be fulfilled by the stored object, otherwise the deletion fails with a 409 Conflict.
+	Preconditions metav1.Preconditions
+	// GracePeriodSeconds is the duration after which a pod marked for deletion is removed. Like in the kube-apiserver,
+	// it is only honoured for pods. Since there are no kubelets confirming the termination of pods, pods are removed
+	// immediately unless a positive grace period is requested explicitly.
+	GracePeriodSeconds *int64
+	// PropagationPolicy determines how the garbage collector of the view treats the dependents of the object. With
+	// metav1.DeletePropagationForeground, the object is only removed once its blocking dependents have been deleted.
+	// With metav1.DeletePropagationOrphan, the owner references to the object are removed from its dependents. By
+	// default, dependents are deleted in the background once the object has been removed.
+	PropagationPolicy *metav1.DeletionPropagation
+}
+
+// ResourceStore holds the objects of a single kind.
+//
+// Objects never cross the boundary of a store by reference, so that a store can be used concurrently by in-process
+// callers without races:
+//   - Objects passed to Add and Update remain owned by the caller, which may mutate them afterwards. The store keeps a
+//     copy and only sets the assigned resource version in the given object.
+//   - Objects returned by GetByKey, Get, List, ListMetaObjects and DeleteObjects, and the objects of the events passed
+//     to the callback of Watch, are copies owned by the caller.
 type ResourceStore interface {
 	Resettable
 	io.Closer
@@ -70,18 +234,41 @@ type ResourceStore interface {
 	GetByKey(key string) (o runtime.Object, err error)
 	Get(objName cache.ObjectName) (o runtime.Object, err error)
 	Update(mo metav1.Object) error
-	DeleteByKey(key string) error
-	Delete(objName cache.ObjectName) error
+	DeleteByKey(key string, opts DeleteOptions) error
+	Delete(objName cache.ObjectName, opts DeleteOptions) error
 
-	DeleteObjects(c MatchCriteria) (delCount int, err error)
+	// DeleteObjects deletes the objects matching the given criteria like Delete honouring the given opts, and returns
+	// the matched objects as they were before their deletion.
+	DeleteObjects(c MatchCriteria, opts DeleteOptions) (delObjs []metav1.Object, err error)
 	List(c MatchCriteria) (listObj runtime.Object, err error)
 
 	ListMetaObjects(c MatchCriteria) (metaObjs []metav1.Object, maxVersion int64, err error)
+	// ListMetaObjectsAt returns the objects matching the given criteria as they were at the given resource version, which
+	// are reconstructed from the watch history of the store. A 410 Expired error is returned if changes made after the
+	// given version are no longer available in the history.
+	ListMetaObjectsAt(c MatchCriteria, version int64) (metaObjs []metav1.Object, err error)
 
-	Watch(ctx context.Context, startVersion int64, namespace string, labelSelector labels.Selector, eventCallback WatchEventCallback) error
+	Watch(ctx context.Context, opts WatchOptions, eventCallback WatchEventCallback) error
 
 	// GetVersionCounter returns the atomic counter for generating monotonically increasing resource versions
 	GetVersionCounter() *atomic.Int64
+
+	// GetStats returns a point-in-time snapshot of the object and watch statistics of this resource store.
+	GetStats() ResourceStoreStats
+}
+
+// ResourceStoreStats holds a point-in-time snapshot of the statistics of a ResourceStore.
+type ResourceStoreStats struct {
+	// ObjectCount is the number of objects held by the store.
+	ObjectCount int
+	// WatcherCount is the number of active watches on the store.
+	WatcherCount int
+	// MaxWatchQueueDepth is the largest number of events broadcast to a single watcher of the store that have not been
+	// consumed yet. A watcher whose queue is full when a change is broadcast is terminated.
+	MaxWatchQueueDepth int
+	// DroppedWatcherCount is the number of watchers terminated since the creation of the store as their queue of
+	// WatchConfig.QueueSize events was full when a change was broadcast.
+	DroppedWatcherCount int64
 }
 
 type ResourceStoreArgs struct {
@@ -93,44 +280,113 @@ type ResourceStoreArgs struct {
 	WatchConfig WatchConfig
 	// VersionCounter is the atomic counter for generating monotonically increasing resource versions
 	VersionCounter *atomic.Int64 //optional
-	Log            logr.Logger
+	// SelectableFields returns the field labels and values of objects in this store that can be used in field selectors.
+	// Optional - defaults to DefaultObjectFields.
+	SelectableFields ObjectFieldsFunc
+	// Indexes are the secondary indexes maintained by this store. Optional - objects are only indexed by key if empty.
+	Indexes IndexConfig
+	// Clock is the source of the time used for graceful deletion, watch bookmarks and watch timeouts.
+	// Optional - defaults to the real clock.
+	Clock clock.WithTickerAndDelayedExecution
+	Log   logr.Logger
+}
+
+// IndexConfig specifies the secondary indexes of a resource store. A list whose MatchCriteria restricts the objects
+// to a namespace, to an exact value of an indexed field label or to the values of an indexed label key only visits
+// the objects of the most selective of the matching indexes instead of all objects of the store.
+type IndexConfig struct {
+	// Namespace indexes objects by their namespace.
+	Namespace bool
+	// FieldLabels are the selectable field labels by whose value objects are indexed, Ex: spec.nodeName for pods.
+	FieldLabels []string
+	// LabelKeys are the label keys by whose value objects are indexed. Objects without the label are not indexed.
+	LabelKeys []string
 }
 
+// ObjectFieldsFunc returns the set of field labels and values of the given object that are supported in field selectors.
+// This is the equivalent of the field label conversion funcs registered for each kind in the real API server.
+type ObjectFieldsFunc func(obj metav1.Object) fields.Set
+
+// EventSink records the events of a view. Events are recorded through the events.k8s.io/v1 API by implementing
+// events.EventSink, and through the core/v1 API by the record.EventSink returned by CoreV1. Repeated events regarding
+// the same object with the same type, reason, action and reporter are aggregated like by the event recorders of
+// client-go: instead of adding an event, the series count and last observed time of the existing event are updated.
+// The number and age of retained events is bounded by the EventConfig of the view. The events are persisted in the
+// event stores of the view, so that they are served by its API along with the events written through it.
 type EventSink interface {
 	Resettable
 	events.EventSink
+	// CoreV1 returns the sink for core/v1 events, as recorded by a record.EventBroadcaster. The core/v1 events are
+	// converted to events.k8s.io/v1 events and aggregated with the other events of this sink.
+	CoreV1() record.EventSink
+	// Delete removes the event with the name and namespace of the given event from this sink.
+	Delete(ctx context.Context, event *eventsv1.Event) error
+	// List returns the events of this sink in the order in which they were first recorded.
 	List() []*eventsv1.Event
+	// RemoveExpired removes the events last recorded longer than the TTL of the EventConfig ago. Expired events are
+	// also removed whenever events are recorded or listed through this sink.
+	RemoveExpired()
 }
 
 // View is the high-level facade to a repository of objects of different types (GVK).
+//
+// Like for a ResourceStore, objects passed to a View remain owned by the caller and objects returned by a View are
+// copies owned by the caller. CreateObject and UpdateObject set the metadata assigned by the view (Ex: name, uid,
+// resourceVersion) in the given object.
 // TODO: Think of a better name. Rename this to ObjectRepository or something else, also add godoc ?
 type View interface {
 	Resettable
 	io.Closer
 	GetName() string
 	GetType() ViewType
+	// GetClock returns the clock that is the source of the time used by this view.
+	GetClock() clock.WithTickerAndDelayedExecution
 	// GetClientFacades gets the in-memory implementation ClientFacades that can be used by code to interact with this view
 	// via standard k8s client and informer interfaces
 	GetClientFacades() (commontypes.ClientFacades, error)
 	GetResourceStore(gvk schema.GroupVersionKind) (ResourceStore, error)
 	GetEventSink() EventSink
+	// GetAuditSink returns the sink recording the mutations made through this view, or nil if mutations are not audited.
+	GetAuditSink() AuditSink
 	CreateObject(gvk schema.GroupVersionKind, obj metav1.Object) error
 	GetObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (runtime.Object, error)
 	UpdateObject(gvk schema.GroupVersionKind, obj metav1.Object) error
-	UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (*corev1.Pod, error)
-	PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte) (patchedObj runtime.Object, err error)
-	PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchData []byte) (patchedObj runtime.Object, err error)
+	// UpdatePodNodeBinding binds the pod with the given name to the target node of the given binding, and returns the pod
+	// as it was stored before and after the binding.
+	UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (prevPod, pod *corev1.Pod, err error)
+	// PatchObject applies the given patch to the object with the given name honouring the given opts, and returns the
+	// object as it was stored before and after the patch.
+	PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts PatchOptions) (prevObj, patchedObj runtime.Object, err error)
+	// PatchObjectStatus applies the given patch to the status of the object with the given name like PatchObject.
+	PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts PatchOptions) (prevObj, patchedObj runtime.Object, err error)
+	// ApplyObject performs a server-side apply of the given apply configuration to the object with the given name,
+	// creating the object if it does not exist. The managedFields of the object are updated to record the fields owned
+	// by the field manager of the given opts. The object is returned as it was stored before and after the apply, with
+	// a nil prevObj if it was created.
+	ApplyObject(gvk schema.GroupVersionKind, objName cache.ObjectName, applyData []byte, opts ApplyOptions) (prevObj, appliedObj runtime.Object, err error)
 	ListMetaObjects(gvk schema.GroupVersionKind, criteria MatchCriteria) (metaObjs []metav1.Object, maxVersion int64, err error)
 	ListObjects(gvk schema.GroupVersionKind, criteria MatchCriteria) (runtime.Object, error)
-	WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, startVersion int64, namespace string, labelSelector labels.Selector, eventCallback WatchEventCallback) error
-	DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName) error
-	DeleteObjects(gvk schema.GroupVersionKind, criteria MatchCriteria) error
+	WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, opts WatchOptions, eventCallback WatchEventCallback) error
+	// DeleteObject deletes the object with the given name honouring the given opts.
+	DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName, opts DeleteOptions) error
+	// DeleteObjects deletes the objects matching the given criteria like DeleteObject honouring the given opts, and
+	// returns the matched objects as they were before their deletion.
+	DeleteObjects(gvk schema.GroupVersionKind, criteria MatchCriteria, opts DeleteOptions) (delObjs []metav1.Object, err error)
 	ListNodes(matchingNodeNames ...string) ([]corev1.Node, error)
 	ListPods(namespace string, matchingPodNames ...string) ([]corev1.Pod, error)
-	ListEvents(namespace string) ([]eventsv1.Event, error)
+	// ListEvents returns the events of the given namespace created through the events.k8s.io/v1 and core/v1 APIs of
+	// this view, including those recorded in its EventSink, ordered by the time they were last observed. If regarding
+	// objects are given, only the events regarding any of them are returned. An object reference matches by kind and
+	// name, and also by namespace, UID and API version if these are set.
+	ListEvents(namespace string, regarding ...corev1.ObjectReference) ([]eventsv1.Event, error)
 	// GetObjectChangeCount returns the current change count made to objects through this view.
 	GetObjectChangeCount() int64
 	GetKubeConfigPath() string
+	// Save writes a ViewArchive of all objects, resource versions and events of this view to the given writer.
+	Save(w io.Writer) error
+	// Load replaces all objects, resource versions and events of this view with the ViewArchive read from the given
+	// reader. Existing watches are terminated.
+	Load(r io.Reader) error
 }
 
 type ViewType string
@@ -151,6 +407,15 @@ type ViewArgs struct {
 	// Scheme is the runtime Scheme used by KAPI objects exposed by this view
 	Scheme      *runtime.Scheme
 	WatchConfig WatchConfig
+	// EventConfig holds the retention policy of the events recorded in the EventSink of this view.
+	EventConfig EventConfig
+	// AuditSink is the optional sink recording the mutations made through this view.
+	AuditSink AuditSink
+	// IndexedLabelKeys are the label keys by whose value the objects of all kinds are indexed in the resource stores of
+	// this view, in addition to the indexes of each kind.
+	IndexedLabelKeys []string
+	// Clock is the source of the time used by this view and its resource stores. Optional - defaults to the real clock.
+	Clock clock.WithTickerAndDelayedExecution
 }
 
 // Server represents a MinKAPI server that provides access to a KAPI (kubernetes API) service accessible at http://<MinKAPIHost>:<MinKAPIPort>/base
@@ -165,6 +430,9 @@ type Server interface {
 	//
 	// TODO: discuss whether the above is OK.
 	GetSandboxView(ctx context.Context, name string) (View, error)
+	// HasBaseViewSynced returns whether the base View has been synced with the source cluster in mirror mode. It always
+	// returns true if the mirror mode is disabled.
+	HasBaseViewSynced() bool
 }
 
 // App represents an application that wraps a minkapi Server, an application context and application cancel func.
@@ -181,9 +449,24 @@ type MatchCriteria struct {
 	Names     sets.Set[string]
 	// Labels        map[string]string
 	LabelSelector labels.Selector
+	// FieldSelector restricts matches by the field labels of objects. See ObjectFieldsFunc.
+	FieldSelector fields.Selector
+	// Limit is the maximum number of objects to return in a list chunk. Zero means no limit.
+	// Only honoured by List operations.
+	Limit int64
+	// Continue is the opaque token returned in the metadata of a previous list chunk and is used to retrieve the next
+	// chunk. Only honoured by List operations.
+	Continue string
 }
 
+// Matches checks whether the given object satisfies this criteria. The FieldSelector is evaluated against the
+// DefaultObjectFields of the object - use MatchesWithFields to evaluate it against kind-specific fields.
 func (c MatchCriteria) Matches(obj metav1.Object) bool {
+	return c.MatchesWithFields(obj, DefaultObjectFields(obj))
+}
+
+// MatchesWithFields checks whether the given object having the given selectable objFields satisfies this criteria.
+func (c MatchCriteria) MatchesWithFields(obj metav1.Object, objFields fields.Set) bool {
 	if c.Namespace != "" && obj.GetNamespace() != c.Namespace {
 		return false
 	}
@@ -193,5 +476,16 @@ func (c MatchCriteria) Matches(obj metav1.Object) bool {
 	if c.LabelSelector != nil && !c.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
 		return false
 	}
+	if c.FieldSelector != nil && !c.FieldSelector.Matches(objFields) {
+		return false
+	}
 	return true
 }
+
+// DefaultObjectFields returns the field labels supported by all kinds, ie `metadata.name` and `metadata.namespace`.
+func DefaultObjectFields(obj metav1.Object) fields.Set {
+	return fields.Set{
+		"metadata.name":      obj.GetName(),
+		"metadata.namespace": obj.GetNamespace(),
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -44,6 +45,18 @@ func TestMatchCriteria(t *testing.T) {
 			criteria: MatchCriteria{Namespace: "default", LabelSelector: labels.SelectorFromSet(map[string]string{"k1": "v2"})},
 			matches:  false,
 		},
+		"matching name field": {
+			criteria: MatchCriteria{FieldSelector: fields.OneTermEqualSelector("metadata.name", "bingo")},
+			matches:  true,
+		},
+		"not matching namespace field": {
+			criteria: MatchCriteria{FieldSelector: fields.OneTermNotEqualSelector("metadata.namespace", "default")},
+			matches:  false,
+		},
+		"matching label but not field": {
+			criteria: MatchCriteria{LabelSelector: labels.SelectorFromSet(map[string]string{"k1": "v1"}), FieldSelector: fields.OneTermEqualSelector("metadata.name", "abcd")},
+			matches:  false,
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -53,3 +66,37 @@ func TestMatchCriteria(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchCriteriaWithFields(t *testing.T) {
+	testPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bingo",
+			Namespace: "default",
+		},
+	}
+	podFields := fields.Set{"metadata.name": "bingo", "metadata.namespace": "default", "spec.nodeName": "node-a", "status.phase": "Running"}
+
+	tests := map[string]struct {
+		fieldSelector string
+		matches       bool
+	}{
+		"matching node name":                 {fieldSelector: "spec.nodeName=node-a", matches: true},
+		"not matching node name":             {fieldSelector: "spec.nodeName=node-b", matches: false},
+		"unassigned pods":                    {fieldSelector: "spec.nodeName=", matches: false},
+		"not succeeded and not failed":       {fieldSelector: "status.phase!=Succeeded,status.phase!=Failed", matches: true},
+		"matching name and not running":      {fieldSelector: "metadata.name=bingo,status.phase!=Running", matches: false},
+		"field unknown to the object fields": {fieldSelector: "spec.schedulerName=default-scheduler", matches: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := fields.ParseSelector(tc.fieldSelector)
+			if err != nil {
+				t.Fatalf("cannot parse field selector %q: %v", tc.fieldSelector, err)
+			}
+			c := MatchCriteria{FieldSelector: selector}
+			if got := c.MatchesWithFields(&testPod, podFields); got != tc.matches {
+				t.Errorf("MatchesWithFields()=%t for field selector %q, want %t", got, tc.fieldSelector, tc.matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package minkapi
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ViewArchiveVersion is the current format version of a ViewArchive. It must be bumped on incompatible format changes.
+const ViewArchiveVersion = "v1"
+
+// ViewArchive is the serialized form of a View produced by View.Save and consumed by View.Load.
+type ViewArchive struct {
+	// Version is the format version of the archive. See ViewArchiveVersion.
+	Version string `json:"version"`
+	// ViewName is the name of the view from which the archive was saved.
+	ViewName string `json:"viewName"`
+	// ViewType is the type of the view from which the archive was saved.
+	ViewType ViewType `json:"viewType"`
+	// CreationTimestamp is the time at which the archive was saved.
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// Stores holds the archives of the resource stores of the view, including the events persisted by its EventSink.
+	Stores []StoreArchive `json:"stores"`
+}
+
+// StoreArchive is the serialized form of the objects in a ResourceStore.
+type StoreArchive struct {
+	// APIVersion is the group version of the objects in the store.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the objects in the store.
+	Kind string `json:"kind"`
+	// ResourceVersion is the current resource version of the store. The objects retain their own resource versions.
+	ResourceVersion int64 `json:"resourceVersion"`
+	// Objects holds the JSON encoded objects of the store.
+	Objects []json.RawMessage `json:"objects"`
+}
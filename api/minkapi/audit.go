@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package minkapi
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuditVerb is the kind of mutation recorded in an AuditEntry.
+type AuditVerb string
+
+const (
+	// AuditVerbCreate records the creation of an object.
+	AuditVerbCreate AuditVerb = "create"
+	// AuditVerbUpdate records the replacement of an object.
+	AuditVerbUpdate AuditVerb = "update"
+	// AuditVerbPatch records a patch or a server-side apply of an object.
+	AuditVerbPatch AuditVerb = "patch"
+	// AuditVerbBind records the binding of a pod to a node.
+	AuditVerbBind AuditVerb = "bind"
+	// AuditVerbDelete records the deletion of an object.
+	AuditVerbDelete AuditVerb = "delete"
+)
+
+// AuditEntry records a mutation made to an object through a View. Audit entries are written as JSON lines by an
+// AuditSink and can be replayed to reproduce the mutations on another view.
+type AuditEntry struct {
+	// Timestamp is the time at which the mutation was made.
+	Timestamp metav1.MicroTime `json:"timestamp"`
+	// ViewName is the name of the view through which the mutation was made.
+	ViewName string `json:"viewName"`
+	// Actor identifies the client that made the mutation. For requests served by the minkapi service this is the
+	// user agent of the request.
+	Actor string `json:"actor,omitempty"`
+	// Verb is the kind of the mutation.
+	Verb AuditVerb `json:"verb"`
+	// APIVersion is the group version of the mutated object.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the mutated object.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the mutated object. Empty for cluster scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the mutated object.
+	Name string `json:"name"`
+	// ResourceVersion is the resource version of the object after the mutation. Empty if the object was removed.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Object is the JSON encoded object as created. Only set for AuditVerbCreate.
+	Object json.RawMessage `json:"object,omitempty"`
+	// Diff is the JSON merge patch from the state of the object before the mutation to its state after the mutation,
+	// excluding the resource version. Only set for AuditVerbUpdate, AuditVerbPatch and AuditVerbBind.
+	Diff json.RawMessage `json:"diff,omitempty"`
+	// PropagationPolicy is the propagation policy requested for the deletion. Only set for AuditVerbDelete.
+	PropagationPolicy *metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+	// GracePeriodSeconds is the grace period requested for the deletion. Only set for AuditVerbDelete.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+}
+
+// AuditSink records the mutations made through a View.
+type AuditSink interface {
+	// Record records the given audit entry.
+	Record(entry AuditEntry) error
+}
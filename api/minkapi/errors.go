@@ -28,4 +28,16 @@ var (
 	ErrUpdateObject = errors.New("cannot update object")
 
 	ErrCreateSandbox = errors.New("cannot create sandbox")
+
+	// ErrSetupSecurity is a sentinel error indicating that the TLS or authentication setup of the service failed.
+	ErrSetupSecurity = errors.New("cannot setup security")
+
+	ErrSaveView = errors.New("cannot save view")
+	ErrLoadView = errors.New("cannot load view")
+
+	// ErrReplayAuditLog is a sentinel error indicating that the replay of an audit log into a view failed.
+	ErrReplayAuditLog = errors.New("cannot replay audit log")
+
+	// ErrMirror is a sentinel error indicating that the base view cannot be mirrored from the source cluster.
+	ErrMirror = errors.New("cannot mirror source cluster")
 )
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
 	"time"
 )
 
@@ -93,6 +94,10 @@ type ScalingAdvisorServiceConfig struct {
 	SchedulerConfigPath string
 	// MaxConcurrentSimulations is the maximum number of concurrent simulations that can be run by the scaling advisor service.
 	MaxConcurrentSimulations int
+	// Clock is the source of the time used by the MinKAPI server of the scaling advisor service, and so by the views in
+	// which simulations run, unless MinKAPIConfig.Clock is set. Optional - defaults to the real clock. A fake clock lets
+	// simulations advance virtual time instead of waiting on real time.
+	Clock clock.WithTickerAndDelayedExecution
 }
 
 // ScalingAdviceResponseFn is a callback function which is invoked by the scaling advisor service when generating scaling advice.
@@ -7,6 +7,7 @@ package clientutil
 import (
 	commontypes "github.com/gardener/scaling-advisor/api/common/types"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
@@ -16,7 +17,8 @@ import (
 	"time"
 )
 
-// BuildClients currently constructs static and dynamic client-go clients given a kubeconfig file.
+// BuildClients currently constructs static and dynamic client-go clients given a kubeconfig file. The static client
+// prefers the protobuf wire format over JSON.
 func BuildClients(log logr.Logger, kubeConfigPath string) (client kubernetes.Interface, dynClient dynamic.Interface, err error) {
 	clientConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
 	if err != nil {
@@ -28,7 +30,9 @@ func BuildClients(log logr.Logger, kubeConfigPath string) (client kubernetes.Int
 			log: log,
 		}
 	}
-	clientConfig.ContentType = "application/json"
+	// protobuf is used for built-in kinds and JSON for custom resources. The dynamic client always uses JSON.
+	clientConfig.ContentType = runtime.ContentTypeProtobuf
+	clientConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
 	client, err = kubernetes.NewForConfig(clientConfig)
 	if err != nil {
 		return
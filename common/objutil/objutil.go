@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -140,6 +142,9 @@ func PatchObject(objPtr runtime.Object, name cache.ObjectName, patchType types.P
 	var patchedBytes []byte
 	switch patchType {
 	case types.StrategicMergePatchType:
+		if _, ok := objPtr.(runtime.Unstructured); ok {
+			return newUnsupportedPatchTypeError(patchType, name)
+		}
 		patchedBytes, err = strategicpatch.StrategicMergePatch(originalJSON, patchBytes, objInterface)
 		if err != nil {
 			return fmt.Errorf("failed to apply strategic merge patch for object %q: %w", name, err)
@@ -149,9 +154,16 @@ func PatchObject(objPtr runtime.Object, name cache.ObjectName, patchType types.P
 		if err != nil {
 			return fmt.Errorf("failed to apply merge-patch for object %q: %w", name, err)
 		}
+	case types.JSONPatchType:
+		patchedBytes, err = applyJSONPatch(originalJSON, patchBytes, name)
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported patch type %q for object %q", patchType, name)
 	}
+	// reset the object since unmarshalling retains fields that are absent in the patched JSON, such as removed finalizers.
+	objValuePtr.Elem().Set(reflect.Zero(objValuePtr.Elem().Type()))
 	err = kjson.Unmarshal(patchedBytes, objInterface)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal patched JSON back into obj %q: %w", name, err)
@@ -159,7 +171,14 @@ func PatchObject(objPtr runtime.Object, name cache.ObjectName, patchType types.P
 	return nil
 }
 
-func PatchObjectStatus(objPtr runtime.Object, objName cache.ObjectName, patch []byte) error {
+// PatchObjectStatus patches the Status field of the given runtime object with the given patch using the given patch type.
+// A strategic merge patch must wrap the status patch in a top-level `status` field. A merge or JSON patch is applied to
+// the whole object, but only the resulting Status is retained - changes to other fields are ignored like in the
+// kube-apiserver. The `status` of unstructured objects can only be patched using a merge or JSON patch.
+func PatchObjectStatus(objPtr runtime.Object, objName cache.ObjectName, patchType types.PatchType, patch []byte) error {
+	if u, ok := objPtr.(*unstructured.Unstructured); ok {
+		return patchUnstructuredStatus(u, objName, patchType, patch)
+	}
 	objValuePtr := reflect.ValueOf(objPtr)
 	if objValuePtr.Kind() != reflect.Ptr || objValuePtr.IsNil() {
 		return fmt.Errorf("object %q must be a non-nil pointer", objName)
@@ -168,7 +187,17 @@ func PatchObjectStatus(objPtr runtime.Object, objName cache.ObjectName, patch []
 	if !statusField.IsValid() {
 		return fmt.Errorf("object %q of type %T has no Status field", objName, objPtr)
 	}
+	switch patchType {
+	case types.StrategicMergePatchType:
+		return strategicMergePatchStatus(statusField, objName, patch)
+	case types.MergePatchType, types.JSONPatchType:
+		return wholeObjectPatchStatus(objValuePtr, statusField, objName, patchType, patch)
+	default:
+		return fmt.Errorf("unsupported patch type %q for status of object %q", patchType, objName)
+	}
+}
 
+func strategicMergePatchStatus(statusField reflect.Value, objName cache.ObjectName, patch []byte) error {
 	var patchWrapper map[string]json.RawMessage
 	err := json.Unmarshal(patch, &patchWrapper)
 	if err != nil {
@@ -198,6 +227,90 @@ func PatchObjectStatus(objPtr runtime.Object, objName cache.ObjectName, patch []
 	return nil
 }
 
+func wholeObjectPatchStatus(objValuePtr reflect.Value, statusField reflect.Value, objName cache.ObjectName, patchType types.PatchType, patch []byte) error {
+	originalJSON, err := kjson.Marshal(objValuePtr.Interface())
+	if err != nil {
+		return fmt.Errorf("failed to marshal object %q: %w", objName, err)
+	}
+	patchedJSON, err := applyMergeOrJSONPatch(originalJSON, patchType, patch, objName)
+	if err != nil {
+		return err
+	}
+	patchedObjVal := reflect.New(objValuePtr.Elem().Type())
+	if err = kjson.Unmarshal(patchedJSON, patchedObjVal.Interface()); err != nil {
+		return fmt.Errorf("failed to unmarshal patched status for object %q: %w", objName, err)
+	}
+	statusField.Set(patchedObjVal.Elem().FieldByName("Status"))
+	return nil
+}
+
+func patchUnstructuredStatus(u *unstructured.Unstructured, objName cache.ObjectName, patchType types.PatchType, patch []byte) error {
+	if patchType != types.MergePatchType && patchType != types.JSONPatchType {
+		return newUnsupportedPatchTypeError(patchType, objName)
+	}
+	originalJSON, err := u.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal object %q: %w", objName, err)
+	}
+	patchedJSON, err := applyMergeOrJSONPatch(originalJSON, patchType, patch, objName)
+	if err != nil {
+		return err
+	}
+	var patched map[string]any
+	if err = kjson.Unmarshal(patchedJSON, &patched); err != nil {
+		return fmt.Errorf("failed to unmarshal patched status for object %q: %w", objName, err)
+	}
+	if status, ok := patched["status"]; ok {
+		u.Object["status"] = status
+	} else {
+		delete(u.Object, "status")
+	}
+	return nil
+}
+
+func applyMergeOrJSONPatch(doc []byte, patchType types.PatchType, patch []byte, objName cache.ObjectName) ([]byte, error) {
+	if patchType == types.JSONPatchType {
+		return applyJSONPatch(doc, patch, objName)
+	}
+	patchedDoc, err := jsonpatch.MergePatch(doc, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply merge-patch for object %q: %w", objName, err)
+	}
+	return patchedDoc, nil
+}
+
+// newUnsupportedPatchTypeError returns a 415 UnsupportedMediaType StatusError like the kube-apiserver does for a patch
+// type not supported for an object, such as a strategic merge patch of a custom resource which has no patch strategies.
+func newUnsupportedPatchTypeError(patchType types.PatchType, objName cache.ObjectName) error {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusUnsupportedMediaType,
+		Reason:  metav1.StatusReasonUnsupportedMediaType,
+		Message: fmt.Sprintf("patch type %q is not supported for object %q, use %q or %q instead", patchType, objName, types.MergePatchType, types.JSONPatchType),
+	}}
+}
+
+// applyJSONPatch applies the given RFC 6902 JSON patch to the given JSON doc. Like the kube-apiserver, a malformed patch
+// results in a 400 BadRequest error and a patch that cannot be applied, including a failed `test` operation, results
+// in a 422 UnprocessableEntity error.
+func applyJSONPatch(doc []byte, patchBytes []byte, objName cache.ObjectName) ([]byte, error) {
+	patch, err := jsonpatch.DecodePatch(patchBytes)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid JSON patch for object %q: %v", objName, err))
+	}
+	patchedDoc, err := patch.Apply(doc)
+	if err != nil {
+		msg := fmt.Sprintf("failed to apply JSON patch for object %q: %v", objName, err)
+		return nil, &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: msg,
+		}}
+	}
+	return patchedDoc, nil
+}
+
 func SliceOfAnyToRuntimeObj(objs []any) ([]runtime.Object, error) {
 	result := make([]runtime.Object, 0, len(objs))
 	for _, item := range objs {
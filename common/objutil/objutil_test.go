@@ -5,7 +5,9 @@
 package objutil
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -13,8 +15,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -147,7 +151,13 @@ func TestPatchPodStatus(t *testing.T) {
   }
 }`
 
+	var testPodJSONPatchStatus = `[
+  {"op": "add", "path": "/status/conditions", "value": [{"type": "PodScheduled", "status": "False", "reason": "Unschedulable"}]},
+  {"op": "replace", "path": "/metadata/name", "value": "ignored"}
+]`
+
 	tests := map[string]struct {
+		patchType  types.PatchType
 		patch      string
 		key        string
 		patchErr   error
@@ -158,6 +168,30 @@ func TestPatchPodStatus(t *testing.T) {
 			patchErr: nil,
 			patch:    testPodPatchStatus,
 		},
+		"correct json patch": {
+			key:       "default/bingo",
+			patchType: types.JSONPatchType,
+			patchErr:  nil,
+			patch:     testPodJSONPatchStatus,
+		},
+		"failed json patch test": {
+			key:       "default/bingo",
+			patchType: types.JSONPatchType,
+			patchErr:  fmt.Errorf("testing value /status/phase failed"),
+			patch:     `[{"op": "test", "path": "/status/phase", "value": "Running"}]`,
+		},
+		"correct merge patch": {
+			key:       "default/bingo",
+			patchType: types.MergePatchType,
+			patchErr:  nil,
+			patch:     testPodPatchStatus,
+		},
+		"unsupported patch type": {
+			key:       "default/bingo",
+			patchType: types.ApplyYAMLPatchType,
+			patchErr:  fmt.Errorf("unsupported patch type"),
+			patch:     testPodPatchStatus,
+		},
 		"incorrect patch": {
 			key:      "default/bingo",
 			patchErr: fmt.Errorf("failed to unmarshal patched status"),
@@ -192,15 +226,22 @@ func TestPatchPodStatus(t *testing.T) {
 			obj := metav1.Object(pod)
 
 			objectName := cache.NewObjectName("default", tc.key)
+			patchType := tc.patchType
+			if patchType == "" {
+				patchType = types.StrategicMergePatchType
+			}
 			if tc.passNilObj {
-				err = PatchObjectStatus(nil, objectName, []byte(tc.patch))
+				err = PatchObjectStatus(nil, objectName, patchType, []byte(tc.patch))
 			} else {
-				err = PatchObjectStatus(obj.(runtime.Object), objectName, []byte(tc.patch))
+				err = PatchObjectStatus(obj.(runtime.Object), objectName, patchType, []byte(tc.patch))
 			}
-			if err != nil {
+			if err != nil || tc.patchErr != nil {
 				testutil.AssertError(t, err, tc.patchErr)
 				return
 			}
+			if pod.Name != testPod.Name {
+				t.Errorf("expected only status to be patched, got name %q", pod.Name)
+			}
 
 			t.Logf("Patched pod status: %#v", pod.Status.Conditions)
 			if pod.Status.Conditions == nil {
@@ -244,7 +285,7 @@ func TestPatchObjectUsingEvent(t *testing.T) {
 			patchErr:    nil,
 		},
 		"Unsupported ContentType": {
-			contentType: "application/json-patch+json",
+			contentType: "application/apply-patch+yaml",
 			patchData:   patchEventSeries,
 			patchErr:    fmt.Errorf("unsupported patch type"),
 		},
@@ -295,3 +336,158 @@ func TestPatchObjectUsingEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchObjectUsingJSONPatch(t *testing.T) {
+	testNode := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "node-a",
+			Finalizers: []string{"f1", "f2"},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "t0", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "t1", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+	}
+	tests := map[string]struct {
+		patch          string
+		wantTaintKeys  []string
+		wantFinalizers []string
+		wantStatusCode int32
+	}{
+		"remove taint by index": {
+			patch:          `[{"op": "test", "path": "/spec/taints/0/key", "value": "t0"}, {"op": "remove", "path": "/spec/taints/0"}]`,
+			wantTaintKeys:  []string{"t1"},
+			wantFinalizers: []string{"f1", "f2"},
+		},
+		"remove finalizer": {
+			patch:          `[{"op": "remove", "path": "/metadata/finalizers/1"}]`,
+			wantTaintKeys:  []string{"t0", "t1"},
+			wantFinalizers: []string{"f1"},
+		},
+		"remove all finalizers": {
+			patch:         `[{"op": "remove", "path": "/metadata/finalizers"}]`,
+			wantTaintKeys: []string{"t0", "t1"},
+		},
+		"failed test": {
+			patch:          `[{"op": "test", "path": "/spec/taints/0/key", "value": "t1"}, {"op": "remove", "path": "/spec/taints/0"}]`,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		"missing path": {
+			patch:          `[{"op": "remove", "path": "/spec/taints/5"}]`,
+			wantStatusCode: http.StatusUnprocessableEntity,
+		},
+		"malformed patch": {
+			patch:          `{"op": "remove"}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := testNode.DeepCopy()
+			err := PatchObject(node, cache.NewObjectName("", node.Name), types.JSONPatchType, []byte(tc.patch))
+			if tc.wantStatusCode != 0 {
+				var statusErr *apierrors.StatusError
+				if !errors.As(err, &statusErr) || statusErr.ErrStatus.Code != tc.wantStatusCode {
+					t.Fatalf("expected status error with code %d, got %v", tc.wantStatusCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to patch node: %v", err)
+			}
+			var gotTaintKeys []string
+			for _, taint := range node.Spec.Taints {
+				gotTaintKeys = append(gotTaintKeys, taint.Key)
+			}
+			if !reflect.DeepEqual(tc.wantTaintKeys, gotTaintKeys) {
+				t.Errorf("expected taints %v, got %v", tc.wantTaintKeys, gotTaintKeys)
+			}
+			if !reflect.DeepEqual(tc.wantFinalizers, node.Finalizers) {
+				t.Errorf("expected finalizers %v, got %v", tc.wantFinalizers, node.Finalizers)
+			}
+		})
+	}
+}
+
+func TestPatchUnstructuredObject(t *testing.T) {
+	testObj := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w", "namespace": "default"},
+		"spec":       map[string]any{"size": int64(1)},
+	}}
+	objName := cache.NewObjectName("default", "w")
+	tests := map[string]struct {
+		status         bool
+		patchType      types.PatchType
+		patch          string
+		wantSize       int64
+		wantPhase      string
+		wantStatusCode int32
+	}{
+		"merge patch": {
+			patchType: types.MergePatchType,
+			patch:     `{"spec":{"size":2}}`,
+			wantSize:  2,
+		},
+		"json patch": {
+			patchType: types.JSONPatchType,
+			patch:     `[{"op": "replace", "path": "/spec/size", "value": 3}]`,
+			wantSize:  3,
+		},
+		"strategic merge patch": {
+			patchType:      types.StrategicMergePatchType,
+			patch:          `{"spec":{"size":2}}`,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+		"status merge patch": {
+			status:    true,
+			patchType: types.MergePatchType,
+			patch:     `{"spec":{"size":2},"status":{"phase":"Ready"}}`,
+			wantSize:  1,
+			wantPhase: "Ready",
+		},
+		"status json patch": {
+			status:    true,
+			patchType: types.JSONPatchType,
+			patch:     `[{"op": "add", "path": "/status", "value": {"phase": "Ready"}}]`,
+			wantSize:  1,
+			wantPhase: "Ready",
+		},
+		"status strategic merge patch": {
+			status:         true,
+			patchType:      types.StrategicMergePatchType,
+			patch:          `{"status":{"phase":"Ready"}}`,
+			wantStatusCode: http.StatusUnsupportedMediaType,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := testObj.DeepCopy()
+			var err error
+			if tc.status {
+				err = PatchObjectStatus(u, objName, tc.patchType, []byte(tc.patch))
+			} else {
+				err = PatchObject(u, objName, tc.patchType, []byte(tc.patch))
+			}
+			if tc.wantStatusCode != 0 {
+				var statusErr *apierrors.StatusError
+				if !errors.As(err, &statusErr) || statusErr.ErrStatus.Code != tc.wantStatusCode {
+					t.Fatalf("expected status error with code %d, got %v", tc.wantStatusCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to patch object: %v", err)
+			}
+			if size, _, _ := unstructured.NestedInt64(u.Object, "spec", "size"); size != tc.wantSize {
+				t.Errorf("expected spec.size %d, got %d", tc.wantSize, size)
+			}
+			if phase, _, _ := unstructured.NestedString(u.Object, "status", "phase"); phase != tc.wantPhase {
+				t.Errorf("expected status.phase %q, got %q", tc.wantPhase, phase)
+			}
+		})
+	}
+}
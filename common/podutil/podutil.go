@@ -54,6 +54,12 @@ func GetPodCondition(status *corev1.PodStatus, conditionType corev1.PodCondition
 	return -1, nil
 }
 
+// IsPodReady returns whether the Ready condition of the given pod is true.
+func IsPodReady(pod *corev1.Pod) bool {
+	_, condition := GetPodCondition(&pod.Status, corev1.PodReady)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
 // AsPod converts a svcapi.PodInfo to a corev1.Pod object.
 func AsPod(info svcapi.PodInfo) *corev1.Pod {
 	return &corev1.Pod{
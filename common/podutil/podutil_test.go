@@ -143,3 +143,31 @@ func TestUpdatePodCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPodReady(t *testing.T) {
+	tests := map[string]struct {
+		conditions []corev1.PodCondition
+		want       bool
+	}{
+		"ready": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			want:       true,
+		},
+		"not ready": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			want:       false,
+		},
+		"without ready condition": {
+			conditions: testPodStatus.Conditions,
+			want:       false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			if got := IsPodReady(pod); got != tc.want {
+				t.Errorf("expected ready %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -49,9 +49,27 @@ func setupFlagsToOpts() (*pflag.FlagSet, *MainOpts) {
 		mainOpts.Port = commonconstants.DefaultMinKAPIPort
 	}
 	commoncli.MapServerConfigFlags(flagSet, &mainOpts.ServerConfig)
-	flagSet.IntVarP(&mainOpts.WatchConfig.QueueSize, "watch-queue-size", "s", minkapi.DefaultWatchQueueSize, "max number of events to queue per watcher")
+	flagSet.IntVarP(&mainOpts.WatchConfig.QueueSize, "watch-queue-size", "s", minkapi.DefaultWatchQueueSize, "max number of events to queue per watcher before it is terminated for lagging behind")
 	flagSet.DurationVarP(&mainOpts.WatchConfig.Timeout, "watch-timeout", "t", minkapi.DefaultWatchTimeout, "watch timeout after which connection is closed and watch removed")
+	flagSet.IntVar(&mainOpts.WatchConfig.HistorySize, "watch-history-size", minkapi.DefaultWatchHistorySize, "max number of most recent changes retained per resource for watches from a past resource version")
+	flagSet.DurationVar(&mainOpts.WatchConfig.BookmarkInterval, "watch-bookmark-interval", minkapi.DefaultWatchBookmarkInterval, "interval at which bookmark events are sent to watchers that allow bookmarks")
+	flagSet.IntVar(&mainOpts.EventConfig.MaxCount, "event-max-count", minkapi.DefaultEventMaxCount, "max number of distinct events retained per view, evicting the least recently recorded ones")
+	flagSet.DurationVar(&mainOpts.EventConfig.TTL, "event-ttl", minkapi.DefaultEventTTL, "duration for which an event is retained after it was last recorded")
 	flagSet.StringVarP(&mainOpts.BasePrefix, "base-prefix", "b", minkapi.DefaultBasePrefix, "base path prefix for the base view of the minkapi service")
+	flagSet.StringVar(&mainOpts.LoadSnapshotPath, "load-snapshot", "", "path of a view archive to load into the base view on startup")
+	flagSet.StringVar(&mainOpts.SaveOnExitPath, "save-on-exit", "", "path to which the base view is saved as a view archive on shutdown")
+	flagSet.StringVar(&mainOpts.AuditLogPath, "audit-log", "", "path of a file to which the mutations made through all views are appended as JSON lines")
+	flagSet.StringVar(&mainOpts.ReplayAuditLogPath, "replay-audit-log", "", "path of an audit log whose mutations are replayed into the base view on startup, after --load-snapshot")
+	flagSet.StringSliceVar(&mainOpts.ReplayAuditLogViews, "replay-audit-log-views", nil, "names of the views whose entries in --replay-audit-log are replayed - defaults to all views")
+	flagSet.BoolVar(&mainOpts.SecurityConfig.TLSEnabled, "tls", false, "serve HTTPS with a server certificate issued by a CA loaded from or generated into --cert-dir")
+	flagSet.StringVar(&mainOpts.SecurityConfig.CertDir, "cert-dir", "", "private directory of the CA, server and client certificates and keys - defaults to a minkapi directory in the user cache dir")
+	flagSet.StringVar((*string)(&mainOpts.SecurityConfig.AuthMode), "auth-mode", string(minkapi.AuthModeNone), fmt.Sprintf("client authentication mode, one of %q, %q or %q", minkapi.AuthModeNone, minkapi.AuthModeToken, minkapi.AuthModeClientCert))
+	flagSet.StringVar(&mainOpts.SecurityConfig.TokenFile, "token-file", "", "file holding the bearer token for --auth-mode=token, generated if missing - defaults to a file named token in --cert-dir")
+	flagSet.StringSliceVar(&mainOpts.IndexedLabelKeys, "indexed-label-keys", nil, "label keys by whose value the objects of all kinds are indexed to speed up lists with label selectors")
+	flagSet.StringVar(&mainOpts.MirrorConfig.SourceKubeConfigPath, "mirror-kubeconfig", "", "kubeconfig of a source cluster whose objects are continuously mirrored into the base view")
+	flagSet.StringSliceVar(&mainOpts.MirrorConfig.Kinds, "mirror-kinds", nil, "resource names or kinds mirrored from --mirror-kubeconfig - defaults to all built-in kinds")
+	flagSet.StringSliceVar(&mainOpts.MirrorConfig.Namespaces, "mirror-namespaces", nil, "namespaces whose objects are mirrored from --mirror-kubeconfig - defaults to all namespaces")
+	flagSet.DurationVar(&mainOpts.MirrorConfig.ResyncPeriod, "mirror-resync-period", 0, "interval at which the objects mirrored from --mirror-kubeconfig are resynced - disabled if zero")
 
 	klogFlagSet := flag.NewFlagSet("klog", flag.ContinueOnError)
 	klog.InitFlags(klogFlagSet)
@@ -68,5 +86,17 @@ func validateMainOpts(opts *MainOpts) error {
 	if len(strings.TrimSpace(opts.KubeConfigPath)) == 0 {
 		errs = append(errs, fmt.Errorf("%w: --kubeconfig/-k", minkapi.ErrMissingOpt))
 	}
+	switch opts.SecurityConfig.AuthMode {
+	case minkapi.AuthModeNone, minkapi.AuthModeToken:
+	case minkapi.AuthModeClientCert:
+		if !opts.SecurityConfig.TLSEnabled {
+			errs = append(errs, fmt.Errorf("%w: --auth-mode=%s requires --tls", commoncli.ErrInvalidOpt, minkapi.AuthModeClientCert))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%w: --auth-mode must be one of %q, %q or %q", commoncli.ErrInvalidOpt, minkapi.AuthModeNone, minkapi.AuthModeToken, minkapi.AuthModeClientCert))
+	}
+	if !opts.MirrorConfig.Enabled() && (len(opts.MirrorConfig.Kinds) > 0 || len(opts.MirrorConfig.Namespaces) > 0) {
+		errs = append(errs, fmt.Errorf("%w: --mirror-kinds and --mirror-namespaces require --mirror-kubeconfig", commoncli.ErrInvalidOpt))
+	}
 	return errors.Join(errs...)
 }
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/printers"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// protobufWatchContentType is the content type of a watch stream of length-delimited protobuf watch events.
+const protobufWatchContentType = runtime.ContentTypeProtobuf + ";stream=watch"
+
+var (
+	codecs = serializer.NewCodecFactory(typeinfo.SupportedScheme)
+	// protobufSerializer encodes objects in the protobuf wire format of the kube-apiserver, where the object is wrapped
+	// in a runtime.Unknown envelope carrying its apiVersion and kind.
+	protobufSerializer = protobuf.NewSerializer(typeinfo.SupportedScheme, typeinfo.SupportedScheme)
+	// protobufRawSerializer encodes the metav1.WatchEvent frames of a protobuf watch stream, which have no envelope.
+	protobufRawSerializer = protobuf.NewRawSerializer(typeinfo.SupportedScheme, typeinfo.SupportedScheme)
+)
+
+// acceptsProtobuf returns whether the Accept header of the given request prefers protobuf over JSON. Media ranges are
+// considered in order, so `application/vnd.kubernetes.protobuf, */*` as sent by client-go selects protobuf.
+func acceptsProtobuf(r *http.Request) bool {
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+		switch strings.TrimSpace(mediaType) {
+		case runtime.ContentTypeProtobuf:
+			return true
+		case runtime.ContentTypeJSON, "application/*", "*/*":
+			return false
+		}
+	}
+	return false
+}
+
+// acceptsTable returns whether the Accept header of the given request prefers a metav1.Table, as sent by kubectl with
+// `application/json;as=Table;v=v1;g=meta.k8s.io,application/json`. Media ranges are considered in order, and ranges
+// asking for other versions of the Table are skipped since only meta.k8s.io/v1 is served.
+func acceptsTable(r *http.Request) bool {
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case runtime.ContentTypeJSON, runtime.ContentTypeProtobuf, "application/*", "*/*":
+		default:
+			continue
+		}
+		if params["as"] == "" {
+			return false
+		}
+		if params["as"] == "Table" && params["g"] == metav1.GroupName && params["v"] == metav1.SchemeGroupVersion.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtobufContentType returns whether the given Content-Type header denotes protobuf.
+func isProtobufContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == runtime.ContentTypeProtobuf
+}
+
+// encodeProtobuf encodes the given obj in protobuf. Only typed objects of kinds registered in the
+// typeinfo.SupportedScheme can be encoded, so ok is false for other objects such as custom resources.
+func encodeProtobuf(obj runtime.Object) (data []byte, ok bool, err error) {
+	if _, isUnstructured := obj.(runtime.Unstructured); isUnstructured {
+		return
+	}
+	encoder := runtime.Encoder(protobufSerializer)
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		// objects such as metav1.Status are typically built without their TypeMeta, which the envelope requires.
+		gvks, _, err := typeinfo.SupportedScheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, false, nil
+		}
+		encoder = codecs.EncoderForVersion(protobufSerializer, gvks[0].GroupVersion())
+	}
+	data, err = runtime.Encode(encoder, obj)
+	if protobuf.IsNotMarshalable(err) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+// encodeResponse encodes the given obj in protobuf if the given request accepts it and obj supports it, otherwise in JSON.
+func encodeResponse(r *http.Request, obj any) (contentType string, data []byte, err error) {
+	if runtimeObj, ok := obj.(runtime.Object); ok && acceptsProtobuf(r) {
+		data, ok, err = encodeProtobuf(runtimeObj)
+		if ok || err != nil {
+			return runtime.ContentTypeProtobuf, data, err
+		}
+	}
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(obj)
+	return runtime.ContentTypeJSON, buf.Bytes(), err
+}
+
+// decodeBody decodes the given request body data of the given content type into the given obj. Protobuf bodies can only
+// be decoded into typed objects.
+func decodeBody(contentType string, data []byte, obj any) error {
+	if !isProtobufContentType(contentType) {
+		return json.Unmarshal(data, obj)
+	}
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("cannot decode protobuf into %T", obj)
+	}
+	_, gvk, err := protobufSerializer.Decode(data, nil, runtimeObj)
+	if err != nil {
+		return err
+	}
+	// unlike JSON, the protobuf encoding of typed objects carries the apiVersion and kind only in its envelope.
+	runtimeObj.GetObjectKind().SetGroupVersionKind(*gvk)
+	return nil
+}
+
+// watchEventEncoder writes a single watch event to a watch stream.
+type watchEventEncoder func(w io.Writer, ev *watch.Event) error
+
+// newWatchEventEncoder returns the content type of the watch stream and the encoder of its events. A stream of
+// length-delimited protobuf frames is used if the given request accepts protobuf and the objects of the given descriptor
+// support it, otherwise a stream of newline-delimited JSON events whose objects are converted into metav1.Table objects
+// if the request accepts them. A BadRequest StatusError is returned for an unsupported includeObject query parameter.
+func newWatchEventEncoder(log logr.Logger, r *http.Request, d typeinfo.Descriptor) (contentType string, encoder watchEventEncoder, err error) {
+	if acceptsTable(r) {
+		includeObject, err := printers.ParseIncludeObject(r.URL.Query().Get("includeObject"))
+		if err != nil {
+			return "", nil, err
+		}
+		return runtime.ContentTypeJSON, newTableWatchEventEncoder(log, d, includeObject), nil
+	}
+	if acceptsProtobuf(r) && typeinfo.SupportedScheme.Recognizes(d.GVK) {
+		return protobufWatchContentType, encodeProtobufWatchEvent, nil
+	}
+	return runtime.ContentTypeJSON, func(w io.Writer, ev *watch.Event) error {
+		return encodeJSONWatchEvent(log, w, ev)
+	}, nil
+}
+
+func encodeJSONWatchEvent(log logr.Logger, w io.Writer, ev *watch.Event) error {
+	eventJson, err := buildWatchEventJsonAlt(log, ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, eventJson)
+	return err
+}
+
+// newTableWatchEventEncoder returns an encoder of JSON watch events whose objects are converted into a metav1.Table with
+// a single row. Like the kube-apiserver, only the first event carries the column definitions, and bookmark objects are
+// converted into a Table without rows. Error events are sent as they are.
+func newTableWatchEventEncoder(log logr.Logger, d typeinfo.Descriptor, includeObject metav1.IncludeObjectPolicy) watchEventEncoder {
+	headersSent := false
+	return func(w io.Writer, ev *watch.Event) error {
+		if ev.Type == watch.Error {
+			return encodeJSONWatchEvent(log, w, ev)
+		}
+		var (
+			table *metav1.Table
+			err   error
+		)
+		if ev.Type == watch.Bookmark {
+			mo, err := meta.Accessor(ev.Object)
+			if err != nil {
+				return err
+			}
+			table = &metav1.Table{
+				TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: metav1.SchemeGroupVersion.String()},
+				ListMeta: metav1.ListMeta{ResourceVersion: mo.GetResourceVersion()},
+			}
+		} else if table, err = printers.ConvertToTable(d, ev.Object, printers.Options{NoHeaders: headersSent, IncludeObject: includeObject}); err != nil {
+			return err
+		}
+		headersSent = headersSent || len(table.ColumnDefinitions) > 0
+		return encodeJSONWatchEvent(log, w, &watch.Event{Type: ev.Type, Object: table})
+	}
+}
+
+// encodeProtobufWatchEvent writes the given event as a length-delimited metav1.WatchEvent frame whose object is
+// protobuf-encoded, which is the watch stream format of the kube-apiserver for protobuf clients.
+func encodeProtobufWatchEvent(w io.Writer, ev *watch.Event) error {
+	objData, ok, err := encodeProtobuf(ev.Object)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("cannot encode object of type %T in protobuf", ev.Object)
+	}
+	var buf bytes.Buffer
+	if err = protobufRawSerializer.Encode(&metav1.WatchEvent{
+		Type:   string(ev.Type),
+		Object: runtime.RawExtension{Raw: objData},
+	}, &buf); err != nil {
+		return err
+	}
+	_, err = protobuf.LengthDelimitedFramer.NewFrameWriter(w).Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestEncodeResponse(t *testing.T) {
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1")
+	widget.SetKind("Widget")
+	widget.SetName("w-a")
+	tests := map[string]struct {
+		accept          string
+		obj             any
+		wantContentType string
+	}{
+		"no accept header": {
+			obj:             newBenchmarkPod(0),
+			wantContentType: runtime.ContentTypeJSON,
+		},
+		"json": {
+			accept:          runtime.ContentTypeJSON,
+			obj:             newBenchmarkPod(0),
+			wantContentType: runtime.ContentTypeJSON,
+		},
+		"protobuf preferred": {
+			accept:          runtime.ContentTypeProtobuf + ", */*",
+			obj:             newBenchmarkPod(0),
+			wantContentType: runtime.ContentTypeProtobuf,
+		},
+		"json preferred": {
+			accept:          "application/json;q=0.9, " + runtime.ContentTypeProtobuf,
+			obj:             newBenchmarkPod(0),
+			wantContentType: runtime.ContentTypeJSON,
+		},
+		"protobuf status without type meta": {
+			accept:          runtime.ContentTypeProtobuf,
+			obj:             &metav1.Status{Status: metav1.StatusSuccess},
+			wantContentType: runtime.ContentTypeProtobuf,
+		},
+		"protobuf falls back for custom resource": {
+			accept:          runtime.ContentTypeProtobuf + ", " + runtime.ContentTypeJSON,
+			obj:             widget,
+			wantContentType: runtime.ContentTypeJSON,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/pods", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			contentType, data, err := encodeResponse(r, tc.obj)
+			if err != nil {
+				t.Fatalf("failed to encode response: %v", err)
+			}
+			if contentType != tc.wantContentType {
+				t.Errorf("expected content type %q, got %q", tc.wantContentType, contentType)
+			}
+			if contentType == runtime.ContentTypeProtobuf && !bytes.HasPrefix(data, []byte("k8s\x00")) {
+				t.Errorf("expected protobuf envelope, got %q", data)
+			}
+		})
+	}
+}
+
+func TestDecodeProtobufBody(t *testing.T) {
+	pod := newBenchmarkPod(0)
+	data, ok, err := encodeProtobuf(pod)
+	if err != nil || !ok {
+		t.Fatalf("failed to encode pod in protobuf: ok=%t, err=%v", ok, err)
+	}
+	var got corev1.Pod
+	if err = decodeBody(runtime.ContentTypeProtobuf, data, &got); err != nil {
+		t.Fatalf("failed to decode protobuf body: %v", err)
+	}
+	if got.Name != pod.Name || got.Kind != "Pod" || got.APIVersion != "v1" {
+		t.Errorf("expected pod %q with kind Pod and apiVersion v1, got %q with kind %q and apiVersion %q", pod.Name, got.Name, got.Kind, got.APIVersion)
+	}
+}
+
+func TestProtobufWatchEvent(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/pods?watch=true", nil)
+	r.Header.Set("Accept", runtime.ContentTypeProtobuf)
+	contentType, encodeEvent, err := newWatchEventEncoder(logr.Discard(), r, typeinfo.PodsDescriptor)
+	if err != nil {
+		t.Fatalf("failed to create watch event encoder: %v", err)
+	}
+	if contentType != protobufWatchContentType {
+		t.Fatalf("expected content type %q, got %q", protobufWatchContentType, contentType)
+	}
+	var buf bytes.Buffer
+	pod := newBenchmarkPod(0)
+	if err = encodeEvent(&buf, &watch.Event{Type: watch.Added, Object: pod}); err != nil {
+		t.Fatalf("failed to encode watch event: %v", err)
+	}
+	frame := make([]byte, buf.Len())
+	n, err := protobuf.LengthDelimitedFramer.NewFrameReader(io.NopCloser(&buf)).Read(frame)
+	if err != nil {
+		t.Fatalf("failed to read watch event frame: %v", err)
+	}
+	var ev metav1.WatchEvent
+	if _, _, err = protobufRawSerializer.Decode(frame[:n], nil, &ev); err != nil {
+		t.Fatalf("failed to decode watch event: %v", err)
+	}
+	var got corev1.Pod
+	if err = decodeBody(runtime.ContentTypeProtobuf, ev.Object.Raw, &got); err != nil {
+		t.Fatalf("failed to decode watch event object: %v", err)
+	}
+	if ev.Type != string(watch.Added) || got.Name != pod.Name {
+		t.Errorf("expected %s event for pod %q, got %s event for pod %q", watch.Added, pod.Name, ev.Type, got.Name)
+	}
+}
+
+func TestAcceptsTable(t *testing.T) {
+	tests := map[string]struct {
+		accept string
+		want   bool
+	}{
+		"no accept header": {},
+		"json":             {accept: runtime.ContentTypeJSON},
+		"kubectl": {
+			accept: "application/json;as=Table;v=v1;g=meta.k8s.io,application/json;as=Table;v=v1beta1;g=meta.k8s.io,application/json",
+			want:   true,
+		},
+		"unsupported table version only": {accept: "application/json;as=Table;v=v1beta1;g=meta.k8s.io,application/json"},
+		"json preferred":                 {accept: "application/json, application/json;as=Table;v=v1;g=meta.k8s.io"},
+		"partial object metadata":        {accept: "application/json;as=PartialObjectMetadataList;v=v1;g=meta.k8s.io"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/pods", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			if got := acceptsTable(r); got != tc.want {
+				t.Errorf("acceptsTable() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTableWatchEvent(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/pods?watch=true&includeObject=None", nil)
+	r.Header.Set("Accept", "application/json;as=Table;v=v1;g=meta.k8s.io")
+	contentType, encodeEvent, err := newWatchEventEncoder(logr.Discard(), r, typeinfo.PodsDescriptor)
+	if err != nil {
+		t.Fatalf("failed to create watch event encoder: %v", err)
+	}
+	if contentType != runtime.ContentTypeJSON {
+		t.Fatalf("expected content type %q, got %q", runtime.ContentTypeJSON, contentType)
+	}
+	var buf bytes.Buffer
+	for i := range 2 {
+		if err = encodeEvent(&buf, &watch.Event{Type: watch.Added, Object: newBenchmarkPod(i)}); err != nil {
+			t.Fatalf("failed to encode watch event: %v", err)
+		}
+	}
+	dec := json.NewDecoder(&buf)
+	for i := range 2 {
+		var ev struct {
+			Type   string       `json:"type"`
+			Object metav1.Table `json:"object"`
+		}
+		if err = dec.Decode(&ev); err != nil {
+			t.Fatalf("failed to decode watch event %d: %v", i, err)
+		}
+		if ev.Object.Kind != "Table" || len(ev.Object.Rows) != 1 || ev.Object.Rows[0].Cells[0] != newBenchmarkPod(i).Name {
+			t.Errorf("expected table with a row for pod %q, got %+v", newBenchmarkPod(i).Name, ev.Object)
+		}
+		if wantHeaders := i == 0; wantHeaders != (len(ev.Object.ColumnDefinitions) > 0) {
+			t.Errorf("expected column definitions only in the first event, got %d in event %d", len(ev.Object.ColumnDefinitions), i)
+		}
+	}
+
+	r = httptest.NewRequest("GET", "/api/v1/pods?watch=true&includeObject=All", nil)
+	r.Header.Set("Accept", "application/json;as=Table;v=v1;g=meta.k8s.io")
+	if _, _, err = newWatchEventEncoder(logr.Discard(), r, typeinfo.PodsDescriptor); !apierrors.IsBadRequest(err) {
+		t.Errorf("expected bad request error for unsupported includeObject, got %v", err)
+	}
+}
+
+func BenchmarkEncodePodList(b *testing.B) {
+	list := newBenchmarkPodList(100)
+	b.Run("json", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := json.Marshal(list); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := encodeProtobuf(list); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodePodList(b *testing.B) {
+	list := newBenchmarkPodList(100)
+	jsonData, err := json.Marshal(list)
+	if err != nil {
+		b.Fatal(err)
+	}
+	protobufData, _, err := encodeProtobuf(list)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("json", func(b *testing.B) {
+		for b.Loop() {
+			if err := decodeBody(runtime.ContentTypeJSON, jsonData, &corev1.PodList{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		for b.Loop() {
+			if err := decodeBody(runtime.ContentTypeProtobuf, protobufData, &corev1.PodList{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func newBenchmarkPodList(numPods int) *corev1.PodList {
+	list := &corev1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}}
+	for i := range numPods {
+		list.Items = append(list.Items, *newBenchmarkPod(i))
+	}
+	return list
+}
+
+func newBenchmarkPod(i int) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("pod-%d", i),
+			Namespace:       "default",
+			ResourceVersion: fmt.Sprintf("%d", i+1),
+			Labels:          map[string]string{"app": "benchmark", "tier": "backend"},
+		},
+		Spec: corev1.PodSpec{
+			SchedulerName: corev1.DefaultSchedulerName,
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "registry.example.com/app:v1",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+			}},
+			Tolerations: []corev1.Toleration{{
+				Key:      "node.kubernetes.io/not-ready",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoExecute,
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeleteCollection(t *testing.T) {
+	tests := map[string]struct {
+		target        string
+		wantCode      int
+		wantDeleted   []string
+		wantRemaining []string
+		wantPending   bool
+	}{
+		"namespaced pods by label": {
+			target:        "/sb/api/v1/namespaces/default/pods?labelSelector=app%3Dweb",
+			wantCode:      http.StatusOK,
+			wantDeleted:   []string{"web-0", "web-1"},
+			wantRemaining: []string{"db-0", "web-0"},
+		},
+		"pods of all namespaces by field": {
+			target:        "/sb/api/v1/pods?fieldSelector=metadata.name%3Dweb-0",
+			wantCode:      http.StatusOK,
+			wantDeleted:   []string{"web-0", "web-0"},
+			wantRemaining: []string{"db-0", "web-1"},
+		},
+		"all namespaced pods": {
+			target:        "/sb/api/v1/namespaces/other/pods",
+			wantCode:      http.StatusOK,
+			wantDeleted:   []string{"web-0"},
+			wantRemaining: []string{"db-0", "web-0", "web-1"},
+		},
+		"pods with grace period": {
+			target:        "/sb/api/v1/namespaces/default/pods?labelSelector=app%3Dweb&gracePeriodSeconds=30",
+			wantCode:      http.StatusOK,
+			wantDeleted:   []string{"web-0", "web-1"},
+			wantRemaining: []string{"db-0", "web-0", "web-0", "web-1"},
+			wantPending:   true,
+		},
+		"invalid label selector": {
+			target:        "/sb/api/v1/namespaces/default/pods?labelSelector=app%3D%3D%3D",
+			wantCode:      http.StatusBadRequest,
+			wantRemaining: []string{"db-0", "web-0", "web-0", "web-1"},
+		},
+		"unsupported field selector": {
+			target:        "/sb/api/v1/namespaces/default/pods?fieldSelector=spec.priority%3D1",
+			wantCode:      http.StatusBadRequest,
+			wantRemaining: []string{"db-0", "web-0", "web-0", "web-1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, v := newSubresourceTestServer(t)
+			for _, pod := range []*corev1.Pod{
+				newCollectionTestPod("default", "web-0", "web"),
+				newCollectionTestPod("default", "web-1", "web"),
+				newCollectionTestPod("default", "db-0", "db"),
+				newCollectionTestPod("other", "web-0", "web"),
+			} {
+				if err := v.CreateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+					t.Fatalf("failed to create pod: %v", err)
+				}
+			}
+
+			w := serveSubresourceRequest(k, http.MethodDelete, tc.target, "", "")
+			if w.Code != tc.wantCode {
+				t.Fatalf("expected status code %d, got %d: %s", tc.wantCode, w.Code, w.Body.String())
+			}
+			if tc.wantCode == http.StatusOK {
+				var podList corev1.PodList
+				if err := json.Unmarshal(w.Body.Bytes(), &podList); err != nil {
+					t.Fatalf("failed to decode pod list: %v", err)
+				}
+				if podList.Kind != "PodList" {
+					t.Errorf("expected kind PodList, got %q", podList.Kind)
+				}
+				var deleted []string
+				for _, p := range podList.Items {
+					deleted = append(deleted, p.Name)
+					if pending := p.DeletionTimestamp != nil; pending != tc.wantPending {
+						t.Errorf("expected pod %q to be pending deletion %t, got %t", p.Name, tc.wantPending, pending)
+					}
+				}
+				slices.Sort(deleted)
+				if !slices.Equal(deleted, tc.wantDeleted) {
+					t.Errorf("expected deleted pods %v, got %v", tc.wantDeleted, deleted)
+				}
+			}
+			pods, _, err := v.ListMetaObjects(typeinfo.PodsDescriptor.GVK, mkapi.MatchCriteria{})
+			if err != nil {
+				t.Fatalf("failed to list pods: %v", err)
+			}
+			var remaining []string
+			for _, p := range pods {
+				remaining = append(remaining, p.GetName())
+			}
+			slices.Sort(remaining)
+			if !slices.Equal(remaining, tc.wantRemaining) {
+				t.Errorf("expected remaining pods %v, got %v", tc.wantRemaining, remaining)
+			}
+		})
+	}
+}
+
+func TestDeleteCollectionOfClusterScopedResource(t *testing.T) {
+	k, v := newSubresourceTestServer(t)
+	for _, name := range []string{"node-a", "node-b"} {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": name}}}
+		if err := v.CreateObject(typeinfo.NodesDescriptor.GVK, node); err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+	}
+	w := serveSubresourceRequest(k, http.MethodDelete, "/sb/api/v1/nodes?labelSelector=pool%3Dnode-a", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	nodes, err := v.ListNodes()
+	if err != nil {
+		t.Fatalf("failed to list nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Name != "node-b" {
+		t.Errorf("expected only node-b to remain, got %v", nodes)
+	}
+}
+
+func newCollectionTestPod(namespace, name, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx"}}},
+	}
+}
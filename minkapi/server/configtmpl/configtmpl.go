@@ -71,6 +71,13 @@ type KubeConfigParams struct {
 	Name           string
 	KubeConfigPath string
 	URL            string
+	// CertificateAuthorityData is the base64 encoded PEM of the CA of the server. TLS verification is skipped if empty.
+	CertificateAuthorityData string
+	// ClientCertificateData and ClientKeyData are the base64 encoded PEMs of the optional client certificate and key.
+	ClientCertificateData string
+	ClientKeyData         string
+	// Token is the optional bearer token.
+	Token string
 }
 
 func GenKubeConfig(params KubeConfigParams) error {
@@ -81,9 +88,17 @@ func GenKubeConfig(params KubeConfigParams) error {
 	var buf bytes.Buffer
 	err = kubeConfigTemplate.Execute(&buf, params)
 	if err != nil {
-		return fmt.Errorf("%w: cannot render %q template with params %q: %w", mkapi.ErrExecuteConfigTemplate, kubeConfigTemplate.Name(), params, err)
+		return fmt.Errorf("%w: cannot render %q template for %q: %w", mkapi.ErrExecuteConfigTemplate, kubeConfigTemplate.Name(), params.Name, err)
+	}
+	perm := os.FileMode(0644)
+	if params.Token != "" || params.ClientKeyData != "" {
+		perm = 0600 // kubeconfig embeds credentials
+	}
+	err = os.WriteFile(params.KubeConfigPath, buf.Bytes(), perm)
+	if err == nil {
+		// WriteFile does not change the permissions of an existing file.
+		err = os.Chmod(params.KubeConfigPath, perm)
 	}
-	err = os.WriteFile(params.KubeConfigPath, buf.Bytes(), 0644)
 	if err != nil {
 		return fmt.Errorf("%w: cannot write kubeconfig to %q: %w", mkapi.ErrExecuteConfigTemplate, params.KubeConfigPath, err)
 	}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// registerEventRoutes registers the routes of the events of the given descriptor. Like the events recorded in-process,
+// the events written through these routes are recorded in the EventSink of the given view, which aggregates repeated
+// events and bounds the number and age of the retained events. Since the sink persists the events in the event stores
+// of the view, these are read and watched like other objects. Writes to events that are not recorded in the sink, like
+// the events of the delegate of a sandbox view, are made to the view directly.
+//
+// As the writes to the sink cannot be replayed without the aggregation state of the sink, they are not audited.
+func registerEventRoutes(viewMux *http.ServeMux, d typeinfo.Descriptor, view mkapi.View) {
+	for _, collection := range collectionPaths(d) {
+		item := collection + "/{name}"
+		viewMux.HandleFunc("POST "+collection, handleCreateEvent(d, view))
+		viewMux.HandleFunc("GET "+collection, withExpiredEventsRemoved(view, handleListOrWatch(d, view)))
+		viewMux.HandleFunc("DELETE "+collection, handleDeleteCollection(d, view))
+		viewMux.HandleFunc("GET "+item, withExpiredEventsRemoved(view, handleGet(d, view)))
+		viewMux.HandleFunc("PATCH "+item, handlePatchEvent(d, view))
+		viewMux.HandleFunc("DELETE "+item, handleDeleteEvent(d, view))
+		viewMux.HandleFunc("PUT "+item, handlePutEvent(d, view))
+	}
+}
+
+// withExpiredEventsRemoved removes the expired events of the EventSink of the given view before serving the request
+// with the given handler, so that these are no longer read.
+func withExpiredEventsRemoved(view mkapi.View, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view.GetEventSink().RemoveExpired()
+		handler(w, r)
+	}
+}
+
+func handleCreateEvent(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mo, err := d.CreateObject()
+		if err != nil {
+			handleInternalServerError(w, r, fmt.Errorf("cannot create object from objGvk %q: %v", d.GVK, err))
+			return
+		}
+		if !readBodyIntoObj(w, r, mo) {
+			return
+		}
+		if mo.GetNamespace() == "" {
+			mo.SetNamespace(GetObjectName(r, d).Namespace)
+		}
+		if mo.GetName() == "" && mo.GetGenerateName() != "" {
+			mo.SetName(typeinfo.GenerateName(mo.GetGenerateName()))
+		}
+		ev, err := recordEvent(r, view.GetEventSink(), mkapi.AuditVerbCreate, mo, nil)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		writeResponse(w, r, ev)
+	}
+}
+
+// handlePutEvent replaces an event recorded in the EventSink of the given view, or an event written to the view
+// directly using handlePut.
+func handlePutEvent(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
+	fallback := handlePut(d, view)
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, ok := bufferBody(w, r)
+		if !ok {
+			return
+		}
+		mo, err := d.CreateObject()
+		if err != nil {
+			handleInternalServerError(w, r, fmt.Errorf("cannot create object from objGvk %q: %v", d.GVK, err))
+			return
+		}
+		if !readBodyIntoObj(w, r, mo) {
+			return
+		}
+		name := GetObjectName(r, d)
+		mo.SetNamespace(name.Namespace)
+		mo.SetName(name.Name)
+		ev, err := recordEvent(r, view.GetEventSink(), mkapi.AuditVerbUpdate, mo, nil)
+		if apierrors.IsNotFound(err) {
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			fallback(w, r)
+			return
+		}
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		writeResponse(w, r, ev)
+	}
+}
+
+// handlePatchEvent applies a strategic merge patch, as sent by the event recorders of client-go, to an event recorded
+// in the EventSink of the given view. Other patches and patches of events written to the view directly are applied
+// using handlePatch.
+func handlePatchEvent(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
+	fallback := handlePatch(d, view)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != string(types.StrategicMergePatchType) {
+			fallback(w, r)
+			return
+		}
+		data, ok := bufferBody(w, r)
+		if !ok {
+			return
+		}
+		mo, err := d.CreateObject()
+		if err != nil {
+			handleInternalServerError(w, r, fmt.Errorf("cannot create object from objGvk %q: %v", d.GVK, err))
+			return
+		}
+		name := GetObjectName(r, d)
+		mo.SetNamespace(name.Namespace)
+		mo.SetName(name.Name)
+		ev, err := recordEvent(r, view.GetEventSink(), mkapi.AuditVerbPatch, mo, data)
+		if apierrors.IsNotFound(err) {
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			fallback(w, r)
+			return
+		}
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		writeResponse(w, r, ev)
+	}
+}
+
+// handleDeleteEvent deletes an event recorded in the EventSink of the given view, or an event written to the view
+// directly using handleDelete.
+func handleDeleteEvent(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
+	fallback := handleDelete(d, view)
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := GetObjectName(r, d)
+		err := view.GetEventSink().Delete(r.Context(), &eventsv1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name}})
+		if apierrors.IsNotFound(err) {
+			fallback(w, r)
+			return
+		}
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		status := metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusSuccess,
+			Details: &metav1.StatusDetails{
+				Name: name.String(),
+				Kind: d.GVR.GroupResource().Resource,
+			},
+		}
+		writeResponse(w, r, &status)
+	}
+}
+
+// recordEvent creates, updates or patches the given event in the given sink according to the given verb, through the
+// API of the event. The patchData is the strategic merge patch of a patch.
+func recordEvent(r *http.Request, sink mkapi.EventSink, verb mkapi.AuditVerb, mo metav1.Object, patchData []byte) (runtime.Object, error) {
+	switch ev := mo.(type) {
+	case *eventsv1.Event:
+		var recorded *eventsv1.Event
+		var err error
+		switch verb {
+		case mkapi.AuditVerbCreate:
+			recorded, err = sink.Create(r.Context(), ev)
+		case mkapi.AuditVerbUpdate:
+			recorded, err = sink.Update(r.Context(), ev)
+		default:
+			recorded, err = sink.Patch(r.Context(), ev, patchData)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return recorded, nil
+	case *corev1.Event:
+		var recorded *corev1.Event
+		var err error
+		switch verb {
+		case mkapi.AuditVerbCreate:
+			recorded, err = sink.CoreV1().Create(ev)
+		case mkapi.AuditVerbUpdate:
+			recorded, err = sink.CoreV1().Update(ev)
+		default:
+			recorded, err = sink.CoreV1().Patch(ev, patchData)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return recorded, nil
+	default:
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("object %q is not an event", cache.NewObjectName(mo.GetNamespace(), mo.GetName())))
+	}
+}
+
+// bufferBody reads the body of the given request and replaces it with a reader of the read data, which is returned so
+// that the body can be read again by a fallback handler.
+func bufferBody(w http.ResponseWriter, r *http.Request) (data []byte, ok bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		handleBadRequest(w, r, err)
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	return data, true
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestCoreEvents(t *testing.T) {
+	k, v := newSubresourceTestServer(t)
+	for _, podName := range []string{"pod-a", "pod-b"} {
+		event := corev1.Event{
+			TypeMeta:       metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+			ObjectMeta:     metav1.ObjectMeta{Name: podName + ".backoff", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: podName},
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+			Source:         corev1.EventSource{Component: "kubelet"},
+			Count:          1,
+			Type:           corev1.EventTypeWarning,
+		}
+		body, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := serveSubresourceRequest(k, http.MethodPost, "/sb/api/v1/namespaces/default/events", "application/json", string(body))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+
+	w := serveSubresourceRequest(k, http.MethodGet, "/sb/api/v1/namespaces/default/events?fieldSelector=involvedObject.name%3Dpod-a", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var eventList corev1.EventList
+	if err := json.Unmarshal(w.Body.Bytes(), &eventList); err != nil {
+		t.Fatalf("failed to decode event list: %v", err)
+	}
+	if eventList.Kind != "EventList" {
+		t.Errorf("expected kind EventList, got %q", eventList.Kind)
+	}
+	if len(eventList.Items) != 1 || eventList.Items[0].Name != "pod-a.backoff" {
+		t.Errorf("expected only event pod-a.backoff, got %v", eventList.Items)
+	}
+
+	events, err := v.ListEvents("default", corev1.ObjectReference{Kind: "Pod", Name: "pod-b"})
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "pod-b.backoff" || events[0].Note != "Back-off restarting failed container" {
+		t.Errorf("expected only event pod-b.backoff, got %v", events)
+	}
+}
+
+func TestEventsRecordedInEventSink(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	k := newEventsTestServer(t, mkapi.EventConfig{MaxCount: 2, TTL: time.Hour}, fakeClock)
+	const coreEvents, events = "/sb/api/v1/namespaces/default/events", "/sb/apis/events.k8s.io/v1/namespaces/default/events"
+
+	// like the event correlator of client-go, which creates repeated events under new names and patches their count.
+	for _, name := range []string{"a.1", "a.2"} {
+		postEvent(t, k, coreEvents, &corev1.Event{
+			TypeMeta:       metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "pod-a"},
+			Reason:         "BackOff",
+			Source:         corev1.EventSource{Component: "kubelet"},
+			Count:          1,
+			Type:           corev1.EventTypeWarning,
+		})
+	}
+	w := serveSubresourceRequest(k, http.MethodPatch, coreEvents+"/a.2", "application/strategic-merge-patch+json", `{"count":3}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d for patch, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var coreEventList corev1.EventList
+	getList(t, k, coreEvents, &coreEventList)
+	if len(coreEventList.Items) != 1 || coreEventList.Items[0].Name != "a.1" || coreEventList.Items[0].Count != 4 {
+		t.Errorf("expected only event a.1 aggregating 4 occurrences, got %v", coreEventList.Items)
+	}
+
+	for _, podName := range []string{"pod-b", "pod-c"} {
+		fakeClock.Step(time.Minute)
+		postEvent(t, k, events, &eventsv1.Event{
+			TypeMeta:            metav1.TypeMeta{APIVersion: "events.k8s.io/v1", Kind: "Event"},
+			ObjectMeta:          metav1.ObjectMeta{Name: podName + ".1", Namespace: "default"},
+			Regarding:           corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: podName},
+			Reason:              "FailedScheduling",
+			Type:                corev1.EventTypeWarning,
+			Action:              "Scheduling",
+			ReportingController: "default-scheduler",
+			ReportingInstance:   "default-scheduler-0",
+		})
+	}
+	getList(t, k, coreEvents, &coreEventList)
+	if len(coreEventList.Items) != 0 {
+		t.Errorf("expected event a.1 to be evicted, got %v", coreEventList.Items)
+	}
+	var eventList eventsv1.EventList
+	getList(t, k, events, &eventList)
+	if len(eventList.Items) != 2 {
+		t.Errorf("expected events pod-b.1 and pod-c.1, got %v", eventList.Items)
+	}
+
+	fakeClock.Step(time.Hour + time.Minute)
+	getList(t, k, events, &eventList)
+	if len(eventList.Items) != 0 {
+		t.Errorf("expected all events to be expired, got %v", eventList.Items)
+	}
+}
+
+// newEventsTestServer returns a server with a sandbox view "sb" retaining events according to the given cfg, with their
+// ages measured by the given clock.
+func newEventsTestServer(t *testing.T, cfg mkapi.EventConfig, clock *testingclock.FakeClock) *InMemoryKAPI {
+	t.Helper()
+	kubeConfigPath := filepath.Join(t.TempDir(), "minkapi.yaml")
+	baseView, err := view.New(logr.Discard(), &mkapi.ViewArgs{
+		Name:           mkapi.DefaultBasePrefix,
+		KubeConfigPath: kubeConfigPath,
+		Scheme:         typeinfo.SupportedScheme,
+		WatchConfig:    mkapi.WatchConfig{QueueSize: mkapi.DefaultWatchQueueSize, Timeout: mkapi.DefaultWatchTimeout},
+		EventConfig:    cfg,
+		Clock:          clock,
+	})
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() { _ = baseView.Close() })
+	serverCfg := mkapi.Config{EventConfig: cfg}
+	serverCfg.KubeConfigPath = kubeConfigPath
+	s, err := NewInMemoryUsingViews(serverCfg, baseView, view.NewSandbox)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	sandboxView, err := s.GetSandboxView(context.Background(), "sb")
+	if err != nil {
+		t.Fatalf("failed to create sandbox view: %v", err)
+	}
+	t.Cleanup(func() { _ = sandboxView.Close() })
+	return s.(*InMemoryKAPI)
+}
+
+func postEvent(t *testing.T, k *InMemoryKAPI, target string, event any) {
+	t.Helper()
+	body, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := serveSubresourceRequest(k, http.MethodPost, target, "application/json", string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d for creation, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func getList(t *testing.T, k *InMemoryKAPI, target string, list any) {
+	t.Helper()
+	w := serveSubresourceRequest(k, http.MethodGet, target, "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d for list, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+}
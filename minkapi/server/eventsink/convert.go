@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package eventsink
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FromCoreV1 converts the given core/v1 event to an events.k8s.io/v1 event like the kube-apiserver, which serves the
+// same events through both APIs.
+func FromCoreV1(event *corev1.Event) *eventsv1.Event {
+	ev := &eventsv1.Event{
+		TypeMeta:                 metav1.TypeMeta{APIVersion: eventsv1.SchemeGroupVersion.String(), Kind: "Event"},
+		ObjectMeta:               *event.ObjectMeta.DeepCopy(),
+		EventTime:                event.EventTime,
+		ReportingController:      event.ReportingController,
+		ReportingInstance:        event.ReportingInstance,
+		Action:                   event.Action,
+		Reason:                   event.Reason,
+		Regarding:                event.InvolvedObject,
+		Note:                     event.Message,
+		Type:                     event.Type,
+		DeprecatedSource:         event.Source,
+		DeprecatedFirstTimestamp: event.FirstTimestamp,
+		DeprecatedLastTimestamp:  event.LastTimestamp,
+		DeprecatedCount:          event.Count,
+	}
+	if event.Related != nil {
+		related := *event.Related
+		ev.Related = &related
+	}
+	if event.Series != nil {
+		ev.Series = &eventsv1.EventSeries{Count: event.Series.Count, LastObservedTime: event.Series.LastObservedTime}
+	}
+	return ev
+}
+
+// ToCoreV1 converts the given events.k8s.io/v1 event to a core/v1 event. It is the inverse of FromCoreV1.
+func ToCoreV1(ev *eventsv1.Event) *corev1.Event {
+	event := &corev1.Event{
+		TypeMeta:            metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Event"},
+		ObjectMeta:          *ev.ObjectMeta.DeepCopy(),
+		InvolvedObject:      ev.Regarding,
+		Reason:              ev.Reason,
+		Message:             ev.Note,
+		Source:              ev.DeprecatedSource,
+		FirstTimestamp:      ev.DeprecatedFirstTimestamp,
+		LastTimestamp:       ev.DeprecatedLastTimestamp,
+		Count:               ev.DeprecatedCount,
+		Type:                ev.Type,
+		EventTime:           ev.EventTime,
+		Action:              ev.Action,
+		ReportingController: ev.ReportingController,
+		ReportingInstance:   ev.ReportingInstance,
+	}
+	if ev.Related != nil {
+		related := *ev.Related
+		event.Related = &related
+	}
+	if ev.Series != nil {
+		event.Series = &corev1.EventSeries{Count: ev.Series.Count, LastObservedTime: ev.Series.LastObservedTime}
+	}
+	return event
+}
+
+// Count returns the number of occurrences of the given event, which is tracked by the series of events.k8s.io/v1
+// events and by the deprecated count of events converted from core/v1.
+func Count(ev *eventsv1.Event) int32 {
+	switch {
+	case ev.DeprecatedCount > 0:
+		return ev.DeprecatedCount
+	case ev.Series != nil:
+		return ev.Series.Count
+	default:
+		return 1
+	}
+}
+
+// LastObservedTime returns the time at which the given event was last observed.
+func LastObservedTime(ev *eventsv1.Event) metav1.Time {
+	switch {
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		return metav1.Time{Time: ev.Series.LastObservedTime.Time}
+	case !ev.DeprecatedLastTimestamp.IsZero():
+		return ev.DeprecatedLastTimestamp
+	case !ev.EventTime.IsZero():
+		return metav1.Time{Time: ev.EventTime.Time}
+	default:
+		return ev.CreationTimestamp
+	}
+}
+
+// setCount sets the number of occurrences of the given event along with the time it was last observed, in the fields
+// from which Count reads it.
+func setCount(ev *eventsv1.Event, count int32, lastObserved metav1.Time) {
+	if ev.DeprecatedCount > 0 {
+		ev.DeprecatedCount = count
+		if lastObserved.After(ev.DeprecatedLastTimestamp.Time) {
+			ev.DeprecatedLastTimestamp = lastObserved
+		}
+		return
+	}
+	if count <= 1 {
+		ev.Series = nil
+		return
+	}
+	if ev.Series == nil || lastObserved.After(ev.Series.LastObservedTime.Time) {
+		ev.Series = &eventsv1.EventSeries{Count: count, LastObservedTime: metav1.MicroTime{Time: lastObserved.Time}}
+		return
+	}
+	ev.Series.Count = count
+}
@@ -7,89 +7,567 @@ package eventsink
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
+
 	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 )
 
 var _ mkapi.EventSink = (*InMemEventSink)(nil)
 
+// maxAggregatedNames is the maximum number of names aggregated into an event. Once exceeded, the names recorded
+// earliest no longer refer to the aggregated event, though their occurrences remain counted by it.
+const maxAggregatedNames = 32
+
+// InMemEventSink holds events in memory. Like for views, events passed to the sink remain owned by the caller and
+// events returned by the sink are copies owned by the caller.
+//
+// A created event that is isomorphic to an existing event, ie that regards the same object with the same type, reason,
+// action and reporter, is aggregated into the existing event instead of being added. Its name then refers to the
+// aggregated event, whose count is the sum of the occurrences recorded under all its names. Updates and patches through
+// any of these names adjust the count by the change of the occurrences of that name, so that recorders that keep
+// updating the count of the events they created, like those of client-go, are aggregated correctly.
+//
+// The aggregated events are persisted in the Stores of the sink, if any, under the name they were first recorded with,
+// or under the earliest of their remaining names once that name is deleted.
 type InMemEventSink struct {
 	log    logr.Logger
-	events []*eventsv1.Event
+	cfg    mkapi.EventConfig
+	clock  clock.PassiveClock
+	stores Stores
+	mu     sync.Mutex
+	// entries holds the aggregated events in the order in which they were first recorded.
+	entries []*entry
+	// byName maps the names aggregated into an entry to the entry.
+	byName map[cache.ObjectName]*entry
+	// byKey maps the aggregation key of the events of an entry to the entry.
+	byKey map[aggregationKey]*entry
+}
+
+// entry is an aggregated event along with the occurrences recorded under each of the names aggregated into it.
+type entry struct {
+	event *eventsv1.Event
+	key   aggregationKey
+	// names are the names aggregated into the event in the order in which they were recorded.
+	names []string
+	// counts holds the number of occurrences recorded under each of the names.
+	counts map[string]int32
+	// untrackedCount is the number of occurrences recorded under names that are no longer aggregated into the event.
+	untrackedCount int32
+	// recordedAt is the time at which the sink last recorded an occurrence of the event. Like the TTL of events in the
+	// kube-apiserver, retention is measured from it rather than from the times reported by the event recorders.
+	recordedAt time.Time
+	// core is whether the event was first recorded through the core/v1 API, in which case it is persisted as a core/v1
+	// event.
+	core bool
+}
+
+// Stores are the resource stores in which an event sink persists its events, so that these are served through the API
+// of a view like the events created through it.
+type Stores struct {
+	// Events is the store of the events.k8s.io/v1 events.
+	Events mkapi.ResourceStore
+	// CoreEvents is the store of the core/v1 events.
+	CoreEvents mkapi.ResourceStore
 }
 
-func New(log logr.Logger) mkapi.EventSink {
+// aggregationKey identifies isomorphic events. It is the key of the event cache of the events.k8s.io/v1 event
+// broadcaster of client-go, extended by the namespace and the source of core/v1 events.
+type aggregationKey struct {
+	namespace           string
+	eventType           string
+	action              string
+	reason              string
+	reportingController string
+	reportingInstance   string
+	source              corev1.EventSource
+	regarding           corev1.ObjectReference
+	related             corev1.ObjectReference
+}
+
+func keyOf(ev *eventsv1.Event) aggregationKey {
+	key := aggregationKey{
+		namespace:           ev.Namespace,
+		eventType:           ev.Type,
+		action:              ev.Action,
+		reason:              ev.Reason,
+		reportingController: ev.ReportingController,
+		reportingInstance:   ev.ReportingInstance,
+		source:              ev.DeprecatedSource,
+		regarding:           ev.Regarding,
+	}
+	if ev.Related != nil {
+		key.related = *ev.Related
+	}
+	return key
+}
+
+// New creates an event sink retaining events according to the given cfg, with their ages measured by the given clock.
+// The events are persisted in the given stores, or only held in memory if these are empty.
+func New(log logr.Logger, cfg mkapi.EventConfig, clock clock.PassiveClock, stores Stores) mkapi.EventSink {
 	return &InMemEventSink{
 		log:    log,
-		events: make([]*eventsv1.Event, 0, 100),
+		cfg:    cfg,
+		clock:  clock,
+		stores: stores,
+		byName: make(map[cache.ObjectName]*entry),
+		byKey:  make(map[aggregationKey]*entry),
 	}
 }
 
 func (s *InMemEventSink) Create(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
-	s.events = append(s.events, event)
-	return event, nil
+	return s.create(event, false)
 }
 
-func (s *InMemEventSink) Update(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
-	for i, e := range s.events {
-		if e.Name == event.Name && e.Namespace == event.Namespace {
-			s.events[i] = event
-			return event, nil
+// create records the given event, which was recorded through the core/v1 API if core is set.
+func (s *InMemEventSink) create(event *eventsv1.Event, core bool) (*eventsv1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	if event.Name == "" {
+		return nil, apierrors.NewBadRequest("cannot record event without name")
+	}
+	name := cache.NewObjectName(event.Namespace, event.Name)
+	if _, ok := s.byName[name]; ok {
+		return nil, apierrors.NewAlreadyExists(eventsv1.Resource("events"), name.String())
+	}
+	ev := event.DeepCopy()
+	if ev.CreationTimestamp.IsZero() {
+		ev.CreationTimestamp = metav1.Time{Time: s.clock.Now()}
+	}
+	key := keyOf(ev)
+	if e, ok := s.byKey[key]; ok && !s.dropIfStale(e) {
+		s.track(e, ev.Name, Count(ev))
+		e.event.Note = ev.Note
+		setCount(e.event, e.count(), LastObservedTime(ev))
+		e.recordedAt = s.clock.Now()
+		if err := s.save(e); err != nil {
+			return nil, err
 		}
+		s.log.V(4).Info("aggregated event", "name", name, "into", e.event.Name, "reason", ev.Reason, "count", e.count())
+		return ev, nil
+	}
+	if ev.UID == "" {
+		ev.UID = uuid.NewUUID()
 	}
-	return nil, apierrors.NewNotFound(eventsv1.Resource("events"), event.Name) //TODO: is it plural events or singluar event ?
+	e := &entry{event: ev.DeepCopy(), key: key, counts: make(map[string]int32), recordedAt: s.clock.Now(), core: core}
+	if err := s.add(e); err != nil {
+		return nil, err
+	}
+	ev.ResourceVersion = e.event.ResourceVersion
+	s.track(e, ev.Name, Count(ev))
+	s.entries = append(s.entries, e)
+	s.byKey[key] = e
+	s.evictExcess()
+	return ev, nil
+}
+
+func (s *InMemEventSink) Update(ctx context.Context, event *eventsv1.Event) (*eventsv1.Event, error) {
+	return s.update(cache.NewObjectName(event.Namespace, event.Name), func(*eventsv1.Event) (*eventsv1.Event, error) {
+		return event.DeepCopy(), nil
+	})
 }
 
 func (s *InMemEventSink) Patch(ctx context.Context, oldEvent *eventsv1.Event, patchData []byte) (*eventsv1.Event, error) {
-	for i, e := range s.events {
-		if e.Name == oldEvent.Name && e.Namespace == oldEvent.Namespace {
-			originalJSON, err := json.Marshal(e)
-			if err != nil {
-				//TODO: start using apierrors
-				return nil, fmt.Errorf("failed to marshal original event: %w", err)
-			}
-			patchedJSON, err := strategicpatch.StrategicMergePatch(originalJSON, patchData, eventsv1.Event{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to apply strategic merge patch: %w", err)
-			}
+	return s.update(cache.NewObjectName(oldEvent.Namespace, oldEvent.Name), func(ev *eventsv1.Event) (*eventsv1.Event, error) {
+		patched := &eventsv1.Event{}
+		if err := applyPatch(ev, patchData, patched); err != nil {
+			return nil, err
+		}
+		return patched, nil
+	})
+}
 
-			var patchedEvent eventsv1.Event
-			if err := json.Unmarshal(patchedJSON, &patchedEvent); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal patched event: %w", err)
-			}
-			s.events[i] = &patchedEvent
-			return s.events[i], nil
+// update replaces the event recorded under the given name with the event returned by the given updateFn, which is
+// passed the event as recorded under that name. The aggregated event takes over the updated fields along with the
+// change in the occurrences of the name.
+func (s *InMemEventSink) update(name cache.ObjectName, updateFn func(ev *eventsv1.Event) (*eventsv1.Event, error)) (*eventsv1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	e, ok := s.byName[name]
+	if !ok || s.dropIfStale(e) {
+		return nil, apierrors.NewNotFound(eventsv1.Resource("events"), name.String())
+	}
+	ev, err := updateFn(e.recordedAs(name.Name))
+	if err != nil {
+		return nil, err
+	}
+	ev.ObjectMeta = *e.event.ObjectMeta.DeepCopy()
+	ev.Name = name.Name
+	e.counts[name.Name] = Count(ev)
+	aggregated := ev.DeepCopy()
+	aggregated.ObjectMeta = e.event.ObjectMeta
+	setCount(aggregated, e.count(), LastObservedTime(ev))
+	e.event = aggregated
+	e.recordedAt = s.clock.Now()
+	if err = s.save(e); err != nil {
+		return nil, err
+	}
+	ev.ResourceVersion = e.event.ResourceVersion
+	if key := keyOf(aggregated); key != e.key {
+		if s.byKey[e.key] == e {
+			delete(s.byKey, e.key)
+		}
+		e.key = key
+		if _, exists := s.byKey[key]; !exists {
+			s.byKey[key] = e
 		}
 	}
-	return nil, apierrors.NewNotFound(eventsv1.Resource("events"), oldEvent.Name) //TODO: is it plural events or singluar event ?
+	return ev, nil
 }
 
+// Delete removes the name of the given event along with the occurrences recorded under it from the aggregated event it
+// refers to. The aggregated event is only removed once no names refer to it anymore. If it was persisted under the
+// removed name, it is persisted again under the earliest of its remaining names.
 func (s *InMemEventSink) Delete(ctx context.Context, event *eventsv1.Event) error {
-	for i, e := range s.events {
-		if e.Name == event.Name && e.Namespace == event.Namespace {
-			s.log.Info("Deleting event - set to nil", "index", i, "event", e)
-			s.events[i] = nil
-			return nil
-		}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	name := cache.NewObjectName(event.Namespace, event.Name)
+	e, ok := s.byName[name]
+	if !ok || s.dropIfStale(e) {
+		return apierrors.NewNotFound(eventsv1.Resource("events"), name.String())
 	}
-	return apierrors.NewNotFound(eventsv1.Resource("events"), event.Name) //TODO: is it plural events or singluar event ?
+	s.untrack(e, name.Name)
+	if len(e.names) == 0 {
+		s.log.V(4).Info("deleting event", "name", e.event.Name, "namespace", e.event.Namespace, "reason", e.event.Reason)
+		s.remove(e)
+		return nil
+	}
+	s.log.V(4).Info("deleting aggregated name of event", "name", name, "from", e.event.Name, "reason", e.event.Reason)
+	setCount(e.event, e.count(), LastObservedTime(e.event))
+	if e.event.Name != name.Name {
+		return s.save(e)
+	}
+	s.deleteStored(e)
+	e.event.Name = e.names[0]
+	e.event.UID = uuid.NewUUID()
+	return s.add(e)
+}
+
+// CoreV1 returns the sink for core/v1 events, which are converted to and from the events.k8s.io/v1 events of this sink.
+func (s *InMemEventSink) CoreV1() record.EventSink {
+	return coreV1Sink{s}
 }
 
 func (s *InMemEventSink) List() []*eventsv1.Event {
-	evs := make([]*eventsv1.Event, 0, len(s.events))
-	for _, e := range s.events {
-		if e != nil {
-			evs = append(evs, e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	evs := make([]*eventsv1.Event, 0, len(s.entries))
+	for _, e := range slices.Clone(s.entries) {
+		if !s.dropIfStale(e) {
+			evs = append(evs, e.event.DeepCopy())
 		}
 	}
 	return evs
 }
 
+func (s *InMemEventSink) RemoveExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+}
+
+// Reset discards the events of the sink. The events held in its stores, like those of a restored view archive, are then
+// recorded again in the order of their creation, each under its own name.
 func (s *InMemEventSink) Reset() {
-	s.events = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	clear(s.byName)
+	clear(s.byKey)
+	for _, st := range []struct {
+		store mkapi.ResourceStore
+		core  bool
+	}{{s.stores.Events, false}, {s.stores.CoreEvents, true}} {
+		if st.store == nil {
+			continue
+		}
+		metaObjs, _, err := st.store.ListMetaObjects(mkapi.MatchCriteria{})
+		if err != nil {
+			s.log.Error(err, "cannot list stored events")
+			continue
+		}
+		for _, mo := range metaObjs {
+			var ev *eventsv1.Event
+			switch obj := mo.(type) {
+			case *eventsv1.Event:
+				ev = obj
+			case *corev1.Event:
+				ev = FromCoreV1(obj)
+			default:
+				s.log.Error(fmt.Errorf("object %q is not an event", objutil.CacheName(mo)), "cannot record stored event")
+				continue
+			}
+			e := &entry{event: ev, key: keyOf(ev), counts: make(map[string]int32), recordedAt: s.clock.Now(), core: st.core}
+			s.track(e, ev.Name, Count(ev))
+			s.entries = append(s.entries, e)
+		}
+	}
+	slices.SortStableFunc(s.entries, func(a, b *entry) int {
+		return a.event.CreationTimestamp.Compare(b.event.CreationTimestamp.Time)
+	})
+	for _, e := range s.entries {
+		if _, exists := s.byKey[e.key]; !exists {
+			s.byKey[e.key] = e
+		}
+	}
+	s.evictExcess()
+}
+
+func (s *InMemEventSink) maxCount() int {
+	if s.cfg.MaxCount <= 0 {
+		return mkapi.DefaultEventMaxCount
+	}
+	return s.cfg.MaxCount
+}
+
+func (s *InMemEventSink) ttl() time.Duration {
+	if s.cfg.TTL <= 0 {
+		return mkapi.DefaultEventTTL
+	}
+	return s.cfg.TTL
+}
+
+// removeExpired removes the events that were last recorded longer than the TTL ago. Must be called with s.mu held.
+func (s *InMemEventSink) removeExpired() {
+	expiry := s.clock.Now().Add(-s.ttl())
+	s.entries = slices.DeleteFunc(s.entries, func(e *entry) bool {
+		if !e.recordedAt.Before(expiry) {
+			return false
+		}
+		s.log.V(4).Info("removing expired event", "name", e.event.Name, "namespace", e.event.Namespace, "reason", e.event.Reason)
+		s.forget(e)
+		s.deleteStored(e)
+		return true
+	})
+}
+
+// evictExcess removes the least recently recorded events in excess of the max count. Must be called with s.mu held.
+func (s *InMemEventSink) evictExcess() {
+	for len(s.entries) > s.maxCount() {
+		oldest := slices.MinFunc(s.entries, func(a, b *entry) int {
+			return a.recordedAt.Compare(b.recordedAt)
+		})
+		s.log.V(4).Info("evicting event", "name", oldest.event.Name, "namespace", oldest.event.Namespace, "reason", oldest.event.Reason, "maxCount", s.maxCount())
+		s.remove(oldest)
+	}
+}
+
+// remove removes the given entry. Must be called with s.mu held.
+func (s *InMemEventSink) remove(e *entry) {
+	s.entries = slices.DeleteFunc(s.entries, func(o *entry) bool { return o == e })
+	s.forget(e)
+	s.deleteStored(e)
+}
+
+// storeOf returns the store in which the event of the given entry is persisted along with its GVK, or nil if the sink
+// has no stores.
+func (s *InMemEventSink) storeOf(e *entry) (mkapi.ResourceStore, schema.GroupVersionKind) {
+	if e.core {
+		return s.stores.CoreEvents, typeinfo.CoreEventsDescriptor.GVK
+	}
+	return s.stores.Events, typeinfo.EventsDescriptor.GVK
+}
+
+// add persists the event of the given new entry. Like the kube-apiserver, it returns a 409 AlreadyExists error if an
+// event of the same name is already stored. Must be called with s.mu held.
+func (s *InMemEventSink) add(e *entry) error {
+	store, _ := s.storeOf(e)
+	if store == nil {
+		return nil
+	}
+	name := cache.NewObjectName(e.event.Namespace, e.event.Name)
+	if _, err := store.Get(name); err == nil {
+		return apierrors.NewAlreadyExists(eventsv1.Resource("events"), name.String())
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	return s.write(e, store.Add)
+}
+
+// save persists the changed event of the given entry, regardless of the resource version of the stored event. Must be
+// called with s.mu held.
+func (s *InMemEventSink) save(e *entry) error {
+	store, _ := s.storeOf(e)
+	if store == nil {
+		return nil
+	}
+	return s.write(e, store.Update)
+}
+
+// write passes the event of the given entry as an object of the API it is persisted with to the given store func, and
+// takes over the resource version assigned by the store. Must be called with s.mu held.
+func (s *InMemEventSink) write(e *entry, storeFn func(mo metav1.Object) error) error {
+	var mo metav1.Object
+	if e.core {
+		mo = ToCoreV1(e.event)
+	} else {
+		mo = e.event.DeepCopy()
+	}
+	_, gvk := s.storeOf(e)
+	objutil.SetMetaObjectGVK(mo, gvk)
+	mo.SetResourceVersion("")
+	if err := storeFn(mo); err != nil {
+		return err
+	}
+	e.event.ResourceVersion = mo.GetResourceVersion()
+	return nil
+}
+
+// dropIfStale removes the given entry without deleting its persisted event if the latter has been deleted or replaced
+// other than through the sink, Ex: by the deletion of a collection of events, and returns whether it did so. Must be
+// called with s.mu held.
+func (s *InMemEventSink) dropIfStale(e *entry) bool {
+	store, _ := s.storeOf(e)
+	if store == nil {
+		return false
+	}
+	obj, err := store.Get(cache.NewObjectName(e.event.Namespace, e.event.Name))
+	if err == nil {
+		mo, accessorErr := meta.Accessor(obj)
+		if accessorErr != nil || mo.GetUID() == e.event.UID {
+			return false
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return false
+	}
+	s.log.V(4).Info("dropping event removed from store", "name", e.event.Name, "namespace", e.event.Namespace, "reason", e.event.Reason)
+	s.entries = slices.DeleteFunc(s.entries, func(o *entry) bool { return o == e })
+	s.forget(e)
+	return true
+}
+
+// deleteStored removes the persisted event of the given entry. Must be called with s.mu held.
+func (s *InMemEventSink) deleteStored(e *entry) {
+	store, _ := s.storeOf(e)
+	if store == nil {
+		return
+	}
+	name := cache.NewObjectName(e.event.Namespace, e.event.Name)
+	if err := store.Delete(name, mkapi.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		s.log.Error(err, "cannot delete stored event", "name", name)
+	}
+}
+
+// forget removes the names and the key of the given entry from the lookups of the sink. Must be called with s.mu held.
+func (s *InMemEventSink) forget(e *entry) {
+	for _, name := range e.names {
+		delete(s.byName, cache.NewObjectName(e.key.namespace, name))
+	}
+	if s.byKey[e.key] == e {
+		delete(s.byKey, e.key)
+	}
+}
+
+// track aggregates the given name into the given entry with the given number of occurrences recorded under it. Must
+// be called with s.mu held.
+func (s *InMemEventSink) track(e *entry, name string, count int32) {
+	e.names = append(e.names, name)
+	e.counts[name] = count
+	s.byName[cache.NewObjectName(e.key.namespace, name)] = e
+	if len(e.names) > maxAggregatedNames {
+		untracked := e.names[0]
+		e.untrackedCount += e.counts[untracked]
+		delete(e.counts, untracked)
+		delete(s.byName, cache.NewObjectName(e.key.namespace, untracked))
+		e.names = e.names[1:]
+	}
+}
+
+// untrack removes the given name along with the occurrences recorded under it from the given entry. Must be called
+// with s.mu held.
+func (s *InMemEventSink) untrack(e *entry, name string) {
+	e.names = slices.DeleteFunc(e.names, func(n string) bool { return n == name })
+	delete(e.counts, name)
+	delete(s.byName, cache.NewObjectName(e.key.namespace, name))
+}
+
+// count returns the sum of the occurrences recorded under all names aggregated into the entry.
+func (e *entry) count() int32 {
+	count := e.untrackedCount
+	for _, c := range e.counts {
+		count += c
+	}
+	return count
+}
+
+// recordedAs returns a copy of the aggregated event as recorded under the given name, ie with the name and the
+// occurrences recorded under it.
+func (e *entry) recordedAs(name string) *eventsv1.Event {
+	ev := e.event.DeepCopy()
+	ev.Name = name
+	setCount(ev, e.counts[name], LastObservedTime(ev))
+	return ev
+}
+
+// applyPatch applies the given strategic merge patch to the given event and stores the result in patched, which must
+// be a pointer to an event of the same API version.
+func applyPatch(event any, patchData []byte, patched any) error {
+	originalJSON, err := json.Marshal(event)
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("cannot marshal event: %w", err))
+	}
+	patchedJSON, err := strategicpatch.StrategicMergePatch(originalJSON, patchData, patched)
+	if err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("cannot apply strategic merge patch to event: %v", err))
+	}
+	if err = json.Unmarshal(patchedJSON, patched); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("cannot unmarshal patched event: %v", err))
+	}
+	return nil
+}
+
+// coreV1Sink records core/v1 events in the events.k8s.io/v1 events of an InMemEventSink.
+type coreV1Sink struct {
+	s *InMemEventSink
+}
+
+func (c coreV1Sink) Create(event *corev1.Event) (*corev1.Event, error) {
+	ev, err := c.s.create(FromCoreV1(event), true)
+	if err != nil {
+		return nil, err
+	}
+	return ToCoreV1(ev), nil
+}
+
+func (c coreV1Sink) Update(event *corev1.Event) (*corev1.Event, error) {
+	ev, err := c.s.Update(context.Background(), FromCoreV1(event))
+	if err != nil {
+		return nil, err
+	}
+	return ToCoreV1(ev), nil
+}
+
+func (c coreV1Sink) Patch(oldEvent *corev1.Event, data []byte) (*corev1.Event, error) {
+	ev, err := c.s.update(cache.NewObjectName(oldEvent.Namespace, oldEvent.Name), func(ev *eventsv1.Event) (*eventsv1.Event, error) {
+		patched := &corev1.Event{}
+		if err := applyPatch(ToCoreV1(ev), data, patched); err != nil {
+			return nil, err
+		}
+		return FromCoreV1(patched), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ToCoreV1(ev), nil
 }
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package eventsink
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/store"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var testStartTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestAggregation(t *testing.T) {
+	tests := map[string]struct {
+		record     func(t *testing.T, s mkapi.EventSink)
+		wantCounts map[string]int32
+	}{
+		"distinct events": {
+			record: func(t *testing.T, s mkapi.EventSink) {
+				mustCreate(t, s, newTestEvent("a.1", "pod-a", "FailedScheduling"))
+				mustCreate(t, s, newTestEvent("b.1", "pod-b", "FailedScheduling"))
+				mustCreate(t, s, newTestEvent("a.2", "pod-a", "Scheduled"))
+			},
+			wantCounts: map[string]int32{"a.1": 1, "b.1": 1, "a.2": 1},
+		},
+		"repeated events": {
+			record: func(t *testing.T, s mkapi.EventSink) {
+				for _, name := range []string{"a.1", "a.2", "a.3"} {
+					mustCreate(t, s, newTestEvent(name, "pod-a", "FailedScheduling"))
+				}
+			},
+			wantCounts: map[string]int32{"a.1": 3},
+		},
+		"series patches of repeated events": {
+			record: func(t *testing.T, s mkapi.EventSink) {
+				first, second := newTestEvent("a.1", "pod-a", "FailedScheduling"), newTestEvent("a.2", "pod-a", "FailedScheduling")
+				mustCreate(t, s, first)
+				mustCreate(t, s, second)
+				// like the event broadcaster of client-go, which patches the series of its own event.
+				mustPatchSeries(t, s, second, 2)
+				mustPatchSeries(t, s, first, 3)
+			},
+			wantCounts: map[string]int32{"a.1": 5},
+		},
+		"repeated core/v1 events": {
+			record: func(t *testing.T, s mkapi.EventSink) {
+				coreSink := s.CoreV1()
+				for _, name := range []string{"a.1", "a.2"} {
+					if _, err := coreSink.Create(newTestCoreEvent(name, "pod-a")); err != nil {
+						t.Fatalf("failed to create core/v1 event %q: %v", name, err)
+					}
+				}
+				// like the event correlator of client-go, which patches the count of its own event.
+				if _, err := coreSink.Patch(newTestCoreEvent("a.2", "pod-a"), []byte(`{"count":4}`)); err != nil {
+					t.Fatalf("failed to patch core/v1 event: %v", err)
+				}
+			},
+			wantCounts: map[string]int32{"a.1": 5},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := New(logr.Discard(), mkapi.EventConfig{}, testingclock.NewFakeClock(testStartTime), Stores{})
+			tc.record(t, s)
+			gotCounts := make(map[string]int32)
+			for _, ev := range s.List() {
+				gotCounts[ev.Name] = Count(ev)
+			}
+			if len(gotCounts) != len(tc.wantCounts) {
+				t.Errorf("expected event counts %v, got %v", tc.wantCounts, gotCounts)
+			}
+			for evName, want := range tc.wantCounts {
+				if got := gotCounts[evName]; got != want {
+					t.Errorf("expected count %d of event %q, got %d", want, evName, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateExistingEvent(t *testing.T) {
+	s := New(logr.Discard(), mkapi.EventConfig{}, testingclock.NewFakeClock(testStartTime), Stores{})
+	mustCreate(t, s, newTestEvent("a.1", "pod-a", "FailedScheduling"))
+	mustCreate(t, s, newTestEvent("a.2", "pod-a", "FailedScheduling"))
+	for _, name := range []string{"a.1", "a.2"} {
+		if _, err := s.Create(context.Background(), newTestEvent(name, "pod-a", "FailedScheduling")); !apierrors.IsAlreadyExists(err) {
+			t.Errorf("expected already exists error for event %q, got %v", name, err)
+		}
+	}
+}
+
+func TestRetention(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(testStartTime)
+	s := New(logr.Discard(), mkapi.EventConfig{MaxCount: 2, TTL: time.Hour}, fakeClock, Stores{})
+	mustCreate(t, s, newTestEvent("a.1", "pod-a", "FailedScheduling"))
+	fakeClock.Step(time.Minute)
+	mustCreate(t, s, newTestEvent("b.1", "pod-b", "FailedScheduling"))
+	fakeClock.Step(time.Minute)
+	// the repeated event of pod-a is now recorded more recently than the event of pod-b.
+	mustCreate(t, s, newTestEvent("a.2", "pod-a", "FailedScheduling"))
+	fakeClock.Step(time.Minute)
+	mustCreate(t, s, newTestEvent("c.1", "pod-c", "FailedScheduling"))
+	assertEventNames(t, s, "a.1", "c.1")
+
+	fakeClock.Step(time.Hour - time.Minute)
+	mustCreate(t, s, newTestEvent("c.2", "pod-c", "FailedScheduling"))
+	fakeClock.Step(time.Minute)
+	assertEventNames(t, s, "c.1")
+	if _, err := s.Patch(context.Background(), newTestEvent("a.2", "pod-a", "FailedScheduling"), []byte(`{}`)); !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found error for patch of expired event, got %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(testStartTime)
+	eventStore := store.NewInMemResourceStore(logr.Discard(), &mkapi.ResourceStoreArgs{
+		Name:          typeinfo.EventsDescriptor.GVR.Resource,
+		ObjectGVK:     typeinfo.EventsDescriptor.GVK,
+		ObjectListGVK: typeinfo.EventsDescriptor.ListGVK,
+		Scheme:        typeinfo.SupportedScheme,
+		Clock:         fakeClock,
+	})
+	s := New(logr.Discard(), mkapi.EventConfig{}, fakeClock, Stores{Events: eventStore})
+	for _, name := range []string{"a.1", "a.2", "a.3"} {
+		mustCreate(t, s, newTestEvent(name, "pod-a", "FailedScheduling"))
+	}
+	tests := []struct {
+		deletedName string
+		wantName    string
+		wantCount   int32
+	}{
+		{deletedName: "a.2", wantName: "a.1", wantCount: 2},
+		// the aggregated event is persisted again under the remaining name.
+		{deletedName: "a.1", wantName: "a.3", wantCount: 1},
+		{deletedName: "a.3"},
+	}
+	for _, tc := range tests {
+		if err := s.Delete(context.Background(), newTestEvent(tc.deletedName, "pod-a", "FailedScheduling")); err != nil {
+			t.Fatalf("failed to delete event %q: %v", tc.deletedName, err)
+		}
+		metaObjs, _, err := eventStore.ListMetaObjects(mkapi.MatchCriteria{})
+		if err != nil {
+			t.Fatalf("failed to list stored events: %v", err)
+		}
+		if tc.wantName == "" {
+			if len(metaObjs) != 0 || len(s.List()) != 0 {
+				t.Errorf("expected no events after deletion of %q, got %v", tc.deletedName, s.List())
+			}
+			continue
+		}
+		if len(metaObjs) != 1 || metaObjs[0].GetName() != tc.wantName || Count(metaObjs[0].(*eventsv1.Event)) != tc.wantCount {
+			t.Errorf("expected stored event %q of count %d after deletion of %q, got %v", tc.wantName, tc.wantCount, tc.deletedName, metaObjs)
+		}
+	}
+	if err := s.Delete(context.Background(), newTestEvent("a.1", "pod-a", "FailedScheduling")); !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found error for deletion of deleted event, got %v", err)
+	}
+}
+
+func TestConversion(t *testing.T) {
+	event := newTestCoreEvent("a.1", "pod-a")
+	event.Related = &corev1.ObjectReference{Kind: "Node", Name: "node-a"}
+	event.Series = &corev1.EventSeries{Count: 2, LastObservedTime: metav1.NewMicroTime(testStartTime)}
+	ev := FromCoreV1(event)
+	if ev.Regarding != event.InvolvedObject || ev.Note != event.Message || ev.DeprecatedCount != event.Count {
+		t.Errorf("unexpected conversion of core/v1 event %+v to %+v", event, ev)
+	}
+	got := ToCoreV1(ev)
+	want := event.DeepCopy()
+	want.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Event"}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("expected round trip conversion to be lossless, want %s, got %s", wantJSON, gotJSON)
+	}
+}
+
+func mustCreate(t *testing.T, s mkapi.EventSink, ev *eventsv1.Event) {
+	t.Helper()
+	if _, err := s.Create(context.Background(), ev); err != nil {
+		t.Fatalf("failed to create event %q: %v", ev.Name, err)
+	}
+}
+
+// mustPatchSeries patches the given event with a series of the given count like the event broadcaster of client-go.
+func mustPatchSeries(t *testing.T, s mkapi.EventSink, ev *eventsv1.Event, count int32) {
+	t.Helper()
+	oldData, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withSeries := ev.DeepCopy()
+	withSeries.Series = &eventsv1.EventSeries{Count: count, LastObservedTime: metav1.NewMicroTime(testStartTime)}
+	newData, err := json.Marshal(withSeries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, eventsv1.Event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Patch(context.Background(), ev, patch); err != nil {
+		t.Fatalf("failed to patch series of event %q: %v", ev.Name, err)
+	}
+}
+
+func assertEventNames(t *testing.T, s mkapi.EventSink, wantNames ...string) {
+	t.Helper()
+	var gotNames []string
+	for _, ev := range s.List() {
+		gotNames = append(gotNames, ev.Name)
+	}
+	if len(gotNames) != len(wantNames) {
+		t.Fatalf("expected events %v, got %v", wantNames, gotNames)
+	}
+	for i := range wantNames {
+		if gotNames[i] != wantNames[i] {
+			t.Errorf("expected events %v, got %v", wantNames, gotNames)
+		}
+	}
+}
+
+func newTestEvent(name, podName, reason string) *eventsv1.Event {
+	return &eventsv1.Event{
+		ObjectMeta:          metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Regarding:           corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: podName},
+		Reason:              reason,
+		Note:                reason + " of " + podName,
+		Type:                corev1.EventTypeWarning,
+		Action:              "Scheduling",
+		ReportingController: "default-scheduler",
+		ReportingInstance:   "default-scheduler-0",
+	}
+}
+
+func newTestCoreEvent(name, podName string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: podName},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Source:         corev1.EventSource{Component: "kubelet", Host: "node-a"},
+		FirstTimestamp: metav1.NewTime(testStartTime),
+		LastTimestamp:  metav1.NewTime(testStartTime),
+		Count:          1,
+		Type:           corev1.EventTypeWarning,
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// healthCheck is a named check of the /healthz, /livez and /readyz endpoints.
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// pingCheck always passes, so that it only checks whether the server responds.
+var pingCheck = healthCheck{name: "ping", check: func() error { return nil }}
+
+// storesCheck returns a check that passes once the stores of all built-in kinds of the given view are initialized and
+// the custom resources defined in the view can be resolved.
+func storesCheck(v mkapi.View) healthCheck {
+	return healthCheck{name: "stores", check: func() error {
+		for _, d := range typeinfo.SupportedDescriptors {
+			if _, err := v.GetResourceStore(d.GVK); err != nil {
+				return fmt.Errorf("store of %q is not initialized: %w", d.GVR.GroupResource(), err)
+			}
+		}
+		_, err := view.CustomResourceDescriptors(v)
+		return err
+	}}
+}
+
+// shutdownCheck returns a check that fails once the server is shutting down, so that clients stop sending it requests.
+func (k *InMemoryKAPI) shutdownCheck() healthCheck {
+	return healthCheck{name: "shutdown", check: func() error {
+		if k.shuttingDown.Load() {
+			return errors.New("server is shutting down")
+		}
+		return nil
+	}}
+}
+
+// mirrorCheck returns a check that passes once the base view has been synced with the source cluster in mirror mode.
+func (k *InMemoryKAPI) mirrorCheck() healthCheck {
+	return healthCheck{name: "mirror", check: func() error {
+		if !k.mirror.HasSynced() {
+			return errors.New("base view is not yet synced with the source cluster")
+		}
+		return nil
+	}}
+}
+
+// registerHealthRoutes registers the /healthz, /livez and /readyz endpoints of the given view on the given mux along
+// with the endpoints of their individual checks, like /readyz/stores. Like the kube-apiserver, /livez only checks that
+// the server responds, while /readyz additionally checks that the stores of the view are initialized and the server is
+// not shutting down. In mirror mode, /readyz of the base view also checks that it has been synced with the source
+// cluster.
+func (k *InMemoryKAPI) registerHealthRoutes(mux *http.ServeMux, v mkapi.View) {
+	stores := storesCheck(v)
+	readyChecks := []healthCheck{pingCheck, stores, k.shutdownCheck()}
+	if k.mirror != nil && v == k.baseView {
+		readyChecks = append(readyChecks, k.mirrorCheck())
+	}
+	registerHealthEndpoint(mux, "healthz", pingCheck, stores)
+	registerHealthEndpoint(mux, "livez", pingCheck)
+	registerHealthEndpoint(mux, "readyz", readyChecks...)
+}
+
+func registerHealthEndpoint(mux *http.ServeMux, endpoint string, checks ...healthCheck) {
+	mux.HandleFunc("GET /"+endpoint, handleHealth(endpoint, checks))
+	for _, c := range checks {
+		mux.HandleFunc("GET /"+endpoint+"/"+c.name, handleHealthCheck(c))
+	}
+}
+
+// handleHealth serves the health endpoint of the given name, which passes if all the given checks pass. Like the
+// kube-apiserver, it responds with ok, or with the result of every check if the verbose query parameter is given.
+// Checks named by exclude query parameters are skipped.
+func handleHealth(endpoint string, checks []healthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := logr.FromContextOrDiscard(r.Context())
+		excluded := sets.New(r.URL.Query()["exclude"]...)
+		var output bytes.Buffer
+		var failed []string
+		for _, c := range checks {
+			if excluded.Has(c.name) {
+				excluded.Delete(c.name)
+				_, _ = fmt.Fprintf(&output, "[+]%s excluded: ok\n", c.name)
+				continue
+			}
+			if err := c.check(); err != nil {
+				log.Info("health check failed", "endpoint", endpoint, "check", c.name, "reason", err.Error())
+				_, _ = fmt.Fprintf(&output, "[-]%s failed: %v\n", c.name, err)
+				failed = append(failed, c.name)
+				continue
+			}
+			_, _ = fmt.Fprintf(&output, "[+]%s ok\n", c.name)
+		}
+		if excluded.Len() > 0 {
+			_, _ = fmt.Fprintf(&output, "warn: some health checks cannot be excluded: no matches for %s\n",
+				strings.Join(sets.List(excluded), ","))
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if len(failed) > 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "%s%s check failed\n", output.String(), endpoint)
+			return
+		}
+		if _, verbose := r.URL.Query()["verbose"]; !verbose {
+			_, _ = fmt.Fprint(w, "ok")
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s%s check passed\n", output.String(), endpoint)
+	}
+}
+
+// handleHealthCheck serves the endpoint of the given individual check.
+func handleHealthCheck(c healthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if err := c.check(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprintf(w, "internal server error: %v\n", err)
+			return
+		}
+		_, _ = fmt.Fprint(w, "ok")
+	}
+}
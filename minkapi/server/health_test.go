@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	tests := map[string]struct {
+		target       string
+		shuttingDown bool
+		wantCode     int
+		wantBody     string
+	}{
+		"livez": {
+			target:   "/livez",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"verbose readyz": {
+			target:   "/readyz?verbose",
+			wantCode: http.StatusOK,
+			wantBody: "[+]ping ok\n[+]stores ok\n[+]shutdown ok\nreadyz check passed\n",
+		},
+		"readyz while shutting down": {
+			target:       "/readyz",
+			shuttingDown: true,
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     "[+]ping ok\n[+]stores ok\n[-]shutdown failed: server is shutting down\nreadyz check failed\n",
+		},
+		"readyz excluding shutdown while shutting down": {
+			target:       "/readyz?exclude=shutdown",
+			shuttingDown: true,
+			wantCode:     http.StatusOK,
+			wantBody:     "ok",
+		},
+		"livez while shutting down": {
+			target:       "/livez",
+			shuttingDown: true,
+			wantCode:     http.StatusOK,
+			wantBody:     "ok",
+		},
+		"healthz excluding unknown check": {
+			target:   "/healthz?verbose&exclude=etcd",
+			wantCode: http.StatusOK,
+			wantBody: "[+]ping ok\n[+]stores ok\nwarn: some health checks cannot be excluded: no matches for etcd\nhealthz check passed\n",
+		},
+		"healthz of sandbox view": {
+			target:   "/sb/healthz",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"individual check of sandbox view": {
+			target:   "/sb/readyz/stores",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		"failing individual check": {
+			target:       "/readyz/shutdown",
+			shuttingDown: true,
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     "internal server error: server is shutting down\n",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := newMetricsTestServer(t)
+			k.shuttingDown.Store(tc.shuttingDown)
+			w := httptest.NewRecorder()
+			k.rootMux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if w.Code != tc.wantCode {
+				t.Errorf("expected status code %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("expected body %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	k := newMetricsTestServer(t)
+	for _, target := range []string{"/version", "/sb/version"} {
+		w := httptest.NewRecorder()
+		k.rootMux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status code %d for %s, got %d", http.StatusOK, target, w.Code)
+		}
+		var got version.Info
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode version of %s: %v", target, err)
+		}
+		if got != typeinfo.ServerVersion {
+			t.Errorf("expected version %v for %s, got %v", typeinfo.ServerVersion, target, got)
+		}
+		if !strings.HasPrefix(got.GitVersion, "v1.") {
+			t.Errorf("expected Kubernetes version of the k8s.io/api module for %s, got %q", target, got.GitVersion)
+		}
+	}
+}
+
+func TestStopClosesSandboxViews(t *testing.T) {
+	k, sandboxView := newSubresourceTestServer(t)
+	s, err := sandboxView.GetResourceStore(typeinfo.PodsDescriptor.GVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the garbage collector of the sandbox view also watches the store.
+	watcherCount := s.GetStats().WatcherCount
+	watchDone := make(chan error, 1)
+	go func() {
+		watchDone <- s.Watch(context.Background(), mkapi.WatchOptions{}, func(watch.Event) error { return nil })
+	}()
+	for s.GetStats().WatcherCount == watcherCount {
+		time.Sleep(time.Millisecond)
+	}
+	if err = k.Stop(context.Background()); err != nil {
+		t.Fatalf("failed to stop server: %v", err)
+	}
+	select {
+	case <-watchDone:
+	case <-time.After(5 * time.Second):
+		t.Error("expected watch on sandbox view to be terminated on stop")
+	}
+}
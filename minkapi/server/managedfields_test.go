@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestManagedFieldsOfUpdates(t *testing.T) {
+	k, sandboxView := newSubresourceTestServer(t)
+	const configMaps = "/sb/api/v1/namespaces/default/configmaps"
+	steps := []struct {
+		method      string
+		target      string
+		userAgent   string
+		contentType string
+		body        string
+		// wantFields are the fields owned by the field managers of the Update entries in the managedFields after the step.
+		wantFields map[string]string
+	}{
+		{
+			method:      http.MethodPost,
+			target:      configMaps + "?fieldManager=creator",
+			contentType: "application/json",
+			body:        `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"},"data":{"a":"1"}}`,
+			wantFields:  map[string]string{"creator": `{"f:data":{".":{},"f:a":{}}}`},
+		},
+		{
+			// like in the kube-apiserver, the field manager defaults to the prefix of the user agent.
+			method:      http.MethodPut,
+			target:      configMaps + "/cm",
+			userAgent:   "updater/v1.33.0 (linux/amd64)",
+			contentType: "application/json",
+			body:        `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"},"data":{"a":"1","b":"2"}}`,
+			wantFields: map[string]string{
+				"creator": `{"f:data":{".":{},"f:a":{}}}`,
+				"updater": `{"f:data":{"f:b":{}}}`,
+			},
+		},
+		{
+			// the patcher takes over the ownership of the field it changes.
+			method:      http.MethodPatch,
+			target:      configMaps + "/cm?fieldManager=patcher",
+			contentType: string(types.MergePatchType),
+			body:        `{"data":{"a":"3"}}`,
+			wantFields: map[string]string{
+				"creator": `{"f:data":{}}`,
+				"patcher": `{"f:data":{"f:a":{}}}`,
+				"updater": `{"f:data":{"f:b":{}}}`,
+			},
+		},
+	}
+	for i, step := range steps {
+		r := httptest.NewRequest(step.method, step.target, strings.NewReader(step.body))
+		r.Header.Set("Content-Type", step.contentType)
+		r.Header.Set("User-Agent", step.userAgent)
+		w := httptest.NewRecorder()
+		k.rootMux.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("step %d: expected status code %d, got %d: %s", i, http.StatusOK, w.Code, w.Body.String())
+		}
+		obj, err := sandboxView.GetObject(typeinfo.ConfigMapsDescriptor.GVK, cache.NewObjectName("default", "cm"))
+		if err != nil {
+			t.Fatalf("step %d: failed to get config map: %v", i, err)
+		}
+		gotFields := make(map[string]string)
+		for _, entry := range obj.(*corev1.ConfigMap).ManagedFields {
+			if entry.Operation != metav1.ManagedFieldsOperationUpdate {
+				t.Errorf("step %d: expected operation %q for manager %q, got %q", i, metav1.ManagedFieldsOperationUpdate, entry.Manager, entry.Operation)
+			}
+			gotFields[entry.Manager] = string(entry.FieldsV1.Raw)
+		}
+		if diff := gocmp.Diff(step.wantFields, gotFields); diff != "" {
+			t.Errorf("step %d: managed fields mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const metricsNamespace = mkapi.ProgramName
+
+// otherLabelValue is the value of the resource and subresource labels of requests for resources and subresources that
+// are not served.
+const otherLabelValue = "other"
+
+var (
+	storeObjectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "store", "objects"),
+		"Number of objects held by the resource store of a view.",
+		[]string{"view", "resource"}, nil,
+	)
+	storeWatchersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "store", "watchers"),
+		"Number of active watches on the resource store of a view.",
+		[]string{"view", "resource"}, nil,
+	)
+	storeWatchQueueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "store", "watch_queue_depth"),
+		"Largest number of events queued for a single watcher of the resource store of a view.",
+		[]string{"view", "resource"}, nil,
+	)
+	storeDroppedWatchersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "store", "dropped_watchers_total"),
+		"Total number of watchers of the resource store of a view terminated since they lagged behind its changes.",
+		[]string{"view", "resource"}, nil,
+	)
+	sandboxViewsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sandbox_views"),
+		"Number of sandbox views.",
+		nil, nil,
+	)
+	sandboxViewAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sandbox_view_age_seconds"),
+		"Seconds since the creation of a sandbox view.",
+		[]string{"view"}, nil,
+	)
+)
+
+// serverMetrics holds the Prometheus metrics of a KAPI server which are served at /metrics. A dedicated registry is used
+// so that several servers can run within the same process.
+type serverMetrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func newServerMetrics(k *InMemoryKAPI) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Number of requests by view, verb, resource, subresource and HTTP response code.",
+		}, []string{"view", "verb", "resource", "subresource", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests by view, verb, resource and subresource. Watches are excluded since they are long-running.",
+			Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"view", "verb", "resource", "subresource"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		&viewCollector{k: k},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// handler returns the handler serving the metrics in the Prometheus exposition format.
+func (m *serverMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// instrument creates a middleware that records the count and latency of the requests served by next.
+func (m *serverMetrics) instrument(k *InMemoryKAPI, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		viewName, verb, resource, subresource := k.requestAttributes(r)
+		m.requests.WithLabelValues(viewName, verb, resource, subresource, strconv.Itoa(rec.statusCode())).Inc()
+		if verb != "watch" {
+			m.requestDuration.WithLabelValues(viewName, verb, resource, subresource).Observe(time.Since(start).Seconds())
+		}
+	})
+}
+
+// requestAttributes returns the view, verb, resource and subresource of the given request for labelling its metrics.
+// The view is empty for requests outside of any view and the resource and subresource are otherLabelValue if they are
+// not served by the view so that unknown paths do not create new label values. Like in the kube-apiserver, the
+// resource is qualified with its group and the verb distinguishes gets, lists and watches.
+func (k *InMemoryKAPI) requestAttributes(r *http.Request) (viewName, verb, resource, subresource string) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	v, ok := k.lookupView(segments[0])
+	if ok {
+		viewName = segments[0]
+		segments = segments[1:]
+	}
+	// segments are reduced to {resource}[/{name}[/{subresource}]] for resource requests.
+	var group string
+	switch {
+	case !ok:
+		segments = nil
+	case len(segments) > 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) > 3 && segments[0] == "apis":
+		group = segments[1]
+		segments = segments[3:]
+	default:
+		segments = nil
+	}
+	if len(segments) > 2 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+	if len(segments) > 0 {
+		d, found := findServedDescriptor(v, schema.GroupResource{Group: group, Resource: segments[0]})
+		resource = otherLabelValue
+		if found {
+			resource = d.GVR.GroupResource().String()
+		}
+		if len(segments) > 2 {
+			subresource = otherLabelValue
+			if found && slices.ContainsFunc(d.SubresourceAPIResources(), func(a metav1.APIResource) bool {
+				return a.Name == d.GVR.Resource+"/"+segments[2]
+			}) {
+				subresource = segments[2]
+			}
+		}
+	}
+	hasName := len(segments) > 1
+	switch r.Method {
+	case http.MethodGet:
+		verb = "get"
+		if resource != "" && !hasName {
+			if w := r.URL.Query().Get("watch"); w == "true" || w == "1" {
+				verb = "watch"
+			} else {
+				verb = "list"
+			}
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		verb = "delete"
+		if resource != "" && !hasName {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(r.Method)
+	}
+	return
+}
+
+// findServedDescriptor returns the descriptor of the given resource served by the given view and whether it was found.
+// The custom resource definitions of the view are only listed for resources that are not built-in.
+func findServedDescriptor(v mkapi.View, gr schema.GroupResource) (typeinfo.Descriptor, bool) {
+	isResource := func(d typeinfo.Descriptor) bool {
+		return d.GVR.GroupResource() == gr
+	}
+	if i := slices.IndexFunc(typeinfo.SupportedDescriptors, isResource); i >= 0 {
+		return typeinfo.SupportedDescriptors[i], true
+	}
+	// the lookup is best-effort: custom resources are labelled as unknown if their definitions cannot be listed.
+	customDescriptors, _ := view.CustomResourceDescriptors(v)
+	if i := slices.IndexFunc(customDescriptors, isResource); i >= 0 {
+		return customDescriptors[i], true
+	}
+	return typeinfo.Descriptor{}, false
+}
+
+// statusRecorder records the status code written to the wrapped http.ResponseWriter. It implements http.Flusher since
+// watches stream their events.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.code == 0 {
+		s.code = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(data []byte) (int, error) {
+	if s.code == 0 {
+		s.code = http.StatusOK
+	}
+	return s.ResponseWriter.Write(data)
+}
+
+func (s *statusRecorder) Flush() {
+	if s.code == 0 {
+		s.code = http.StatusOK
+	}
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) statusCode() int {
+	if s.code == 0 {
+		return http.StatusOK
+	}
+	return s.code
+}
+
+// viewCollector collects the statistics of the resource stores of all views and of the sandbox views at scrape time.
+type viewCollector struct {
+	k *InMemoryKAPI
+}
+
+var _ prometheus.Collector = (*viewCollector)(nil)
+
+func (c *viewCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- storeObjectsDesc
+	ch <- storeWatchersDesc
+	ch <- storeWatchQueueDepthDesc
+	ch <- storeDroppedWatchersDesc
+	ch <- sandboxViewsDesc
+	ch <- sandboxViewAgeDesc
+}
+
+func (c *viewCollector) Collect(ch chan<- prometheus.Metric) {
+	sandboxViews, creationTimes := c.k.listSandboxViews()
+	ch <- prometheus.MustNewConstMetric(sandboxViewsDesc, prometheus.GaugeValue, float64(len(sandboxViews)))
+	for i, v := range sandboxViews {
+		ch <- prometheus.MustNewConstMetric(sandboxViewAgeDesc, prometheus.GaugeValue, c.k.baseView.GetClock().Since(creationTimes[i]).Seconds(), v.GetName())
+	}
+	for _, v := range slices.Concat([]mkapi.View{c.k.baseView}, sandboxViews) {
+		c.collectStores(ch, v)
+	}
+}
+
+func (c *viewCollector) collectStores(ch chan<- prometheus.Metric, v mkapi.View) {
+	// the collection is best-effort: the stores of custom resources are skipped if their definitions cannot be listed.
+	customDescriptors, _ := view.CustomResourceDescriptors(v)
+	for _, d := range slices.Concat(typeinfo.SupportedDescriptors, customDescriptors) {
+		s, err := v.GetResourceStore(d.GVK)
+		if err != nil {
+			continue
+		}
+		stats := s.GetStats()
+		resource := d.GVR.GroupResource().String()
+		ch <- prometheus.MustNewConstMetric(storeObjectsDesc, prometheus.GaugeValue, float64(stats.ObjectCount), v.GetName(), resource)
+		ch <- prometheus.MustNewConstMetric(storeWatchersDesc, prometheus.GaugeValue, float64(stats.WatcherCount), v.GetName(), resource)
+		ch <- prometheus.MustNewConstMetric(storeWatchQueueDepthDesc, prometheus.GaugeValue, float64(stats.MaxWatchQueueDepth), v.GetName(), resource)
+		ch <- prometheus.MustNewConstMetric(storeDroppedWatchersDesc, prometheus.CounterValue, float64(stats.DroppedWatcherCount), v.GetName(), resource)
+	}
+}
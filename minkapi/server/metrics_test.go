@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRequestAttributes(t *testing.T) {
+	k := newMetricsTestServer(t)
+	sandboxView, err := k.GetSandboxView(context.Background(), "sb")
+	if err != nil {
+		t.Fatalf("failed to get sandbox view: %v", err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": {Type: "object"}},
+				}},
+			}},
+		},
+	}
+	if err = sandboxView.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, crd); err != nil {
+		t.Fatalf("failed to create CustomResourceDefinition: %v", err)
+	}
+	tests := map[string]struct {
+		method          string
+		target          string
+		wantView        string
+		wantVerb        string
+		wantResource    string
+		wantSubresource string
+	}{
+		"list core resource": {
+			method:       http.MethodGet,
+			target:       "/base/api/v1/namespaces/default/pods",
+			wantView:     "base",
+			wantVerb:     "list",
+			wantResource: "pods",
+		},
+		"watch grouped resource": {
+			method:       http.MethodGet,
+			target:       "/sb/apis/apps/v1/deployments?watch=true",
+			wantView:     "sb",
+			wantVerb:     "watch",
+			wantResource: "deployments.apps",
+		},
+		"get namespace": {
+			method:       http.MethodGet,
+			target:       "/base/api/v1/namespaces/default",
+			wantView:     "base",
+			wantVerb:     "get",
+			wantResource: "namespaces",
+		},
+		"create subresource": {
+			method:          http.MethodPost,
+			target:          "/base/api/v1/namespaces/default/pods/p1/binding",
+			wantView:        "base",
+			wantVerb:        "create",
+			wantResource:    "pods",
+			wantSubresource: "binding",
+		},
+		"delete collection": {
+			method:       http.MethodDelete,
+			target:       "/base/api/v1/namespaces/default/pods",
+			wantView:     "base",
+			wantVerb:     "deletecollection",
+			wantResource: "pods",
+		},
+		"discovery": {
+			method:   http.MethodGet,
+			target:   "/base/apis",
+			wantView: "base",
+			wantVerb: "get",
+		},
+		"custom resource": {
+			method:       http.MethodGet,
+			target:       "/sb/apis/example.com/v1/namespaces/default/widgets/w1",
+			wantView:     "sb",
+			wantVerb:     "get",
+			wantResource: "widgets.example.com",
+		},
+		"custom resource not defined in view": {
+			method:       http.MethodGet,
+			target:       "/base/apis/example.com/v1/namespaces/default/widgets/w1",
+			wantView:     "base",
+			wantVerb:     "get",
+			wantResource: "other",
+		},
+		"unknown subresource": {
+			method:          http.MethodGet,
+			target:          "/base/api/v1/namespaces/default/pods/p1/log",
+			wantView:        "base",
+			wantVerb:        "get",
+			wantResource:    "pods",
+			wantSubresource: "other",
+		},
+		"subresource not served for resource": {
+			method:          http.MethodPut,
+			target:          "/base/api/v1/namespaces/default/configmaps/c1/scale",
+			wantView:        "base",
+			wantVerb:        "update",
+			wantResource:    "configmaps",
+			wantSubresource: "other",
+		},
+		"unknown view": {
+			method:       http.MethodGet,
+			target:       "/other/api/v1/pods",
+			wantVerb:     "get",
+			wantResource: "",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+			viewName, verb, resource, subresource := k.requestAttributes(r)
+			if viewName != tc.wantView || verb != tc.wantVerb || resource != tc.wantResource || subresource != tc.wantSubresource {
+				t.Errorf("requestAttributes() = (%q, %q, %q, %q), want (%q, %q, %q, %q)", viewName, verb, resource, subresource,
+					tc.wantView, tc.wantVerb, tc.wantResource, tc.wantSubresource)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	k := newMetricsTestServer(t)
+	k.registerRoutes(logr.Discard(), http.NewServeMux(), k.baseView)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p1", Namespace: "default"}}
+	if err := k.baseView.CreateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	handler := k.metrics.instrument(k, k.rootMux)
+	for _, target := range []string{"/base/api/v1/namespaces/default/pods/p1", "/base/api/v1/namespaces/default/pods/p2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	if got := testutil.ToFloat64(k.metrics.requests.WithLabelValues("base", "get", "pods", "", "200")); got != 1 {
+		t.Errorf("expected 1 successful get request, got %v", got)
+	}
+	if got := testutil.ToFloat64(k.metrics.requests.WithLabelValues("base", "get", "pods", "", "404")); got != 1 {
+		t.Errorf("expected 1 get request not found, got %v", got)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d for metrics, got %d", http.StatusOK, w.Code)
+	}
+	for _, want := range []string{
+		`minkapi_store_objects{resource="pods",view="base"} 1`,
+		`minkapi_store_objects{resource="pods",view="sb"} 0`,
+		`minkapi_store_watchers{resource="pods",view="base"}`,
+		`minkapi_store_watch_queue_depth{resource="pods",view="base"}`,
+		`minkapi_store_dropped_watchers_total{resource="pods",view="base"}`,
+		`minkapi_sandbox_views 1`,
+		`minkapi_sandbox_view_age_seconds{view="sb"}`,
+		`minkapi_request_duration_seconds_count{resource="pods",subresource="",verb="get",view="base"} 2`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
+
+// newMetricsTestServer returns a KAPI server with a base view named "base" and a sandbox view named "sb".
+func newMetricsTestServer(t *testing.T) *InMemoryKAPI {
+	t.Helper()
+	kubeConfigPath := filepath.Join(t.TempDir(), "minkapi.yaml")
+	baseView, err := view.New(logr.Discard(), &mkapi.ViewArgs{
+		Name:           mkapi.DefaultBasePrefix,
+		KubeConfigPath: kubeConfigPath,
+		Scheme:         typeinfo.SupportedScheme,
+		WatchConfig:    mkapi.WatchConfig{QueueSize: mkapi.DefaultWatchQueueSize, Timeout: mkapi.DefaultWatchTimeout},
+	})
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	cfg := mkapi.Config{}
+	cfg.KubeConfigPath = kubeConfigPath
+	s, err := NewInMemoryUsingViews(cfg, baseView, view.NewSandbox)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	k := s.(*InMemoryKAPI)
+	sandboxView, err := k.GetSandboxView(context.Background(), "sb")
+	if err != nil {
+		t.Fatalf("failed to create sandbox view: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = sandboxView.Close()
+		_ = baseView.Close()
+	})
+	return k
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Mirror keeps the objects of a view in sync with those of a source cluster. It runs informers on the source cluster
+// for each mirrored kind and namespace, and replicates the objects they observe into the view.
+//
+// Mirrored objects keep the UID and creationTimestamp of the source cluster, but are assigned resource versions of the
+// view. Since the deletionTimestamp of an object cannot be changed by an update, the marking of an object for deletion
+// in the source cluster is not mirrored, only its eventual removal.
+type Mirror struct {
+	log       logr.Logger
+	view      mkapi.View
+	factories []dynamicinformer.DynamicSharedInformerFactory
+	informers []kindInformer
+	synced    atomic.Bool
+	// ctx is cancelled once the mirror is shut down, which stops the informers.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// kindInformer is an informer on the objects of a kind in a namespace of the source cluster, or in all namespaces if
+// the namespace is empty.
+type kindInformer struct {
+	d         typeinfo.Descriptor
+	namespace string
+	informer  cache.SharedIndexInformer
+}
+
+// New creates a Mirror of the source cluster of the given cfg into the given view.
+func New(cfg mkapi.MirrorConfig, v mkapi.View) (*Mirror, error) {
+	descriptors, err := selectDescriptors(cfg.Kinds)
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := clientcmd.BuildConfigFromFlags("", cfg.SourceKubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot load kubeconfig %q: %w", mkapi.ErrMirror, cfg.SourceKubeConfigPath, err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot create client: %w", mkapi.ErrMirror, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Mirror{
+		log:    logr.Discard(),
+		view:   v,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	namespaces := cfg.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	factories := make(map[string]dynamicinformer.DynamicSharedInformerFactory)
+	factoryOf := func(namespace string) dynamicinformer.DynamicSharedInformerFactory {
+		f, ok := factories[namespace]
+		if !ok {
+			f = dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, cfg.ResyncPeriod, namespace, nil)
+			factories[namespace] = f
+			m.factories = append(m.factories, f)
+		}
+		return f
+	}
+	for _, d := range descriptors {
+		kindNamespaces := namespaces
+		if !d.APIResource.Namespaced {
+			kindNamespaces = []string{metav1.NamespaceAll}
+		}
+		for _, ns := range kindNamespaces {
+			ki := kindInformer{d: d, namespace: ns, informer: factoryOf(ns).ForResource(d.GVR).Informer()}
+			if _, err = ki.informer.AddEventHandler(m.eventHandler(d)); err != nil {
+				return nil, fmt.Errorf("%w: cannot add event handler for %q: %w", mkapi.ErrMirror, d.GVR.GroupResource(), err)
+			}
+			m.informers = append(m.informers, ki)
+		}
+	}
+	return m, nil
+}
+
+// Start starts the informers on the source cluster and blocks until they have synced the view, after which the objects
+// of the mirrored kinds and namespaces that do not exist in the source cluster are removed from the view. The
+// informers keep the view in sync until the given ctx is cancelled or the mirror is shut down.
+func (m *Mirror) Start(ctx context.Context) error {
+	m.log = logr.FromContextOrDiscard(ctx).WithName("mirror")
+	context.AfterFunc(ctx, m.cancel)
+	for _, f := range m.factories {
+		f.Start(m.ctx.Done())
+	}
+	for _, f := range m.factories {
+		for gvr, ok := range f.WaitForCacheSync(m.ctx.Done()) {
+			if !ok {
+				return fmt.Errorf("%w: cannot sync informer of %q", mkapi.ErrMirror, gvr.GroupResource())
+			}
+		}
+	}
+	for _, ki := range m.informers {
+		if err := m.prune(ki); err != nil {
+			return err
+		}
+	}
+	m.synced.Store(true)
+	m.log.Info("synced view with source cluster", "view", m.view.GetName(), "numInformers", len(m.informers))
+	return nil
+}
+
+// HasSynced returns whether the view has been synced with the source cluster.
+func (m *Mirror) HasSynced() bool {
+	return m.synced.Load()
+}
+
+// Shutdown stops the informers on the source cluster and waits for them to terminate.
+func (m *Mirror) Shutdown() {
+	m.cancel()
+	for _, f := range m.factories {
+		f.Shutdown()
+	}
+}
+
+func (m *Mirror) eventHandler(d typeinfo.Descriptor) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			m.upsert(d, obj)
+		},
+		UpdateFunc: func(_, obj any) {
+			m.upsert(d, obj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				m.log.Error(fmt.Errorf("unexpected object of type %T", obj), "cannot mirror deletion", "gvk", d.GVK)
+				return
+			}
+			name := objutil.CacheName(u)
+			if err := m.remove(d, name); err != nil {
+				m.log.Error(err, "cannot mirror deletion", "gvk", d.GVK, "name", name)
+			}
+		},
+	}
+}
+
+// upsert creates or updates the given object of the source cluster in the view.
+func (m *Mirror) upsert(d typeinfo.Descriptor, obj any) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		m.log.Error(fmt.Errorf("unexpected object of type %T", obj), "cannot mirror object", "gvk", d.GVK)
+		return
+	}
+	name := objutil.CacheName(u)
+	mo, err := toViewObject(d, u)
+	if err != nil {
+		m.log.Error(err, "cannot mirror object", "gvk", d.GVK, "name", name)
+		return
+	}
+	_, err = m.view.GetObject(d.GVK, name)
+	switch {
+	case apierrors.IsNotFound(err):
+		err = m.view.CreateObject(d.GVK, mo)
+	case err == nil:
+		err = m.view.UpdateObject(d.GVK, mo)
+	}
+	if err != nil {
+		m.log.Error(err, "cannot mirror object", "gvk", d.GVK, "name", name)
+		return
+	}
+	m.log.V(4).Info("mirrored object", "gvk", d.GVK, "name", name)
+}
+
+// remove removes the object with the given name from the view regardless of its finalizers, since it no longer exists
+// in the source cluster. Its dependents are deleted by the garbage collector of the view like in the source cluster.
+func (m *Mirror) remove(d typeinfo.Descriptor, name cache.ObjectName) error {
+	obj, err := m.view.GetObject(d.GVK, name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	mo := obj.(metav1.Object)
+	if len(mo.GetFinalizers()) > 0 {
+		mo = obj.DeepCopyObject().(metav1.Object)
+		mo.SetFinalizers(nil)
+		mo.SetResourceVersion("")
+		// clearing the finalizers of an object marked for deletion removes it.
+		if err = m.view.UpdateObject(d.GVK, mo); err != nil {
+			return err
+		}
+	}
+	if err = m.view.DeleteObject(d.GVK, name, mkapi.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	m.log.V(4).Info("removed mirrored object", "gvk", d.GVK, "name", name)
+	return nil
+}
+
+// prune removes the objects observed by the given informer from the view that do not exist in the source cluster.
+func (m *Mirror) prune(ki kindInformer) error {
+	metaObjs, _, err := m.view.ListMetaObjects(ki.d.GVK, mkapi.MatchCriteria{Namespace: ki.namespace})
+	if err != nil {
+		return fmt.Errorf("%w: cannot list objects of %q: %w", mkapi.ErrMirror, ki.d.GVR.GroupResource(), err)
+	}
+	for _, mo := range metaObjs {
+		name := objutil.CacheName(mo)
+		if _, exists, _ := ki.informer.GetStore().GetByKey(name.String()); exists {
+			continue
+		}
+		if err = m.remove(ki.d, name); err != nil {
+			return fmt.Errorf("%w: cannot remove object %q of %q: %w", mkapi.ErrMirror, name, ki.d.GVR.GroupResource(), err)
+		}
+	}
+	return nil
+}
+
+// toViewObject converts the given object of the source cluster to an object of the kind described by the given
+// descriptor that can be stored in the view.
+func toViewObject(d typeinfo.Descriptor, u *unstructured.Unstructured) (metav1.Object, error) {
+	mo, err := d.CreateObject()
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), mo); err != nil {
+		return nil, fmt.Errorf("cannot convert %q to %T: %w", objutil.CacheName(u), mo, err)
+	}
+	objutil.SetMetaObjectGVK(mo, d.GVK)
+	// the resource versions of the source cluster are unrelated to those of the view.
+	mo.SetResourceVersion("")
+	return mo, nil
+}
+
+// selectDescriptors returns the descriptors of the built-in kinds with the given resource names or kinds, or of all
+// built-in kinds if none are given.
+func selectDescriptors(kinds []string) ([]typeinfo.Descriptor, error) {
+	if len(kinds) == 0 {
+		return typeinfo.SupportedDescriptors, nil
+	}
+	descriptors := make([]typeinfo.Descriptor, 0, len(kinds))
+	for _, k := range kinds {
+		i := slices.IndexFunc(typeinfo.SupportedDescriptors, func(d typeinfo.Descriptor) bool {
+			return strings.EqualFold(d.GVR.Resource, k) || strings.EqualFold(d.GVK.Kind, k) || d.GVR.GroupResource().String() == k
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unsupported kind %q", mkapi.ErrMirror, k)
+		}
+		if d := typeinfo.SupportedDescriptors[i]; !slices.ContainsFunc(descriptors, func(s typeinfo.Descriptor) bool { return s.GVK == d.GVK }) {
+			descriptors = append(descriptors, d)
+		}
+	}
+	return descriptors, nil
+}
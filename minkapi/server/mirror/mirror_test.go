@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mirror
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSelectDescriptors(t *testing.T) {
+	tests := map[string]struct {
+		kinds    []string
+		wantGVKs []schema.GroupVersionKind
+		wantErr  error
+	}{
+		"all kinds": {
+			wantGVKs: descriptorGVKs(typeinfo.SupportedDescriptors),
+		},
+		"resource names and kinds": {
+			kinds:    []string{"pods", "Node", "deployments.apps"},
+			wantGVKs: descriptorGVKs([]typeinfo.Descriptor{typeinfo.PodsDescriptor, typeinfo.NodesDescriptor, typeinfo.DeploymentDescriptor}),
+		},
+		"duplicate kinds": {
+			kinds:    []string{"pods", "pod"},
+			wantGVKs: descriptorGVKs([]typeinfo.Descriptor{typeinfo.PodsDescriptor}),
+		},
+		"unsupported kind": {
+			kinds:   []string{"widgets"},
+			wantErr: mkapi.ErrMirror,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			descriptors, err := selectDescriptors(tc.kinds)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got := descriptorGVKs(descriptors); !slices.Equal(got, tc.wantGVKs) {
+				t.Errorf("expected kinds %v, got %v", tc.wantGVKs, got)
+			}
+		})
+	}
+}
+
+func descriptorGVKs(descriptors []typeinfo.Descriptor) (gvks []schema.GroupVersionKind) {
+	for _, d := range descriptors {
+		gvks = append(gvks, d.GVK)
+	}
+	return
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/configtmpl"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestMirror(t *testing.T) {
+	source, sourceView := newSubresourceTestServer(t)
+	ts := httptest.NewServer(source.rootMux)
+	t.Cleanup(ts.Close)
+	sourceKubeConfigPath := filepath.Join(t.TempDir(), "source.yaml")
+	if err := configtmpl.GenKubeConfig(source.security.kubeConfigParams(sourceView.GetName(), sourceKubeConfigPath, ts.URL+"/"+sourceView.GetName())); err != nil {
+		t.Fatalf("failed to generate kubeconfig of source: %v", err)
+	}
+	webPod := newCollectionTestPod("default", "web-0", "web")
+	for _, pod := range []*corev1.Pod{webPod, newCollectionTestPod("other", "web-0", "web")} {
+		if err := sourceView.CreateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+			t.Fatalf("failed to create pod in source: %v", err)
+		}
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	if err := sourceView.CreateObject(typeinfo.NodesDescriptor.GVK, node); err != nil {
+		t.Fatalf("failed to create node in source: %v", err)
+	}
+
+	baseView, err := view.New(logr.Discard(), &mkapi.ViewArgs{
+		Name:           mkapi.DefaultBasePrefix,
+		KubeConfigPath: filepath.Join(t.TempDir(), "minkapi.yaml"),
+		Scheme:         typeinfo.SupportedScheme,
+		WatchConfig:    mkapi.WatchConfig{QueueSize: mkapi.DefaultWatchQueueSize, Timeout: mkapi.DefaultWatchTimeout},
+	})
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() { _ = baseView.Close() })
+	stalePod := newCollectionTestPod("default", "stale", "web")
+	stalePod.Finalizers = []string{"example.com/protect"}
+	if err = baseView.CreateObject(typeinfo.PodsDescriptor.GVK, stalePod); err != nil {
+		t.Fatalf("failed to create stale pod: %v", err)
+	}
+	cfg := mkapi.Config{
+		MirrorConfig: mkapi.MirrorConfig{
+			SourceKubeConfigPath: sourceKubeConfigPath,
+			Kinds:                []string{"pods", "Node"},
+			Namespaces:           []string{"default"},
+		},
+	}
+	s, err := NewInMemoryUsingViews(cfg, baseView, view.NewSandbox)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	k := s.(*InMemoryKAPI)
+	t.Cleanup(k.mirror.Shutdown)
+	if w := serveSubresourceRequest(k, http.MethodGet, "/readyz/mirror", "", ""); w.Code != http.StatusInternalServerError {
+		t.Errorf("expected base view not to be ready before sync, got status code %d", w.Code)
+	}
+	if k.HasBaseViewSynced() {
+		t.Errorf("expected base view not to be synced before sync")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = k.mirror.Start(ctx); err != nil {
+		t.Fatalf("failed to start mirror: %v", err)
+	}
+	if w := serveSubresourceRequest(k, http.MethodGet, "/readyz/mirror", "", ""); w.Code != http.StatusOK {
+		t.Errorf("expected base view to be ready after sync, got status code %d", w.Code)
+	}
+	if !k.HasBaseViewSynced() {
+		t.Errorf("expected base view to be synced after sync")
+	}
+
+	tests := map[string]struct {
+		desc       typeinfo.Descriptor
+		name       cache.ObjectName
+		wantExists bool
+	}{
+		"pod of mirrored namespace": {
+			desc:       typeinfo.PodsDescriptor,
+			name:       cache.NewObjectName("default", "web-0"),
+			wantExists: true,
+		},
+		"pod of other namespace": {
+			desc: typeinfo.PodsDescriptor,
+			name: cache.NewObjectName("other", "web-0"),
+		},
+		"stale pod": {
+			desc: typeinfo.PodsDescriptor,
+			name: objutil.CacheName(stalePod),
+		},
+		"node": {
+			desc:       typeinfo.NodesDescriptor,
+			name:       cache.NewObjectName("", "node-a"),
+			wantExists: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj, err := baseView.GetObject(tc.desc.GVK, tc.name)
+			if exists := err == nil; exists != tc.wantExists {
+				t.Fatalf("expected %s %q to exist %t, got %v", tc.desc.Kind, tc.name, tc.wantExists, err)
+			}
+			if tc.wantExists && obj.(metav1.Object).GetUID() == "" {
+				t.Errorf("expected mirrored %s %q to keep its UID", tc.desc.Kind, tc.name)
+			}
+		})
+	}
+
+	webPod.Labels["tier"] = "frontend"
+	webPod.ResourceVersion = ""
+	if err = sourceView.UpdateObject(typeinfo.PodsDescriptor.GVK, webPod); err != nil {
+		t.Fatalf("failed to update pod in source: %v", err)
+	}
+	waitForMirror(t, "update of pod", func() bool {
+		obj, err := baseView.GetObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(webPod))
+		return err == nil && obj.(*corev1.Pod).Labels["tier"] == "frontend"
+	})
+	if err = sourceView.DeleteObject(typeinfo.NodesDescriptor.GVK, objutil.CacheName(node), mkapi.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete node in source: %v", err)
+	}
+	waitForMirror(t, "deletion of node", func() bool {
+		_, err := baseView.GetObject(typeinfo.NodesDescriptor.GVK, objutil.CacheName(node))
+		return apierrors.IsNotFound(err)
+	})
+}
+
+func waitForMirror(t *testing.T, change string, condition func() bool) {
+	t.Helper()
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return condition(), nil
+	})
+	if err != nil {
+		t.Errorf("expected %s to be mirrored: %v", change, err)
+	}
+}
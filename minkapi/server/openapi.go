@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/openapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kube-openapi/pkg/handler"
+	"k8s.io/kube-openapi/pkg/handler3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+// openAPIService serves the OpenAPI v2 document at /openapi/v2 and the OpenAPI v3 documents of the group versions at
+// /openapi/v3 for the built-in kinds and the custom resources defined in a view. Like the kube-apiserver, it relies on
+// the handlers of kube-openapi for content negotiation and caching, and only rebuilds the documents when the custom
+// resources change.
+type openAPIService struct {
+	view mkapi.View
+	v2   *handler.OpenAPIService
+	v3   *handler3.OpenAPIService
+	mux  *http.ServeMux
+	// mu guards fingerprint and groupVersions.
+	mu            sync.Mutex
+	fingerprint   string
+	groupVersions sets.Set[string]
+}
+
+func newOpenAPIService(v mkapi.View) *openAPIService {
+	o := &openAPIService{
+		view:          v,
+		v2:            handler.NewOpenAPIService(&spec.Swagger{}),
+		v3:            handler3.NewOpenAPIService(),
+		mux:           http.NewServeMux(),
+		groupVersions: sets.New[string](),
+	}
+	o.v2.RegisterOpenAPIVersionedService("/openapi/v2", o.mux)
+	o.mux.HandleFunc("/openapi/v3", o.v3.HandleDiscovery)
+	o.mux.HandleFunc("/openapi/v3/", o.handleGroupVersion)
+	return o
+}
+
+func (o *openAPIService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := o.sync(); err != nil {
+		handleError(w, r, err)
+		return
+	}
+	o.mux.ServeHTTP(w, r)
+}
+
+// handleGroupVersion serves the OpenAPI v3 document of a group version, which the kube-openapi handler does not report
+// as not found.
+func (o *openAPIService) handleGroupVersion(w http.ResponseWriter, r *http.Request) {
+	gvPath := strings.TrimPrefix(r.URL.Path, "/openapi/v3/")
+	o.mu.Lock()
+	found := o.groupVersions.Has(gvPath)
+	o.mu.Unlock()
+	if !found {
+		handleStatusError(w, r, apierrors.NewGenericServerResponse(http.StatusNotFound, r.Method, schema.GroupResource{}, "", "", 0, false))
+		return
+	}
+	o.v3.HandleGroupVersion(w, r)
+}
+
+// sync rebuilds the OpenAPI documents if the custom resources of the view changed since they were last built.
+func (o *openAPIService) sync() error {
+	customDescriptors, err := view.CustomResourceDescriptors(o.view)
+	if err != nil {
+		return err
+	}
+	descriptors := slices.Concat(typeinfo.SupportedDescriptors, customDescriptors)
+	fingerprint, err := openapi.Fingerprint(descriptors)
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if fingerprint == o.fingerprint {
+		return nil
+	}
+	v2, err := openapi.BuildV2(descriptors)
+	if err != nil {
+		return err
+	}
+	v3, err := openapi.BuildV3(descriptors)
+	if err != nil {
+		return err
+	}
+	if err = o.v2.UpdateSpec(v2); err != nil {
+		return err
+	}
+	for gvPath, doc := range v3 {
+		o.v3.UpdateGroupVersion(gvPath, doc)
+	}
+	for gvPath := range o.groupVersions {
+		if _, ok := v3[gvPath]; !ok {
+			o.v3.DeleteGroupVersion(gvPath)
+		}
+	}
+	o.groupVersions = sets.KeySet(v3)
+	o.fingerprint = fingerprint
+	return nil
+}
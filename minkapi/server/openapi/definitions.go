@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/util"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	// v2RefPrefix is the prefix of references to the definitions of an OpenAPI v2 document.
+	v2RefPrefix = "#/definitions/"
+	// v3RefPrefix is the prefix of references to the component schemas of an OpenAPI v3 document.
+	v3RefPrefix = "#/components/schemas/"
+
+	extensionGVK                  = "x-kubernetes-group-version-kind"
+	extensionPatchStrategy        = "x-kubernetes-patch-strategy"
+	extensionPatchMergeKey        = "x-kubernetes-patch-merge-key"
+	extensionPreserveUnknownField = "x-kubernetes-preserve-unknown-fields"
+)
+
+// openAPISchemaTyper is implemented by the types of the API that are not serialized like their Go type, such as
+// metav1.Time and resource.Quantity.
+type openAPISchemaTyper interface {
+	OpenAPISchemaType() []string
+	OpenAPISchemaFormat() string
+}
+
+// openAPIV3OneOfTyper is implemented by the types of the API that are serialized as one of several JSON types, such as
+// intstr.IntOrString.
+type openAPIV3OneOfTyper interface {
+	OpenAPIV3OneOfTypes() []string
+}
+
+// swaggerDocer is implemented by the types of the API, whose SwaggerDoc holds the descriptions of the type and its
+// fields keyed by their JSON names, and the description of the type itself keyed by the empty string.
+type swaggerDocer interface {
+	SwaggerDoc() map[string]string
+}
+
+// definitions collects the schemas of the types of the API, named like the definitions of the kube-apiserver. Since the
+// Go types of minkapi are not accompanied by generated OpenAPI definitions, the schemas are derived from the Go types
+// themselves through reflection along with their SwaggerDoc descriptions.
+type definitions struct {
+	// refPrefix prefixes the names of the definitions in references to them.
+	refPrefix string
+	// v3 is true if the schemas are OpenAPI v3 schemas, which can describe values of several JSON types.
+	v3      bool
+	schemas map[string]spec.Schema
+}
+
+func newDefinitions(v3 bool) *definitions {
+	d := &definitions{refPrefix: v2RefPrefix, v3: v3, schemas: make(map[string]spec.Schema)}
+	if v3 {
+		d.refPrefix = v3RefPrefix
+	}
+	return d
+}
+
+// definitionName returns the name of the definition of the given named Go type, such as io.k8s.api.core.v1.Pod for
+// corev1.Pod.
+func definitionName(t reflect.Type) string {
+	return util.ToRESTFriendlyName(t.PkgPath() + "." + t.Name())
+}
+
+// customResourceDefinitionName returns the name of the definition of the custom resource of the given gvk, such as
+// com.example.v1.Widget for the Widget kind of the example.com/v1 group version.
+func customResourceDefinitionName(gvk schema.GroupVersionKind) string {
+	return util.ToRESTFriendlyName(gvk.GroupVersion().String() + "." + gvk.Kind)
+}
+
+func (d *definitions) refSchema(name string) spec.Schema {
+	return *spec.RefSchema(d.refPrefix + name)
+}
+
+// add adds the definition of the given Go type of an object along with the definitions of the types it refers to, and
+// returns its name. The given gvks are recorded in the definition, so that clients can look it up by kind.
+func (d *definitions) add(t reflect.Type, gvks ...schema.GroupVersionKind) string {
+	d.schemaOf(t)
+	name := definitionName(t)
+	d.setGVKs(name, gvks...)
+	return name
+}
+
+// setGVKs records the given gvks in the definition of the given name.
+func (d *definitions) setGVKs(name string, gvks ...schema.GroupVersionKind) {
+	if len(gvks) == 0 {
+		return
+	}
+	s := d.schemas[name]
+	existing, _ := s.Extensions[extensionGVK].([]any)
+	for _, gvk := range gvks {
+		existing = append(existing, map[string]any{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind})
+	}
+	s.AddExtension(extensionGVK, existing)
+	d.schemas[name] = s
+}
+
+// schemaOf returns the schema of values of the given Go type. Structs are referenced through their definitions, which
+// are added if missing.
+func (d *definitions) schemaOf(t reflect.Type) spec.Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return *spec.BooleanProperty()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return *spec.Int32Property()
+	case reflect.Int64, reflect.Uint64:
+		return *spec.Int64Property()
+	case reflect.Int, reflect.Uint:
+		return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"integer"}}}
+	case reflect.Float32, reflect.Float64:
+		return *spec.Float64Property()
+	case reflect.String:
+		return *spec.StringProperty()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are serialized as base64 encoded strings.
+			return spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{"string"}, Format: "byte"}}
+		}
+		items := d.schemaOf(t.Elem())
+		return *spec.ArrayProperty(&items)
+	case reflect.Map:
+		values := d.schemaOf(t.Elem())
+		return *spec.MapProperty(&values)
+	case reflect.Struct:
+		name := definitionName(t)
+		if _, ok := d.schemas[name]; !ok {
+			// the definition is reserved before walking the fields, since types such as JSONSchemaProps refer to themselves.
+			d.schemas[name] = spec.Schema{}
+			d.schemas[name] = d.structSchema(t)
+		}
+		return d.refSchema(name)
+	default:
+		// interfaces such as runtime.Object hold values of any type.
+		return spec.Schema{}
+	}
+}
+
+// structSchema returns the schema of the definition of the given struct type.
+func (d *definitions) structSchema(t reflect.Type) spec.Schema {
+	var s spec.Schema
+	ptr := reflect.New(t).Interface()
+	if docer, ok := ptr.(swaggerDocer); ok {
+		s.Description = docer.SwaggerDoc()[""]
+	}
+	if typer, ok := ptr.(openAPISchemaTyper); ok {
+		if oneOfTyper, ok := ptr.(openAPIV3OneOfTyper); ok && d.v3 {
+			for _, typ := range oneOfTyper.OpenAPIV3OneOfTypes() {
+				s.OneOf = append(s.OneOf, spec.Schema{SchemaProps: spec.SchemaProps{Type: []string{typ}}})
+			}
+		} else {
+			s.Type = typer.OpenAPISchemaType()
+		}
+		s.Format = typer.OpenAPISchemaFormat()
+		return s
+	}
+	s.Type = []string{"object"}
+	d.addProperties(&s, t)
+	return s
+}
+
+// addProperties adds the properties of the fields of the given struct type to the given schema, including those of the
+// structs it embeds inline.
+func (d *definitions) addProperties(s *spec.Schema, t reflect.Type) {
+	var docs map[string]string
+	if docer, ok := reflect.New(t).Interface().(swaggerDocer); ok {
+		docs = docer.SwaggerDoc()
+	}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			if ft := f.Type; ft.Kind() == reflect.Struct {
+				d.addProperties(s, ft)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := d.withDescription(d.schemaOf(f.Type), docs[name])
+		if strategy := f.Tag.Get("patchStrategy"); strategy != "" {
+			prop.AddExtension(extensionPatchStrategy, strategy)
+		}
+		if key := f.Tag.Get("patchMergeKey"); key != "" {
+			prop.AddExtension(extensionPatchMergeKey, key)
+		}
+		s.SetProperty(name, prop)
+		// like the kube-apiserver, fields are required unless they are optional, which the API conventions express with
+		// omitempty or pointers.
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// withDescription returns the given schema of a property with the given description. Since properties of OpenAPI v3
+// cannot describe a reference, references are wrapped in an allOf like the kube-apiserver does.
+func (d *definitions) withDescription(s spec.Schema, description string) spec.Schema {
+	if description == "" {
+		return s
+	}
+	if s.Ref.String() != "" && d.v3 {
+		return spec.Schema{SchemaProps: spec.SchemaProps{AllOf: []spec.Schema{s}, Description: description}}
+	}
+	s.Description = description
+	return s
+}
+
+// addCustomResource adds the definitions of the custom resource of the given gvk and its list, whose objects are
+// validated by the given structural schema of their CustomResourceDefinition, and returns their names. Custom
+// resources without a schema preserve unknown fields.
+func (d *definitions) addCustomResource(gvk, listGVK schema.GroupVersionKind, crSchema *apiextensionsv1.JSONSchemaProps) (name, listName string, err error) {
+	var s spec.Schema
+	if crSchema != nil {
+		data, err := json.Marshal(crSchema)
+		if err != nil {
+			return "", "", err
+		}
+		if err = json.Unmarshal(data, &s); err != nil {
+			return "", "", fmt.Errorf("cannot convert schema of custom resource %q: %w", gvk, err)
+		}
+	} else {
+		s.Type = []string{"object"}
+		s.AddExtension(extensionPreserveUnknownField, true)
+	}
+	typeMetaDocs := metav1.TypeMeta{}.SwaggerDoc()
+	s.SetProperty("apiVersion", *spec.StringProperty().WithDescription(typeMetaDocs["apiVersion"]))
+	s.SetProperty("kind", *spec.StringProperty().WithDescription(typeMetaDocs["kind"]))
+	s.SetProperty("metadata", d.withDescription(d.schemaOf(reflect.TypeFor[metav1.ObjectMeta]()), "Standard object's metadata. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata"))
+	name = customResourceDefinitionName(gvk)
+	d.schemas[name] = s
+	d.setGVKs(name, gvk)
+
+	list := spec.Schema{SchemaProps: spec.SchemaProps{
+		Description: fmt.Sprintf("%s is a list of %s", listGVK.Kind, gvk.Kind),
+		Type:        []string{"object"},
+		Required:    []string{"items"},
+	}}
+	list.SetProperty("apiVersion", *spec.StringProperty().WithDescription(typeMetaDocs["apiVersion"]))
+	list.SetProperty("kind", *spec.StringProperty().WithDescription(typeMetaDocs["kind"]))
+	list.SetProperty("metadata", d.withDescription(d.schemaOf(reflect.TypeFor[metav1.ListMeta]()), "Standard list metadata. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds"))
+	items := d.refSchema(name)
+	list.SetProperty("items", *spec.ArrayProperty(&items).WithDescription(fmt.Sprintf("List of %s.", strings.ToLower(gvk.Kind))))
+	listName = customResourceDefinitionName(listGVK)
+	d.schemas[listName] = list
+	d.setGVKs(listName, listGVK)
+	return
+}
@@ -0,0 +1,603 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openapi builds the OpenAPI v2 and v3 documents of the kinds served by minkapi, which clients such as kubectl
+// rely on for kubectl explain, client-side validation and strategic merge patches.
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const extensionAction = "x-kubernetes-action"
+
+var (
+	// produces are the media types of the responses of minkapi.
+	produces = []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf}
+	// patchConsumes are the media types of the patches accepted by minkapi.
+	patchConsumes = []string{string(types.JSONPatchType), string(types.MergePatchType), string(types.StrategicMergePatchType), string(types.ApplyYAMLPatchType)}
+	// statusPatchConsumes are the media types of the patches of the status subresource, which cannot be applied.
+	statusPatchConsumes = patchConsumes[:3]
+
+	listParameters = optionParameters(reflect.TypeFor[metav1.ListOptions](), "labelSelector", "fieldSelector", "limit",
+		"continue", "resourceVersion", "resourceVersionMatch", "watch", "allowWatchBookmarks", "sendInitialEvents")
+	patchParameters  = optionParameters(reflect.TypeFor[metav1.PatchOptions](), "fieldManager", "force")
+	deleteParameters = optionParameters(reflect.TypeFor[metav1.DeleteOptions](), "gracePeriodSeconds", "propagationPolicy")
+	// deleteCollectionParameters are the query parameters of a collection delete, which selects the objects to delete.
+	deleteCollectionParameters = slices.Concat(optionParameters(reflect.TypeFor[metav1.ListOptions](), "labelSelector", "fieldSelector"), deleteParameters)
+
+	namespaceParameter = parameter{
+		name:        "namespace",
+		in:          "path",
+		description: "object name and auth scope, such as for teams and projects",
+		required:    true,
+		schema:      *spec.StringProperty(),
+	}
+)
+
+// parameter is a path or query parameter of an operation.
+type parameter struct {
+	name        string
+	in          string
+	description string
+	required    bool
+	schema      spec.Schema
+}
+
+// optionParameters returns the query parameters of the fields of the given options type with the given JSON names,
+// described by the SwaggerDoc of the options.
+func optionParameters(t reflect.Type, names ...string) []parameter {
+	docs := reflect.New(t).Interface().(swaggerDocer).SwaggerDoc()
+	defs := newDefinitions(false)
+	params := make([]parameter, 0, len(names))
+	for _, name := range names {
+		for i := range t.NumField() {
+			if f := t.Field(i); strings.Split(f.Tag.Get("json"), ",")[0] == name {
+				params = append(params, parameter{name: name, in: "query", description: docs[name], schema: defs.schemaOf(f.Type)})
+				break
+			}
+		}
+	}
+	return params
+}
+
+// operation is an operation on a path of a kind.
+type operation struct {
+	method      string
+	action      string
+	id          string
+	description string
+	query       []parameter
+	consumes    []string
+	// body is the name of the definition of the request body, if any.
+	body string
+	// responses are the names of the definitions of the responses keyed by their status codes.
+	responses map[int]string
+}
+
+// route is a path of a kind along with its operations.
+type route struct {
+	path   string
+	params []parameter
+	gvk    schema.GroupVersionKind
+	ops    []operation
+}
+
+// kindDefinitions are the names of the definitions referred to by the operations of a kind.
+type kindDefinitions struct {
+	object, list, status, deleteOptions, patch, binding, eviction, scale string
+}
+
+// addKind adds the definitions referred to by the operations of the kind described by the given descriptor.
+func (d *definitions) addKind(desc typeinfo.Descriptor) (names kindDefinitions, err error) {
+	if typeinfo.SupportedScheme.Recognizes(desc.GVK) {
+		obj, err := typeinfo.SupportedScheme.New(desc.GVK)
+		if err != nil {
+			return names, err
+		}
+		list, err := typeinfo.SupportedScheme.New(desc.ListGVK)
+		if err != nil {
+			return names, err
+		}
+		names.object = d.add(reflect.TypeOf(obj).Elem(), desc.GVK)
+		names.list = d.add(reflect.TypeOf(list).Elem(), desc.ListGVK)
+	} else if names.object, names.list, err = d.addCustomResource(desc.GVK, desc.ListGVK, desc.OpenAPIV3Schema); err != nil {
+		return
+	}
+	statusType := reflect.TypeFor[metav1.Status]()
+	if _, ok := d.schemas[definitionName(statusType)]; !ok {
+		d.add(statusType, metav1.SchemeGroupVersion.WithKind("Status"))
+	}
+	names.status = definitionName(statusType)
+	names.deleteOptions = d.add(reflect.TypeFor[metav1.DeleteOptions]())
+	names.patch = d.add(reflect.TypeFor[metav1.Patch]())
+	if desc.GVK == typeinfo.PodsDescriptor.GVK {
+		names.binding = d.add(reflect.TypeFor[corev1.Binding](), corev1.SchemeGroupVersion.WithKind("Binding"))
+		names.eviction = d.add(reflect.TypeFor[policyv1.Eviction](), policyv1.SchemeGroupVersion.WithKind("Eviction"))
+	}
+	if desc.ScaleSubresource {
+		names.scale = d.add(reflect.TypeFor[autoscalingv1.Scale](), autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+	}
+	return
+}
+
+// operationGroupVersion returns the group version of the given gv as it appears in the IDs of operations, such as
+// CoreV1 for v1 and RbacAuthorizationV1 for rbac.authorization.k8s.io/v1.
+func operationGroupVersion(gv schema.GroupVersion) string {
+	group := strings.TrimSuffix(gv.Group, ".k8s.io")
+	if group == "" {
+		group = "core"
+	}
+	var b strings.Builder
+	for part := range strings.SplitSeq(group, ".") {
+		b.WriteString(capitalize(part))
+	}
+	b.WriteString(capitalize(gv.Version))
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// groupVersionPath returns the path at which the given gv is served, such as /api/v1 or /apis/apps/v1.
+func groupVersionPath(gv schema.GroupVersion) string {
+	if gv.Group == "" {
+		return "/api/" + gv.Version
+	}
+	return "/apis/" + gv.String()
+}
+
+// routesOf returns the routes served for the kind described by the given descriptor, whose operations refer to the
+// definitions of the given names.
+func routesOf(desc typeinfo.Descriptor, names kindDefinitions) []route {
+	var (
+		kind       = string(desc.Kind)
+		idPrefix   = operationGroupVersion(desc.GVK.GroupVersion())
+		idKind     = kind
+		collection = groupVersionPath(desc.GVK.GroupVersion())
+		params     []parameter
+		routes     []route
+	)
+	if desc.APIResource.Namespaced {
+		routes = append(routes, route{
+			path: collection + "/" + desc.GVR.Resource,
+			gvk:  desc.GVK,
+			ops: []operation{{
+				method:      http.MethodGet,
+				action:      "list",
+				id:          "list" + idPrefix + kind + "ForAllNamespaces",
+				description: fmt.Sprintf("list or watch objects of kind %s", kind),
+				query:       listParameters,
+				responses:   map[int]string{http.StatusOK: names.list},
+			}},
+		})
+		idKind = "Namespaced" + kind
+		collection += "/namespaces/{namespace}"
+		params = append(params, namespaceParameter)
+	}
+	collection += "/" + desc.GVR.Resource
+	routes = append(routes, route{
+		path:   collection,
+		params: params,
+		gvk:    desc.GVK,
+		ops: []operation{
+			{
+				method:      http.MethodGet,
+				action:      "list",
+				id:          "list" + idPrefix + idKind,
+				description: fmt.Sprintf("list or watch objects of kind %s", kind),
+				query:       listParameters,
+				responses:   map[int]string{http.StatusOK: names.list},
+			},
+			{
+				method:      http.MethodPost,
+				action:      "post",
+				id:          "create" + idPrefix + idKind,
+				description: fmt.Sprintf("create a %s", kind),
+				consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+				body:        names.object,
+				responses:   map[int]string{http.StatusOK: names.object, http.StatusCreated: names.object},
+			},
+			{
+				method:      http.MethodDelete,
+				action:      "deletecollection",
+				id:          "deletecollection" + idPrefix + idKind,
+				description: fmt.Sprintf("delete collection of %s", kind),
+				query:       deleteCollectionParameters,
+				consumes:    []string{runtime.ContentTypeJSON},
+				body:        names.deleteOptions,
+				responses:   map[int]string{http.StatusOK: names.list},
+			},
+		},
+	})
+
+	item := collection + "/{name}"
+	params = append(params, parameter{
+		name:        "name",
+		in:          "path",
+		description: fmt.Sprintf("name of the %s", kind),
+		required:    true,
+		schema:      *spec.StringProperty(),
+	})
+	routes = append(routes, route{
+		path:   item,
+		params: params,
+		gvk:    desc.GVK,
+		ops: []operation{
+			{
+				method:      http.MethodGet,
+				action:      "get",
+				id:          "read" + idPrefix + idKind,
+				description: fmt.Sprintf("read the specified %s", kind),
+				responses:   map[int]string{http.StatusOK: names.object},
+			},
+			{
+				method:      http.MethodPut,
+				action:      "put",
+				id:          "replace" + idPrefix + idKind,
+				description: fmt.Sprintf("replace the specified %s", kind),
+				consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+				body:        names.object,
+				responses:   map[int]string{http.StatusOK: names.object},
+			},
+			{
+				method:      http.MethodPatch,
+				action:      "patch",
+				id:          "patch" + idPrefix + idKind,
+				description: fmt.Sprintf("partially update the specified %s", kind),
+				query:       patchParameters,
+				consumes:    patchConsumes,
+				body:        names.patch,
+				responses:   map[int]string{http.StatusOK: names.object},
+			},
+			{
+				method:      http.MethodDelete,
+				action:      "delete",
+				id:          "delete" + idPrefix + idKind,
+				description: fmt.Sprintf("delete a %s", kind),
+				query:       deleteParameters,
+				consumes:    []string{runtime.ContentTypeJSON},
+				body:        names.deleteOptions,
+				responses:   map[int]string{http.StatusOK: names.status, http.StatusAccepted: names.object},
+			},
+		},
+	})
+
+	if desc.StatusSubresource {
+		routes = append(routes, route{
+			path:   item + "/status",
+			params: params,
+			gvk:    desc.GVK,
+			ops: []operation{
+				{
+					method:      http.MethodGet,
+					action:      "get",
+					id:          "read" + idPrefix + idKind + "Status",
+					description: fmt.Sprintf("read status of the specified %s", kind),
+					responses:   map[int]string{http.StatusOK: names.object},
+				},
+				{
+					method:      http.MethodPut,
+					action:      "put",
+					id:          "replace" + idPrefix + idKind + "Status",
+					description: fmt.Sprintf("replace status of the specified %s", kind),
+					consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+					body:        names.object,
+					responses:   map[int]string{http.StatusOK: names.object},
+				},
+				{
+					method:      http.MethodPatch,
+					action:      "patch",
+					id:          "patch" + idPrefix + idKind + "Status",
+					description: fmt.Sprintf("partially update status of the specified %s", kind),
+					consumes:    statusPatchConsumes,
+					body:        names.patch,
+					responses:   map[int]string{http.StatusOK: names.object},
+				},
+			},
+		})
+	}
+	if names.scale != "" {
+		routes = append(routes, route{
+			path:   item + "/scale",
+			params: params,
+			gvk:    autoscalingv1.SchemeGroupVersion.WithKind("Scale"),
+			ops: []operation{
+				{
+					method:      http.MethodGet,
+					action:      "get",
+					id:          "read" + idPrefix + idKind + "Scale",
+					description: fmt.Sprintf("read scale of the specified %s", kind),
+					responses:   map[int]string{http.StatusOK: names.scale},
+				},
+				{
+					method:      http.MethodPut,
+					action:      "put",
+					id:          "replace" + idPrefix + idKind + "Scale",
+					description: fmt.Sprintf("replace scale of the specified %s", kind),
+					consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+					body:        names.scale,
+					responses:   map[int]string{http.StatusOK: names.scale},
+				},
+				{
+					method:      http.MethodPatch,
+					action:      "patch",
+					id:          "patch" + idPrefix + idKind + "Scale",
+					description: fmt.Sprintf("partially update scale of the specified %s", kind),
+					consumes:    statusPatchConsumes,
+					body:        names.patch,
+					responses:   map[int]string{http.StatusOK: names.scale},
+				},
+			},
+		})
+	}
+	if names.binding != "" {
+		routes = append(routes, route{
+			path:   item + "/binding",
+			params: params,
+			gvk:    corev1.SchemeGroupVersion.WithKind("Binding"),
+			ops: []operation{{
+				method:      http.MethodPost,
+				action:      "post",
+				id:          "create" + idPrefix + idKind + "Binding",
+				description: "create binding of a Pod",
+				consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+				body:        names.binding,
+				responses:   map[int]string{http.StatusOK: names.binding, http.StatusCreated: names.binding},
+			}},
+		})
+	}
+	if names.eviction != "" {
+		routes = append(routes, route{
+			path:   item + "/eviction",
+			params: params,
+			gvk:    policyv1.SchemeGroupVersion.WithKind("Eviction"),
+			ops: []operation{{
+				method:      http.MethodPost,
+				action:      "post",
+				id:          "create" + idPrefix + idKind + "Eviction",
+				description: "create eviction of a Pod",
+				consumes:    []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf},
+				body:        names.eviction,
+				responses:   map[int]string{http.StatusOK: names.eviction, http.StatusCreated: names.eviction},
+			}},
+		})
+	}
+	return routes
+}
+
+func info() *spec.Info {
+	return &spec.Info{InfoProps: spec.InfoProps{Title: "Kubernetes", Version: typeinfo.ServerVersion.GitVersion}}
+}
+
+func gvkExtension(gvk schema.GroupVersionKind) map[string]any {
+	return map[string]any{"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind}
+}
+
+// BuildV2 returns the OpenAPI v2 document of the kinds described by the given descriptors.
+func BuildV2(descriptors []typeinfo.Descriptor) (*spec.Swagger, error) {
+	defs := newDefinitions(false)
+	paths := &spec.Paths{Paths: make(map[string]spec.PathItem)}
+	for _, desc := range descriptors {
+		names, err := defs.addKind(desc)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routesOf(desc, names) {
+			paths.Paths[r.path] = r.v2PathItem(defs)
+		}
+	}
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Swagger:     "2.0",
+		Info:        info(),
+		Paths:       paths,
+		Definitions: defs.schemas,
+	}}, nil
+}
+
+func (r route) v2PathItem(defs *definitions) spec.PathItem {
+	var item spec.PathItem
+	for _, p := range r.params {
+		item.Parameters = append(item.Parameters, p.v2())
+	}
+	for _, op := range r.ops {
+		o := &spec.Operation{OperationProps: spec.OperationProps{
+			ID:          op.id,
+			Description: op.description,
+			Consumes:    op.consumes,
+			Produces:    produces,
+			Responses:   &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: make(map[int]spec.Response)}},
+		}}
+		for _, p := range op.query {
+			o.Parameters = append(o.Parameters, p.v2())
+		}
+		if op.body != "" {
+			body := defs.refSchema(op.body)
+			o.Parameters = append(o.Parameters, spec.Parameter{ParamProps: spec.ParamProps{Name: "body", In: "body", Required: true, Schema: &body}})
+		}
+		for code, name := range op.responses {
+			schema := defs.refSchema(name)
+			o.Responses.StatusCodeResponses[code] = spec.Response{ResponseProps: spec.ResponseProps{Description: http.StatusText(code), Schema: &schema}}
+		}
+		o.AddExtension(extensionAction, op.action)
+		o.AddExtension(extensionGVK, gvkExtension(r.gvk))
+		switch op.method {
+		case http.MethodGet:
+			item.Get = o
+		case http.MethodPost:
+			item.Post = o
+		case http.MethodPut:
+			item.Put = o
+		case http.MethodPatch:
+			item.Patch = o
+		case http.MethodDelete:
+			item.Delete = o
+		}
+	}
+	return item
+}
+
+func (p parameter) v2() spec.Parameter {
+	param := spec.Parameter{ParamProps: spec.ParamProps{Name: p.name, In: p.in, Description: p.description, Required: p.required}}
+	if len(p.schema.Type) > 0 {
+		param.Type = p.schema.Type[0]
+	}
+	param.Format = p.schema.Format
+	return param
+}
+
+// BuildV3 returns the OpenAPI v3 documents of the kinds described by the given descriptors, keyed by the paths of their
+// group versions such as api/v1 and apis/apps/v1. Each document only holds the schemas its operations refer to.
+func BuildV3(descriptors []typeinfo.Descriptor) (map[string]*spec3.OpenAPI, error) {
+	docs := make(map[string]*spec3.OpenAPI)
+	defsByPath := make(map[string]*definitions)
+	for _, desc := range descriptors {
+		gvPath := strings.TrimPrefix(groupVersionPath(desc.GVK.GroupVersion()), "/")
+		doc, ok := docs[gvPath]
+		if !ok {
+			doc = &spec3.OpenAPI{
+				Version:    "3.0.0",
+				Info:       info(),
+				Paths:      &spec3.Paths{Paths: make(map[string]*spec3.Path)},
+				Components: &spec3.Components{},
+			}
+			docs[gvPath] = doc
+			defsByPath[gvPath] = newDefinitions(true)
+		}
+		defs := defsByPath[gvPath]
+		names, err := defs.addKind(desc)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routesOf(desc, names) {
+			doc.Paths.Paths[r.path] = r.v3Path(defs)
+		}
+	}
+	for gvPath, doc := range docs {
+		schemas := defsByPath[gvPath].schemas
+		doc.Components.Schemas = make(map[string]*spec.Schema, len(schemas))
+		for name := range schemas {
+			s := schemas[name]
+			doc.Components.Schemas[name] = &s
+		}
+	}
+	return docs, nil
+}
+
+func (r route) v3Path(defs *definitions) *spec3.Path {
+	path := &spec3.Path{}
+	for _, p := range r.params {
+		path.Parameters = append(path.Parameters, p.v3())
+	}
+	for _, op := range r.ops {
+		o := &spec3.Operation{OperationProps: spec3.OperationProps{
+			OperationId: op.id,
+			Description: op.description,
+			Responses:   &spec3.Responses{ResponsesProps: spec3.ResponsesProps{StatusCodeResponses: make(map[int]*spec3.Response)}},
+		}}
+		for _, p := range op.query {
+			o.Parameters = append(o.Parameters, p.v3())
+		}
+		if op.body != "" {
+			o.RequestBody = &spec3.RequestBody{RequestBodyProps: spec3.RequestBodyProps{
+				Content:  mediaTypes(op.consumes, defs.refSchema(op.body)),
+				Required: true,
+			}}
+		}
+		for code, name := range op.responses {
+			o.Responses.StatusCodeResponses[code] = &spec3.Response{ResponseProps: spec3.ResponseProps{
+				Description: http.StatusText(code),
+				Content:     mediaTypes(produces, defs.refSchema(name)),
+			}}
+		}
+		o.AddExtension(extensionAction, op.action)
+		o.AddExtension(extensionGVK, gvkExtension(r.gvk))
+		switch op.method {
+		case http.MethodGet:
+			path.Get = o
+		case http.MethodPost:
+			path.Post = o
+		case http.MethodPut:
+			path.Put = o
+		case http.MethodPatch:
+			path.Patch = o
+		case http.MethodDelete:
+			path.Delete = o
+		}
+	}
+	return path
+}
+
+func (p parameter) v3() *spec3.Parameter {
+	schema := p.schema
+	return &spec3.Parameter{ParameterProps: spec3.ParameterProps{
+		Name:        p.name,
+		In:          p.in,
+		Description: p.description,
+		Required:    p.required,
+		Schema:      &schema,
+	}}
+}
+
+func mediaTypes(contentTypes []string, schema spec.Schema) map[string]*spec3.MediaType {
+	content := make(map[string]*spec3.MediaType, len(contentTypes))
+	for _, contentType := range contentTypes {
+		content[contentType] = &spec3.MediaType{MediaTypeProps: spec3.MediaTypeProps{Schema: &schema}}
+	}
+	return content
+}
+
+// Fingerprint returns a fingerprint of the OpenAPI documents of the kinds described by the given descriptors, which
+// only changes along with the documents. It allows to rebuild the documents only if custom resources change.
+func Fingerprint(descriptors []typeinfo.Descriptor) (string, error) {
+	type fingerprinted struct {
+		GVK               schema.GroupVersionKind
+		ListGVK           schema.GroupVersionKind
+		GVR               schema.GroupVersionResource
+		Namespaced        bool
+		StatusSubresource bool
+		OpenAPIV3Schema   *apiextensionsv1.JSONSchemaProps
+	}
+	fps := make([]fingerprinted, 0, len(descriptors))
+	for _, d := range descriptors {
+		fps = append(fps, fingerprinted{
+			GVK:               d.GVK,
+			ListGVK:           d.ListGVK,
+			GVR:               d.GVR,
+			Namespaced:        d.APIResource.Namespaced,
+			StatusSubresource: d.StatusSubresource,
+			OpenAPIV3Schema:   d.OpenAPIV3Schema,
+		})
+	}
+	data, err := json.Marshal(fps)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
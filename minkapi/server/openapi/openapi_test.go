@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestOperationGroupVersion(t *testing.T) {
+	tests := map[string]struct {
+		gv   schema.GroupVersion
+		want string
+	}{
+		"core": {
+			gv:   schema.GroupVersion{Version: "v1"},
+			want: "CoreV1",
+		},
+		"single word group": {
+			gv:   schema.GroupVersion{Group: "apps", Version: "v1"},
+			want: "AppsV1",
+		},
+		"kubernetes group": {
+			gv:   schema.GroupVersion{Group: "rbac.authorization.k8s.io", Version: "v1"},
+			want: "RbacAuthorizationV1",
+		},
+		"custom resource group": {
+			gv:   schema.GroupVersion{Group: "example.com", Version: "v1beta1"},
+			want: "ExampleComV1beta1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := operationGroupVersion(tc.gv); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildV2(t *testing.T) {
+	widgets := newWidgetDescriptor(t, &apiextensionsv1.JSONSchemaProps{Type: "object"})
+	doc, err := BuildV2([]typeinfo.Descriptor{typeinfo.PodsDescriptor, typeinfo.NodesDescriptor, typeinfo.DeploymentDescriptor, widgets})
+	if err != nil {
+		t.Fatalf("failed to build OpenAPI v2 document: %v", err)
+	}
+
+	pod, ok := doc.Definitions["io.k8s.api.core.v1.Pod"]
+	if !ok {
+		t.Fatalf("expected definition of Pod")
+	}
+	if gvks, _ := pod.Extensions[extensionGVK].([]any); len(gvks) != 1 {
+		t.Errorf("expected a single group version kind of Pod, got %v", pod.Extensions[extensionGVK])
+	}
+	containers := doc.Definitions["io.k8s.api.core.v1.PodSpec"].Properties["containers"]
+	if containers.Extensions[extensionPatchMergeKey] != "name" || containers.Extensions[extensionPatchStrategy] != "merge" {
+		t.Errorf("expected containers to be merged by name, got extensions %v", containers.Extensions)
+	}
+	if !slices.Contains(doc.Definitions["io.k8s.api.core.v1.PodSpec"].Required, "containers") {
+		t.Errorf("expected containers to be required")
+	}
+	if got := doc.Definitions["io.k8s.apimachinery.pkg.util.intstr.IntOrString"].Format; got != "int-or-string" {
+		t.Errorf("expected IntOrString format int-or-string, got %q", got)
+	}
+	for _, name := range []string{"io.k8s.api.policy.v1.Eviction", "io.k8s.api.autoscaling.v1.Scale"} {
+		if _, ok := doc.Definitions[name]; !ok {
+			t.Errorf("expected definition of %s", name)
+		}
+	}
+	widget, ok := doc.Definitions["com.example.v1.Widget"]
+	if !ok {
+		t.Fatalf("expected definition of custom resource Widget")
+	}
+	metadata := widget.Properties["metadata"]
+	if got := metadata.Ref.String(); got != v2RefPrefix+"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta" {
+		t.Errorf("expected metadata of Widget to refer to ObjectMeta, got %q", got)
+	}
+
+	tests := map[string]struct {
+		path         string
+		wantGetID    string
+		wantPatchID  string
+		wantDeleteID string
+	}{
+		"pods of all namespaces": {
+			path:      "/api/v1/pods",
+			wantGetID: "listCoreV1PodForAllNamespaces",
+		},
+		"pods": {
+			path:         "/api/v1/namespaces/{namespace}/pods",
+			wantGetID:    "listCoreV1NamespacedPod",
+			wantDeleteID: "deletecollectionCoreV1NamespacedPod",
+		},
+		"pod": {
+			path:        "/api/v1/namespaces/{namespace}/pods/{name}",
+			wantGetID:   "readCoreV1NamespacedPod",
+			wantPatchID: "patchCoreV1NamespacedPod",
+		},
+		"pod status": {
+			path:        "/api/v1/namespaces/{namespace}/pods/{name}/status",
+			wantPatchID: "patchCoreV1NamespacedPodStatus",
+		},
+		"pod binding": {
+			path: "/api/v1/namespaces/{namespace}/pods/{name}/binding",
+		},
+		"pod eviction": {
+			path: "/api/v1/namespaces/{namespace}/pods/{name}/eviction",
+		},
+		"node status": {
+			path:        "/api/v1/nodes/{name}/status",
+			wantGetID:   "readCoreV1NodeStatus",
+			wantPatchID: "patchCoreV1NodeStatus",
+		},
+		"deployment scale": {
+			path:        "/apis/apps/v1/namespaces/{namespace}/deployments/{name}/scale",
+			wantGetID:   "readAppsV1NamespacedDeploymentScale",
+			wantPatchID: "patchAppsV1NamespacedDeploymentScale",
+		},
+		"widgets": {
+			path:      "/apis/example.com/v1/namespaces/{namespace}/widgets",
+			wantGetID: "listExampleComV1NamespacedWidget",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			item, ok := doc.Paths.Paths[tc.path]
+			if !ok {
+				t.Fatalf("expected path %q", tc.path)
+			}
+			if tc.wantGetID != "" && (item.Get == nil || item.Get.ID != tc.wantGetID) {
+				t.Errorf("expected get operation %q, got %v", tc.wantGetID, item.Get)
+			}
+			if tc.wantPatchID == "" && item.Patch != nil {
+				t.Errorf("expected no patch operation, got %q", item.Patch.ID)
+			} else if tc.wantPatchID != "" && (item.Patch == nil || item.Patch.ID != tc.wantPatchID) {
+				t.Errorf("expected patch operation %q, got %v", tc.wantPatchID, item.Patch)
+			}
+			if tc.wantDeleteID != "" && (item.Delete == nil || item.Delete.ID != tc.wantDeleteID) {
+				t.Errorf("expected delete operation %q, got %v", tc.wantDeleteID, item.Delete)
+			}
+		})
+	}
+}
+
+func TestBuildV3(t *testing.T) {
+	docs, err := BuildV3([]typeinfo.Descriptor{typeinfo.PodsDescriptor, typeinfo.DeploymentDescriptor, newWidgetDescriptor(t, nil)})
+	if err != nil {
+		t.Fatalf("failed to build OpenAPI v3 documents: %v", err)
+	}
+	for _, gvPath := range []string{"api/v1", "apis/apps/v1", "apis/example.com/v1"} {
+		if _, ok := docs[gvPath]; !ok {
+			t.Errorf("expected OpenAPI v3 document of %q", gvPath)
+		}
+	}
+	if len(docs) != 3 {
+		t.Errorf("expected 3 OpenAPI v3 documents, got %d", len(docs))
+	}
+
+	core := docs["api/v1"].Components.Schemas
+	if _, ok := core["io.k8s.api.apps.v1.Deployment"]; ok {
+		t.Errorf("expected document of api/v1 not to hold the schema of Deployment")
+	}
+	if got := len(core["io.k8s.apimachinery.pkg.util.intstr.IntOrString"].OneOf); got != 2 {
+		t.Errorf("expected IntOrString to be one of 2 types, got %d", got)
+	}
+	spec := core["io.k8s.api.core.v1.Pod"].Properties["spec"]
+	if len(spec.AllOf) != 1 || spec.AllOf[0].Ref.String() != v3RefPrefix+"io.k8s.api.core.v1.PodSpec" || spec.Description == "" {
+		t.Errorf("expected described spec of Pod to refer to PodSpec through allOf, got %v", spec)
+	}
+	widget := docs["apis/example.com/v1"].Components.Schemas["com.example.v1.Widget"]
+	if widget == nil || widget.Extensions[extensionPreserveUnknownField] != true {
+		t.Errorf("expected Widget without schema to preserve unknown fields, got %v", widget)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(descriptors ...typeinfo.Descriptor) string {
+		t.Helper()
+		fp, err := Fingerprint(descriptors)
+		if err != nil {
+			t.Fatalf("failed to fingerprint descriptors: %v", err)
+		}
+		return fp
+	}
+	withoutSchema := fingerprint(typeinfo.PodsDescriptor, newWidgetDescriptor(t, nil))
+	if got := fingerprint(typeinfo.PodsDescriptor, newWidgetDescriptor(t, nil)); got != withoutSchema {
+		t.Errorf("expected fingerprint of equal descriptors to be equal")
+	}
+	if got := fingerprint(typeinfo.PodsDescriptor, newWidgetDescriptor(t, &apiextensionsv1.JSONSchemaProps{Type: "object"})); got == withoutSchema {
+		t.Errorf("expected fingerprint to change with the schema of a custom resource")
+	}
+	if got := fingerprint(typeinfo.PodsDescriptor); got == withoutSchema {
+		t.Errorf("expected fingerprint to change with the removal of a custom resource")
+	}
+}
+
+func newWidgetDescriptor(t *testing.T, crSchema *apiextensionsv1.JSONSchemaProps) typeinfo.Descriptor {
+	t.Helper()
+	d, err := typeinfo.NewCustomResourceDescriptor(&apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema:  &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: crSchema},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create descriptor of custom resource: %v", err)
+	}
+	return d
+}
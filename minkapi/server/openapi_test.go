@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/handler3"
+)
+
+func TestOpenAPIService(t *testing.T) {
+	k := newMetricsTestServer(t)
+	discover := func() handler3.OpenAPIV3Discovery {
+		t.Helper()
+		w := httptest.NewRecorder()
+		k.rootMux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sb/openapi/v3", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status code %d for OpenAPI v3 discovery, got %d", http.StatusOK, w.Code)
+		}
+		var discovery handler3.OpenAPIV3Discovery
+		if err := json.NewDecoder(w.Body).Decode(&discovery); err != nil {
+			t.Fatalf("failed to decode OpenAPI v3 discovery: %v", err)
+		}
+		return discovery
+	}
+
+	discovery := discover()
+	if _, ok := discovery.Paths["api/v1"]; !ok {
+		t.Errorf("expected OpenAPI v3 discovery to contain api/v1, got %v", discovery.Paths)
+	}
+	if _, ok := discovery.Paths["apis/example.com/v1"]; ok {
+		t.Errorf("expected OpenAPI v3 discovery not to contain apis/example.com/v1 before its CustomResourceDefinition is created")
+	}
+
+	sandboxView, err := k.GetSandboxView(context.Background(), "sb")
+	if err != nil {
+		t.Fatalf("failed to get sandbox view: %v", err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": {Type: "object"}},
+				}},
+			}},
+		},
+	}
+	if err = sandboxView.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, crd); err != nil {
+		t.Fatalf("failed to create CustomResourceDefinition: %v", err)
+	}
+	discovery = discover()
+	gv, ok := discovery.Paths["apis/example.com/v1"]
+	if !ok {
+		t.Fatalf("expected OpenAPI v3 discovery to contain apis/example.com/v1 after its CustomResourceDefinition is created, got %v", discovery.Paths)
+	}
+
+	tests := map[string]struct {
+		target          string
+		accept          string
+		wantCode        int
+		wantContentType string
+	}{
+		"v2 in JSON": {
+			target:          "/sb/openapi/v2",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+		},
+		"v2 in protobuf": {
+			target:          "/sb/openapi/v2",
+			accept:          "application/com.github.proto-openapi.spec.v2@v1.0+protobuf",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/com.github.proto-openapi.spec.v2.v1.0+protobuf",
+		},
+		"v3 of custom resource group version": {
+			target:          "/sb" + gv.ServerRelativeURL,
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+		},
+		"v3 in protobuf": {
+			target:          "/sb/openapi/v3/apis/apps/v1",
+			accept:          "application/com.github.proto-openapi.spec.v3@v1.0+protobuf",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/com.github.proto-openapi.spec.v3.v1.0+protobuf",
+		},
+		"v3 of custom resource group version in other view": {
+			target:   "/base/openapi/v3/apis/example.com/v1",
+			wantCode: http.StatusNotFound,
+		},
+		"v3 of unknown group version": {
+			target:   "/sb/openapi/v3/apis/unknown/v1",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	baseViewMux := http.NewServeMux()
+	k.registerRoutes(logr.Discard(), baseViewMux, k.baseView)
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			k.rootMux.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Fatalf("expected status code %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); tc.wantContentType != "" && got != tc.wantContentType {
+				t.Errorf("expected content type %q, got %q", tc.wantContentType, got)
+			}
+		})
+	}
+}
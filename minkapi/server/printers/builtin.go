@@ -0,0 +1,855 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package printers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/eventsink"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// builtinPrinters holds the printers of the kinds in typeinfo.SupportedDescriptors which have dedicated columns in the
+// kube-apiserver. Other kinds, such as roles, are printed by the defaultPrinter. Like the kube-apiserver, all columns are printed, and clients only show the columns with a non-zero
+// priority in their wide output.
+var builtinPrinters = map[schema.GroupVersionKind]printer{
+	typeinfo.NamespacesDescriptor.GVK:             newPrinter(namespaceColumns, printNamespace),
+	typeinfo.ServiceAccountsDescriptor.GVK:        newPrinter(serviceAccountColumns, printServiceAccount),
+	typeinfo.ConfigMapsDescriptor.GVK:             newPrinter(configMapColumns, printConfigMap),
+	typeinfo.NodesDescriptor.GVK:                  newPrinter(nodeColumns, printNode),
+	typeinfo.PodsDescriptor.GVK:                   newPrinter(podColumns, printPod),
+	typeinfo.ServicesDescriptor.GVK:               newPrinter(serviceColumns, printService),
+	typeinfo.PersistentVolumesDescriptor.GVK:      newPrinter(persistentVolumeColumns, printPersistentVolume),
+	typeinfo.PersistentVolumeClaimsDescriptor.GVK: newPrinter(persistentVolumeClaimColumns, printPersistentVolumeClaim),
+	typeinfo.ReplicationControllersDescriptor.GVK: newPrinter(replicationControllerColumns, printReplicationController),
+	typeinfo.CoreEventsDescriptor.GVK:             newPrinter(eventColumns, printCoreEvent),
+	typeinfo.PriorityClassesDescriptor.GVK:        newPrinter(priorityClassColumns, printPriorityClass),
+	typeinfo.LeaseDescriptor.GVK:                  newPrinter(leaseColumns, printLease),
+	typeinfo.EventsDescriptor.GVK:                 newPrinter(eventColumns, printEvent),
+	typeinfo.DeploymentDescriptor.GVK:             newPrinter(deploymentColumns, printDeployment),
+	typeinfo.ReplicaSetDescriptor.GVK:             newPrinter(replicaSetColumns, printReplicaSet),
+	typeinfo.StatefulSetDescriptor.GVK:            newPrinter(statefulSetColumns, printStatefulSet),
+	typeinfo.PodDisruptionBudgetDescriptor.GVK:    newPrinter(podDisruptionBudgetColumns, printPodDisruptionBudget),
+	typeinfo.StorageClassDescriptor.GVK:           newPrinter(storageClassColumns, printStorageClass),
+	typeinfo.CSIDriverDescriptor.GVK:              newPrinter(csiDriverColumns, printCSIDriver),
+	typeinfo.CSIStorageCapacityDescriptor.GVK:     newPrinter(csiStorageCapacityColumns, printCSIStorageCapacity),
+	typeinfo.CSINodeDescriptor.GVK:                newPrinter(csiNodeColumns, printCSINode),
+	typeinfo.VolumeAttachmentDescriptor.GVK:       newPrinter(volumeAttachmentColumns, printVolumeAttachment),
+}
+
+const (
+	labelNodeRolePrefix = "node-role.kubernetes.io/"
+	nodeLabelRole       = "kubernetes.io/role"
+	// nodeUnreachablePodReason is the reason set by the node lifecycle controller on pods of unreachable nodes.
+	nodeUnreachablePodReason = "NodeLost"
+
+	betaStorageClassAnnotation          = "volume.beta.kubernetes.io/storage-class"
+	isDefaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaIsDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+var (
+	containersColumn = metav1.TableColumnDefinition{Name: "Containers", Type: "string", Priority: 1, Description: "Names of each container in the template."}
+	imagesColumn     = metav1.TableColumnDefinition{Name: "Images", Type: "string", Priority: 1, Description: "Images referenced by each container in the template."}
+	selectorColumn   = metav1.TableColumnDefinition{Name: "Selector", Type: "string", Priority: 1, Description: "The label selector of the controlled pods."}
+
+	namespaceColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Status", Type: "string", Description: "The status of the namespace"},
+		ageColumn,
+	}
+	serviceAccountColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Secrets", Type: "integer", Description: corev1.ServiceAccount{}.SwaggerDoc()["secrets"]},
+		ageColumn,
+	}
+	configMapColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Data", Type: "integer", Description: corev1.ConfigMap{}.SwaggerDoc()["data"]},
+		ageColumn,
+	}
+	nodeColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Status", Type: "string", Description: "The status of the node"},
+		{Name: "Roles", Type: "string", Description: "The roles of the node"},
+		ageColumn,
+		{Name: "Version", Type: "string", Description: corev1.NodeSystemInfo{}.SwaggerDoc()["kubeletVersion"]},
+		{Name: "Internal-IP", Type: "string", Priority: 1, Description: corev1.NodeStatus{}.SwaggerDoc()["addresses"]},
+		{Name: "External-IP", Type: "string", Priority: 1, Description: corev1.NodeStatus{}.SwaggerDoc()["addresses"]},
+		{Name: "OS-Image", Type: "string", Priority: 1, Description: corev1.NodeSystemInfo{}.SwaggerDoc()["osImage"]},
+		{Name: "Kernel-Version", Type: "string", Priority: 1, Description: corev1.NodeSystemInfo{}.SwaggerDoc()["kernelVersion"]},
+		{Name: "Container-Runtime", Type: "string", Priority: 1, Description: corev1.NodeSystemInfo{}.SwaggerDoc()["containerRuntimeVersion"]},
+	}
+	podColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Ready", Type: "string", Description: "The aggregate readiness state of this pod for accepting traffic."},
+		{Name: "Status", Type: "string", Description: "The aggregate status of the containers in this pod."},
+		{Name: "Restarts", Type: "string", Description: "The number of times the containers in this pod have been restarted and when the last container in this pod has restarted."},
+		ageColumn,
+		{Name: "IP", Type: "string", Priority: 1, Description: corev1.PodStatus{}.SwaggerDoc()["podIP"]},
+		{Name: "Node", Type: "string", Priority: 1, Description: corev1.PodSpec{}.SwaggerDoc()["nodeName"]},
+		{Name: "Nominated Node", Type: "string", Priority: 1, Description: corev1.PodStatus{}.SwaggerDoc()["nominatedNodeName"]},
+		{Name: "Readiness Gates", Type: "string", Priority: 1, Description: corev1.PodSpec{}.SwaggerDoc()["readinessGates"]},
+	}
+	serviceColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Type", Type: "string", Description: corev1.ServiceSpec{}.SwaggerDoc()["type"]},
+		{Name: "Cluster-IP", Type: "string", Description: corev1.ServiceSpec{}.SwaggerDoc()["clusterIP"]},
+		{Name: "External-IP", Type: "string", Description: corev1.ServiceSpec{}.SwaggerDoc()["externalIPs"]},
+		{Name: "Port(s)", Type: "string", Description: corev1.ServiceSpec{}.SwaggerDoc()["ports"]},
+		ageColumn,
+		{Name: "Selector", Type: "string", Priority: 1, Description: corev1.ServiceSpec{}.SwaggerDoc()["selector"]},
+	}
+	persistentVolumeColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Capacity", Type: "string", Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["capacity"]},
+		{Name: "Access Modes", Type: "string", Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["accessModes"]},
+		{Name: "Reclaim Policy", Type: "string", Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["persistentVolumeReclaimPolicy"]},
+		{Name: "Status", Type: "string", Description: corev1.PersistentVolumeStatus{}.SwaggerDoc()["phase"]},
+		{Name: "Claim", Type: "string", Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["claimRef"]},
+		{Name: "StorageClass", Type: "string", Description: "StorageClass of the pv"},
+		{Name: "VolumeAttributesClass", Type: "string", Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["volumeAttributesClassName"]},
+		{Name: "Reason", Type: "string", Description: corev1.PersistentVolumeStatus{}.SwaggerDoc()["reason"]},
+		ageColumn,
+		{Name: "VolumeMode", Type: "string", Priority: 1, Description: corev1.PersistentVolumeSpec{}.SwaggerDoc()["volumeMode"]},
+	}
+	persistentVolumeClaimColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Status", Type: "string", Description: corev1.PersistentVolumeClaimStatus{}.SwaggerDoc()["phase"]},
+		{Name: "Volume", Type: "string", Description: corev1.PersistentVolumeClaimSpec{}.SwaggerDoc()["volumeName"]},
+		{Name: "Capacity", Type: "string", Description: corev1.PersistentVolumeClaimStatus{}.SwaggerDoc()["capacity"]},
+		{Name: "Access Modes", Type: "string", Description: corev1.PersistentVolumeClaimStatus{}.SwaggerDoc()["accessModes"]},
+		{Name: "StorageClass", Type: "string", Description: "StorageClass of the pvc"},
+		{Name: "VolumeAttributesClass", Type: "string", Description: corev1.PersistentVolumeClaimSpec{}.SwaggerDoc()["volumeAttributesClassName"]},
+		ageColumn,
+		{Name: "VolumeMode", Type: "string", Priority: 1, Description: corev1.PersistentVolumeClaimSpec{}.SwaggerDoc()["volumeMode"]},
+	}
+	replicationControllerColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Desired", Type: "integer", Description: corev1.ReplicationControllerSpec{}.SwaggerDoc()["replicas"]},
+		{Name: "Current", Type: "integer", Description: corev1.ReplicationControllerStatus{}.SwaggerDoc()["replicas"]},
+		{Name: "Ready", Type: "integer", Description: corev1.ReplicationControllerStatus{}.SwaggerDoc()["readyReplicas"]},
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+		selectorColumn,
+	}
+	priorityClassColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Value", Type: "integer", Description: schedulingv1.PriorityClass{}.SwaggerDoc()["value"]},
+		{Name: "Global-Default", Type: "boolean", Description: schedulingv1.PriorityClass{}.SwaggerDoc()["globalDefault"]},
+		ageColumn,
+		{Name: "PreemptionPolicy", Type: "string", Description: schedulingv1.PriorityClass{}.SwaggerDoc()["preemptionPolicy"]},
+	}
+	leaseColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Holder", Type: "string", Description: coordinationv1.LeaseSpec{}.SwaggerDoc()["holderIdentity"]},
+		ageColumn,
+	}
+	eventColumns = []metav1.TableColumnDefinition{
+		{Name: "Last Seen", Type: "string", Description: eventsv1.Event{}.SwaggerDoc()["deprecatedLastTimestamp"]},
+		{Name: "Type", Type: "string", Description: eventsv1.Event{}.SwaggerDoc()["type"]},
+		{Name: "Reason", Type: "string", Description: eventsv1.Event{}.SwaggerDoc()["reason"]},
+		{Name: "Object", Type: "string", Description: eventsv1.Event{}.SwaggerDoc()["regarding"]},
+		{Name: "Subobject", Type: "string", Priority: 1, Description: corev1.ObjectReference{}.SwaggerDoc()["fieldPath"]},
+		{Name: "Source", Type: "string", Priority: 1, Description: eventsv1.Event{}.SwaggerDoc()["reportingController"]},
+		{Name: "Message", Type: "string", Description: eventsv1.Event{}.SwaggerDoc()["note"]},
+		{Name: "First Seen", Type: "string", Priority: 1, Description: eventsv1.Event{}.SwaggerDoc()["deprecatedFirstTimestamp"]},
+		{Name: "Count", Type: "string", Priority: 1, Description: eventsv1.Event{}.SwaggerDoc()["deprecatedCount"]},
+		{Name: "Name", Type: "string", Priority: 1, Format: "name", Description: metav1.ObjectMeta{}.SwaggerDoc()["name"]},
+	}
+	deploymentColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Ready", Type: "string", Description: "Number of the pod with ready state"},
+		{Name: "Up-to-date", Type: "string", Description: appsv1.DeploymentStatus{}.SwaggerDoc()["updatedReplicas"]},
+		{Name: "Available", Type: "string", Description: appsv1.DeploymentStatus{}.SwaggerDoc()["availableReplicas"]},
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+		selectorColumn,
+	}
+	replicaSetColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Desired", Type: "integer", Description: appsv1.ReplicaSetSpec{}.SwaggerDoc()["replicas"]},
+		{Name: "Current", Type: "integer", Description: appsv1.ReplicaSetStatus{}.SwaggerDoc()["replicas"]},
+		{Name: "Ready", Type: "integer", Description: appsv1.ReplicaSetStatus{}.SwaggerDoc()["readyReplicas"]},
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+		selectorColumn,
+	}
+	statefulSetColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Ready", Type: "string", Description: "Number of the pod with ready state"},
+		ageColumn,
+		containersColumn,
+		imagesColumn,
+	}
+	podDisruptionBudgetColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Min Available", Type: "string", Description: "The minimum number of pods that must be available."},
+		{Name: "Max Unavailable", Type: "string", Description: "The maximum number of pods that may be unavailable."},
+		{Name: "Allowed Disruptions", Type: "integer", Description: "Calculated number of pods that may be disrupted at this time."},
+		ageColumn,
+	}
+	storageClassColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Provisioner", Type: "string", Description: "Indicates the type of the provisioner."},
+		{Name: "ReclaimPolicy", Type: "string", Description: "Indicates the type of the reclaim policy."},
+		{Name: "VolumeBindingMode", Type: "string", Description: "Indicates how PersistentVolumeClaims should be provisioned and bound"},
+		{Name: "AllowVolumeExpansion", Type: "string", Description: "Indicates whether the storage class allow volume expand"},
+		ageColumn,
+	}
+	csiDriverColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "AttachRequired", Type: "boolean", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["attachRequired"]},
+		{Name: "PodInfoOnMount", Type: "boolean", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["podInfoOnMount"]},
+		{Name: "StorageCapacity", Type: "boolean", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["storageCapacity"]},
+		{Name: "TokenRequests", Type: "string", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["tokenRequests"]},
+		{Name: "RequiresRepublish", Type: "boolean", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["requiresRepublish"]},
+		{Name: "Modes", Type: "string", Description: storagev1.CSIDriverSpec{}.SwaggerDoc()["volumeLifecycleModes"]},
+		ageColumn,
+	}
+	csiStorageCapacityColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "StorageClassName", Type: "string", Description: storagev1.CSIStorageCapacity{}.SwaggerDoc()["storageClassName"]},
+		{Name: "Capacity", Type: "string", Description: storagev1.CSIStorageCapacity{}.SwaggerDoc()["capacity"]},
+	}
+	csiNodeColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Drivers", Type: "integer", Description: "Drivers indicates the number of CSI drivers registered on the node"},
+		ageColumn,
+	}
+	volumeAttachmentColumns = []metav1.TableColumnDefinition{
+		nameColumn,
+		{Name: "Attacher", Type: "string", Format: "name", Description: storagev1.VolumeAttachmentSpec{}.SwaggerDoc()["attacher"]},
+		{Name: "PV", Type: "string", Description: storagev1.VolumeAttachmentSource{}.SwaggerDoc()["persistentVolumeName"]},
+		{Name: "Node", Type: "string", Description: storagev1.VolumeAttachmentSpec{}.SwaggerDoc()["nodeName"]},
+		{Name: "Attached", Type: "boolean", Description: storagev1.VolumeAttachmentStatus{}.SwaggerDoc()["attached"]},
+		ageColumn,
+	}
+)
+
+func printNamespace(obj *corev1.Namespace) metav1.TableRow {
+	return metav1.TableRow{Cells: []any{obj.Name, string(obj.Status.Phase), translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printServiceAccount(obj *corev1.ServiceAccount) metav1.TableRow {
+	return metav1.TableRow{Cells: []any{obj.Name, int64(len(obj.Secrets)), translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printConfigMap(obj *corev1.ConfigMap) metav1.TableRow {
+	return metav1.TableRow{Cells: []any{obj.Name, int64(len(obj.Data) + len(obj.BinaryData)), translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printNode(obj *corev1.Node) metav1.TableRow {
+	var status []string
+	if i := slices.IndexFunc(obj.Status.Conditions, func(c corev1.NodeCondition) bool {
+		return c.Type == corev1.NodeReady
+	}); i >= 0 {
+		if obj.Status.Conditions[i].Status == corev1.ConditionTrue {
+			status = append(status, string(corev1.NodeReady))
+		} else {
+			status = append(status, "Not"+string(corev1.NodeReady))
+		}
+	} else {
+		status = append(status, "Unknown")
+	}
+	if obj.Spec.Unschedulable {
+		status = append(status, "SchedulingDisabled")
+	}
+	roles := strings.Join(findNodeRoles(obj), ",")
+	if roles == "" {
+		roles = "<none>"
+	}
+	nodeInfo := obj.Status.NodeInfo
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		strings.Join(status, ","),
+		roles,
+		translateTimestampSince(obj.CreationTimestamp),
+		nodeInfo.KubeletVersion,
+		nodeAddress(obj, corev1.NodeInternalIP),
+		nodeAddress(obj, corev1.NodeExternalIP),
+		valueOrUnknown(nodeInfo.OSImage),
+		valueOrUnknown(nodeInfo.KernelVersion),
+		valueOrUnknown(nodeInfo.ContainerRuntimeVersion),
+	}}
+}
+
+// findNodeRoles returns the sorted roles of the given node, which are taken from the node-role.kubernetes.io/<role>
+// and kubernetes.io/role labels.
+func findNodeRoles(node *corev1.Node) []string {
+	roles := sets.New[string]()
+	for k, v := range node.Labels {
+		switch {
+		case strings.HasPrefix(k, labelNodeRolePrefix):
+			if role := strings.TrimPrefix(k, labelNodeRolePrefix); role != "" {
+				roles.Insert(role)
+			}
+		case k == nodeLabelRole && v != "":
+			roles.Insert(v)
+		}
+	}
+	return sets.List(roles)
+}
+
+func nodeAddress(node *corev1.Node, addressType corev1.NodeAddressType) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == addressType {
+			return address.Address
+		}
+	}
+	return "<none>"
+}
+
+// printPod prints the cells of a pod like `kubectl get pods`, where the status is derived from the phase of the pod
+// and the states of its init and app containers.
+func printPod(pod *corev1.Pod) metav1.TableRow {
+	var (
+		restarts, restartableInitContainerRestarts               int
+		lastRestartDate, lastRestartableInitContainerRestartDate metav1.Time
+		readyContainers                                          int
+		totalContainers                                          = len(pod.Spec.Containers)
+	)
+	row := metav1.TableRow{}
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+	if slices.ContainsFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+		return c.Type == corev1.PodScheduled && c.Reason == corev1.PodReasonSchedulingGated
+	}) {
+		reason = corev1.PodReasonSchedulingGated
+	}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		row.Conditions = []metav1.TableRowCondition{{Type: metav1.RowCompleted, Status: metav1.ConditionTrue, Reason: string(pod.Status.Phase), Message: "The pod has completed successfully."}}
+	case corev1.PodFailed:
+		row.Conditions = []metav1.TableRowCondition{{Type: metav1.RowCompleted, Status: metav1.ConditionTrue, Reason: string(pod.Status.Phase), Message: "The pod failed."}}
+	}
+
+	initContainers := make(map[string]*corev1.Container, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		initContainers[pod.Spec.InitContainers[i].Name] = &pod.Spec.InitContainers[i]
+		if isRestartableInitContainer(&pod.Spec.InitContainers[i]) {
+			totalContainers++
+		}
+	}
+	initializing := false
+	for i, container := range pod.Status.InitContainerStatuses {
+		restarts += int(container.RestartCount)
+		if container.LastTerminationState.Terminated != nil && lastRestartDate.Before(&container.LastTerminationState.Terminated.FinishedAt) {
+			lastRestartDate = container.LastTerminationState.Terminated.FinishedAt
+		}
+		restartable := isRestartableInitContainer(initContainers[container.Name])
+		if restartable {
+			restartableInitContainerRestarts += int(container.RestartCount)
+			if container.LastTerminationState.Terminated != nil && lastRestartableInitContainerRestartDate.Before(&container.LastTerminationState.Terminated.FinishedAt) {
+				lastRestartableInitContainerRestartDate = container.LastTerminationState.Terminated.FinishedAt
+			}
+		}
+		switch {
+		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			continue
+		case restartable && container.Started != nil && *container.Started:
+			if container.Ready {
+				readyContainers++
+			}
+			continue
+		case container.State.Terminated != nil:
+			// initialization has failed.
+			reason = "Init:" + terminationReason(container.State.Terminated)
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "" && container.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + container.State.Waiting.Reason
+		default:
+			reason = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing || isPodInitialized(pod) {
+		restarts = restartableInitContainerRestarts
+		lastRestartDate = lastRestartableInitContainerRestartDate
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			container := pod.Status.ContainerStatuses[i]
+			restarts += int(container.RestartCount)
+			if container.LastTerminationState.Terminated != nil && lastRestartDate.Before(&container.LastTerminationState.Terminated.FinishedAt) {
+				lastRestartDate = container.LastTerminationState.Terminated.FinishedAt
+			}
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+				reason = container.State.Waiting.Reason
+			case container.State.Terminated != nil:
+				reason = terminationReason(container.State.Terminated)
+			case container.Ready && container.State.Running != nil:
+				hasRunning = true
+				readyContainers++
+			}
+		}
+		// the pod is reported as running if there is at least one container still running.
+		if reason == "Completed" && hasRunning {
+			if hasPodReadyCondition(pod) {
+				reason = string(corev1.PodRunning)
+			} else {
+				reason = "NotReady"
+			}
+		}
+	}
+
+	if pod.DeletionTimestamp != nil && pod.Status.Reason == nodeUnreachablePodReason {
+		reason = "Unknown"
+	} else if pod.DeletionTimestamp != nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		reason = "Terminating"
+	}
+
+	restartsStr := strconv.Itoa(restarts)
+	if restarts != 0 && !lastRestartDate.IsZero() {
+		restartsStr = fmt.Sprintf("%d (%s ago)", restarts, translateTimestampSince(lastRestartDate))
+	}
+	podIP := "<none>"
+	if len(pod.Status.PodIPs) > 0 {
+		podIP = pod.Status.PodIPs[0].IP
+	}
+	readinessGates := "<none>"
+	if len(pod.Spec.ReadinessGates) > 0 {
+		trueConditions := 0
+		for _, gate := range pod.Spec.ReadinessGates {
+			if i := slices.IndexFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+				return c.Type == gate.ConditionType
+			}); i >= 0 && pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+				trueConditions++
+			}
+		}
+		readinessGates = fmt.Sprintf("%d/%d", trueConditions, len(pod.Spec.ReadinessGates))
+	}
+	row.Cells = []any{
+		pod.Name,
+		fmt.Sprintf("%d/%d", readyContainers, totalContainers),
+		reason,
+		restartsStr,
+		translateTimestampSince(pod.CreationTimestamp),
+		podIP,
+		valueOrNone(pod.Spec.NodeName),
+		valueOrNone(pod.Status.NominatedNodeName),
+		readinessGates,
+	}
+	return row
+}
+
+// terminationReason returns the reason of the given terminated container state, falling back to its signal or exit code.
+func terminationReason(terminated *corev1.ContainerStateTerminated) string {
+	switch {
+	case terminated.Reason != "":
+		return terminated.Reason
+	case terminated.Signal != 0:
+		return fmt.Sprintf("Signal:%d", terminated.Signal)
+	default:
+		return fmt.Sprintf("ExitCode:%d", terminated.ExitCode)
+	}
+}
+
+func isRestartableInitContainer(container *corev1.Container) bool {
+	return container != nil && container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+func isPodInitialized(pod *corev1.Pod) bool {
+	return slices.ContainsFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+		return c.Type == corev1.PodInitialized && c.Status == corev1.ConditionTrue
+	})
+}
+
+func hasPodReadyCondition(pod *corev1.Pod) bool {
+	return slices.ContainsFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+		return c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue
+	})
+}
+
+func printService(obj *corev1.Service) metav1.TableRow {
+	clusterIP := "<none>"
+	if len(obj.Spec.ClusterIPs) > 0 {
+		clusterIP = obj.Spec.ClusterIPs[0]
+	} else if obj.Spec.ClusterIP != "" {
+		clusterIP = obj.Spec.ClusterIP
+	}
+	ports := make([]string, len(obj.Spec.Ports))
+	for i, port := range obj.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		if port.NodePort > 0 {
+			ports[i] = fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, protocol)
+		} else {
+			ports[i] = fmt.Sprintf("%d/%s", port.Port, protocol)
+		}
+	}
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		string(obj.Spec.Type),
+		clusterIP,
+		serviceExternalIP(obj),
+		valueOrNone(strings.Join(ports, ",")),
+		translateTimestampSince(obj.CreationTimestamp),
+		labels.FormatLabels(obj.Spec.Selector),
+	}}
+}
+
+func serviceExternalIP(svc *corev1.Service) string {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, "":
+		return valueOrNone(strings.Join(svc.Spec.ExternalIPs, ","))
+	case corev1.ServiceTypeLoadBalancer:
+		var ips []string
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				ips = append(ips, ingress.IP)
+			} else if ingress.Hostname != "" {
+				ips = append(ips, ingress.Hostname)
+			}
+		}
+		ips = append(ips, svc.Spec.ExternalIPs...)
+		if len(ips) == 0 {
+			return "<pending>"
+		}
+		return strings.Join(ips, ",")
+	case corev1.ServiceTypeExternalName:
+		return svc.Spec.ExternalName
+	}
+	return "<unknown>"
+}
+
+func printPersistentVolume(obj *corev1.PersistentVolume) metav1.TableRow {
+	claim := ""
+	if obj.Spec.ClaimRef != nil {
+		claim = obj.Spec.ClaimRef.Namespace + "/" + obj.Spec.ClaimRef.Name
+	}
+	phase := string(obj.Status.Phase)
+	if obj.DeletionTimestamp != nil {
+		phase = "Terminating"
+	}
+	storageClass := obj.Spec.StorageClassName
+	if class, ok := obj.Annotations[betaStorageClassAnnotation]; ok {
+		storageClass = class
+	}
+	capacity := obj.Spec.Capacity[corev1.ResourceStorage]
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		capacity.String(),
+		accessModesAsString(obj.Spec.AccessModes),
+		string(obj.Spec.PersistentVolumeReclaimPolicy),
+		phase,
+		claim,
+		storageClass,
+		valueOrUnset(obj.Spec.VolumeAttributesClassName),
+		obj.Status.Reason,
+		translateTimestampSince(obj.CreationTimestamp),
+		valueOrUnset(obj.Spec.VolumeMode),
+	}}
+}
+
+func printPersistentVolumeClaim(obj *corev1.PersistentVolumeClaim) metav1.TableRow {
+	phase := string(obj.Status.Phase)
+	if obj.DeletionTimestamp != nil {
+		phase = "Terminating"
+	}
+	var capacity, accessModes string
+	if obj.Spec.VolumeName != "" {
+		accessModes = accessModesAsString(obj.Status.AccessModes)
+		storage := obj.Status.Capacity[corev1.ResourceStorage]
+		capacity = storage.String()
+	}
+	storageClass := ""
+	if class, ok := obj.Annotations[betaStorageClassAnnotation]; ok {
+		storageClass = class
+	} else if obj.Spec.StorageClassName != nil {
+		storageClass = *obj.Spec.StorageClassName
+	}
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		phase,
+		obj.Spec.VolumeName,
+		capacity,
+		accessModes,
+		storageClass,
+		valueOrUnset(obj.Spec.VolumeAttributesClassName),
+		translateTimestampSince(obj.CreationTimestamp),
+		valueOrUnset(obj.Spec.VolumeMode),
+	}}
+}
+
+// accessModesAsString returns the abbreviations of the given access modes, such as RWO,ROX.
+func accessModesAsString(modes []corev1.PersistentVolumeAccessMode) string {
+	var abbreviations []string
+	for _, m := range []struct {
+		mode         corev1.PersistentVolumeAccessMode
+		abbreviation string
+	}{
+		{corev1.ReadWriteOnce, "RWO"},
+		{corev1.ReadOnlyMany, "ROX"},
+		{corev1.ReadWriteMany, "RWX"},
+		{corev1.ReadWriteOncePod, "RWOP"},
+	} {
+		if slices.Contains(modes, m.mode) {
+			abbreviations = append(abbreviations, m.abbreviation)
+		}
+	}
+	return strings.Join(abbreviations, ",")
+}
+
+func printReplicationController(obj *corev1.ReplicationController) metav1.TableRow {
+	containers, images := containerNamesAndImages(obj.Spec.Template)
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		int64(desiredReplicas(obj.Spec.Replicas)),
+		int64(obj.Status.Replicas),
+		int64(obj.Status.ReadyReplicas),
+		translateTimestampSince(obj.CreationTimestamp),
+		containers,
+		images,
+		labels.FormatLabels(obj.Spec.Selector),
+	}}
+}
+
+func printPriorityClass(obj *schedulingv1.PriorityClass) metav1.TableRow {
+	preemptionPolicy := string(corev1.PreemptLowerPriority)
+	if obj.PreemptionPolicy != nil {
+		preemptionPolicy = string(*obj.PreemptionPolicy)
+	}
+	return metav1.TableRow{Cells: []any{obj.Name, int64(obj.Value), obj.GlobalDefault, translateTimestampSince(obj.CreationTimestamp), preemptionPolicy}}
+}
+
+func printLease(obj *coordinationv1.Lease) metav1.TableRow {
+	holder := ""
+	if obj.Spec.HolderIdentity != nil {
+		holder = *obj.Spec.HolderIdentity
+	}
+	return metav1.TableRow{Cells: []any{obj.Name, holder, translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+// printEvent prints the cells of an event like `kubectl get events`, falling back to the deprecated core/v1 fields
+// for events that were recorded through the core API.
+func printEvent(obj *eventsv1.Event) metav1.TableRow {
+	firstSeen := translateTimestampSince(obj.DeprecatedFirstTimestamp)
+	if obj.DeprecatedFirstTimestamp.IsZero() {
+		firstSeen = translateMicroTimestampSince(obj.EventTime)
+	}
+	lastSeen := translateTimestampSince(obj.DeprecatedLastTimestamp)
+	if obj.DeprecatedLastTimestamp.IsZero() {
+		lastSeen = firstSeen
+	}
+	count := obj.DeprecatedCount
+	if obj.Series != nil {
+		lastSeen = translateMicroTimestampSince(obj.Series.LastObservedTime)
+		count = obj.Series.Count
+	} else if count == 0 {
+		count = 1
+	}
+	target := strings.ToLower(obj.Regarding.Kind)
+	if obj.Regarding.Name != "" {
+		target += "/" + obj.Regarding.Name
+	}
+	component := obj.DeprecatedSource.Component
+	if component == "" {
+		component = obj.ReportingController
+	}
+	host := obj.DeprecatedSource.Host
+	if host == "" {
+		host = obj.ReportingInstance
+	}
+	source := component
+	if component != "" && host != "" {
+		source += ", " + host
+	}
+	return metav1.TableRow{Cells: []any{
+		lastSeen,
+		obj.Type,
+		obj.Reason,
+		target,
+		obj.Regarding.FieldPath,
+		source,
+		strings.TrimSpace(obj.Note),
+		firstSeen,
+		int64(count),
+		obj.Name,
+	}}
+}
+
+// printCoreEvent prints the cells of a core/v1 event like printEvent.
+func printCoreEvent(obj *corev1.Event) metav1.TableRow {
+	return printEvent(eventsink.FromCoreV1(obj))
+}
+
+func printDeployment(obj *appsv1.Deployment) metav1.TableRow {
+	containers, images := containerNamesAndImages(&obj.Spec.Template)
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		fmt.Sprintf("%d/%d", obj.Status.ReadyReplicas, desiredReplicas(obj.Spec.Replicas)),
+		int64(obj.Status.UpdatedReplicas),
+		int64(obj.Status.AvailableReplicas),
+		translateTimestampSince(obj.CreationTimestamp),
+		containers,
+		images,
+		metav1.FormatLabelSelector(obj.Spec.Selector),
+	}}
+}
+
+func printReplicaSet(obj *appsv1.ReplicaSet) metav1.TableRow {
+	containers, images := containerNamesAndImages(&obj.Spec.Template)
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		int64(desiredReplicas(obj.Spec.Replicas)),
+		int64(obj.Status.Replicas),
+		int64(obj.Status.ReadyReplicas),
+		translateTimestampSince(obj.CreationTimestamp),
+		containers,
+		images,
+		metav1.FormatLabelSelector(obj.Spec.Selector),
+	}}
+}
+
+func printStatefulSet(obj *appsv1.StatefulSet) metav1.TableRow {
+	containers, images := containerNamesAndImages(&obj.Spec.Template)
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		fmt.Sprintf("%d/%d", obj.Status.ReadyReplicas, desiredReplicas(obj.Spec.Replicas)),
+		translateTimestampSince(obj.CreationTimestamp),
+		containers,
+		images,
+	}}
+}
+
+// desiredReplicas returns the given replicas of a workload, which default to 1 in the kube-apiserver.
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// containerNamesAndImages returns the comma separated names and images of the containers of the given pod template.
+func containerNamesAndImages(template *corev1.PodTemplateSpec) (names, images string) {
+	if template == nil {
+		return
+	}
+	var nameList, imageList []string
+	for _, container := range template.Spec.Containers {
+		nameList = append(nameList, container.Name)
+		imageList = append(imageList, container.Image)
+	}
+	return strings.Join(nameList, ","), strings.Join(imageList, ",")
+}
+
+func printPodDisruptionBudget(obj *policyv1.PodDisruptionBudget) metav1.TableRow {
+	minAvailable, maxUnavailable := "N/A", "N/A"
+	if obj.Spec.MinAvailable != nil {
+		minAvailable = obj.Spec.MinAvailable.String()
+	}
+	if obj.Spec.MaxUnavailable != nil {
+		maxUnavailable = obj.Spec.MaxUnavailable.String()
+	}
+	return metav1.TableRow{Cells: []any{obj.Name, minAvailable, maxUnavailable, int64(obj.Status.DisruptionsAllowed), translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printStorageClass(obj *storagev1.StorageClass) metav1.TableRow {
+	name := obj.Name
+	if obj.Annotations[isDefaultStorageClassAnnotation] == "true" || obj.Annotations[betaIsDefaultStorageClassAnnotation] == "true" {
+		name += " (default)"
+	}
+	reclaimPolicy := string(corev1.PersistentVolumeReclaimDelete)
+	if obj.ReclaimPolicy != nil {
+		reclaimPolicy = string(*obj.ReclaimPolicy)
+	}
+	volumeBindingMode := string(storagev1.VolumeBindingImmediate)
+	if obj.VolumeBindingMode != nil {
+		volumeBindingMode = string(*obj.VolumeBindingMode)
+	}
+	allowVolumeExpansion := obj.AllowVolumeExpansion != nil && *obj.AllowVolumeExpansion
+	return metav1.TableRow{Cells: []any{name, obj.Provisioner, reclaimPolicy, volumeBindingMode, allowVolumeExpansion, translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printCSIDriver(obj *storagev1.CSIDriver) metav1.TableRow {
+	var modes []string
+	for _, mode := range obj.Spec.VolumeLifecycleModes {
+		modes = append(modes, string(mode))
+	}
+	tokenRequests := "<unset>"
+	if obj.Spec.TokenRequests != nil {
+		audiences := make([]string, len(obj.Spec.TokenRequests))
+		for i, t := range obj.Spec.TokenRequests {
+			audiences[i] = t.Audience
+		}
+		tokenRequests = strings.Join(audiences, ",")
+	}
+	return metav1.TableRow{Cells: []any{
+		obj.Name,
+		obj.Spec.AttachRequired == nil || *obj.Spec.AttachRequired,
+		obj.Spec.PodInfoOnMount != nil && *obj.Spec.PodInfoOnMount,
+		obj.Spec.StorageCapacity != nil && *obj.Spec.StorageCapacity,
+		tokenRequests,
+		obj.Spec.RequiresRepublish != nil && *obj.Spec.RequiresRepublish,
+		valueOrNone(strings.Join(modes, ",")),
+		translateTimestampSince(obj.CreationTimestamp),
+	}}
+}
+
+func printCSIStorageCapacity(obj *storagev1.CSIStorageCapacity) metav1.TableRow {
+	capacity := "<unset>"
+	if obj.Capacity != nil {
+		capacity = obj.Capacity.String()
+	}
+	return metav1.TableRow{Cells: []any{obj.Name, obj.StorageClassName, capacity}}
+}
+
+func printCSINode(obj *storagev1.CSINode) metav1.TableRow {
+	return metav1.TableRow{Cells: []any{obj.Name, int64(len(obj.Spec.Drivers)), translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func printVolumeAttachment(obj *storagev1.VolumeAttachment) metav1.TableRow {
+	pvName := ""
+	if obj.Spec.Source.PersistentVolumeName != nil {
+		pvName = *obj.Spec.Source.PersistentVolumeName
+	}
+	return metav1.TableRow{Cells: []any{obj.Name, obj.Spec.Attacher, pvName, obj.Spec.NodeName, obj.Status.Attached, translateTimestampSince(obj.CreationTimestamp)}}
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "<unknown>"
+	}
+	return s
+}
+
+// valueOrUnset returns the value of the given optional string field, or <unset> if it is not set.
+func valueOrUnset[T ~string](s *T) string {
+	if s == nil || *s == "" {
+		return "<unset>"
+	}
+	return string(*s)
+}
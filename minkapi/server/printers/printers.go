@@ -0,0 +1,287 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package printers converts objects into metav1.Table responses with the default columns of the kube-apiserver, so that
+// clients such as kubectl can print them in their human-readable form.
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metatable "k8s.io/apimachinery/pkg/api/meta/table"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Options configure the conversion of objects into a metav1.Table.
+type Options struct {
+	// NoHeaders omits the column definitions from the table. Watches only send them with their first event.
+	NoHeaders bool
+	// IncludeObject determines how the objects are embedded in the rows of the table. Defaults to
+	// metav1.IncludeMetadata, which embeds their metav1.PartialObjectMetadata.
+	IncludeObject metav1.IncludeObjectPolicy
+}
+
+// ParseIncludeObject parses the value of the includeObject query parameter. A BadRequest StatusError is returned for
+// unsupported values.
+func ParseIncludeObject(value string) (metav1.IncludeObjectPolicy, error) {
+	switch policy := metav1.IncludeObjectPolicy(value); policy {
+	case "":
+		return metav1.IncludeMetadata, nil
+	case metav1.IncludeNone, metav1.IncludeMetadata, metav1.IncludeObject:
+		return policy, nil
+	default:
+		return "", apierrors.NewBadRequest(fmt.Sprintf("includeObject must be one of %q, %q or %q",
+			metav1.IncludeNone, metav1.IncludeMetadata, metav1.IncludeObject))
+	}
+}
+
+// ConvertToTable converts the given object or list of objects of the kind described by the given descriptor into a
+// metav1.Table with one row per object.
+func ConvertToTable(d typeinfo.Descriptor, obj runtime.Object, opts Options) (*metav1.Table, error) {
+	p, err := printerFor(d)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: metav1.SchemeGroupVersion.String()},
+	}
+	if !opts.NoHeaders {
+		table.ColumnDefinitions = p.columns
+	}
+	var items []runtime.Object
+	if meta.IsListType(obj) {
+		if items, err = meta.ExtractList(obj); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		listMeta, err := meta.ListAccessor(obj)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		table.ResourceVersion = listMeta.GetResourceVersion()
+		table.Continue = listMeta.GetContinue()
+		table.RemainingItemCount = listMeta.GetRemainingItemCount()
+	} else {
+		mo, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		table.ResourceVersion = mo.GetResourceVersion()
+		items = []runtime.Object{obj}
+	}
+	table.Rows = make([]metav1.TableRow, 0, len(items))
+	for _, item := range items {
+		row, err := p.printRow(item)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		if row.Object, err = embedObject(item, opts.IncludeObject); err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// embedObject returns the given object to be embedded in a table row according to the given policy.
+func embedObject(obj runtime.Object, policy metav1.IncludeObjectPolicy) (ext runtime.RawExtension, err error) {
+	var embedded any
+	switch policy {
+	case metav1.IncludeNone:
+		return
+	case metav1.IncludeObject:
+		embedded = obj
+	default:
+		mo, err := meta.Accessor(obj)
+		if err != nil {
+			return ext, err
+		}
+		partial := meta.AsPartialObjectMetadata(mo)
+		partial.TypeMeta = metav1.TypeMeta{Kind: "PartialObjectMetadata", APIVersion: metav1.SchemeGroupVersion.String()}
+		embedded = partial
+	}
+	// rows are encoded in JSON, which only includes the raw bytes of a runtime.RawExtension.
+	ext.Raw, err = json.Marshal(embedded)
+	return
+}
+
+// printer holds the column definitions of a kind and prints the cells of the rows of its objects.
+type printer struct {
+	columns  []metav1.TableColumnDefinition
+	printRow func(obj runtime.Object) (metav1.TableRow, error)
+}
+
+// newPrinter returns a printer for the objects of type T whose rows are printed by the given printRow func.
+func newPrinter[T runtime.Object](columns []metav1.TableColumnDefinition, printRow func(obj T) metav1.TableRow) printer {
+	return printer{
+		columns: columns,
+		printRow: func(obj runtime.Object) (metav1.TableRow, error) {
+			typed, ok := obj.(T)
+			if !ok {
+				return metav1.TableRow{}, fmt.Errorf("cannot print object of type %T, expected %T", obj, typed)
+			}
+			return printRow(typed), nil
+		},
+	}
+}
+
+// printerFor returns the printer of the kind described by the given descriptor. Custom resources are printed with the
+// additional printer columns of their CustomResourceDefinition, and other kinds with their name and creation time.
+func printerFor(d typeinfo.Descriptor) (printer, error) {
+	if p, ok := builtinPrinters[d.GVK]; ok {
+		return p, nil
+	}
+	if !typeinfo.SupportedScheme.Recognizes(d.GVK) {
+		return newCustomResourcePrinter(d.PrinterColumns)
+	}
+	return defaultPrinter, nil
+}
+
+var (
+	nameColumn = metav1.TableColumnDefinition{
+		Name:        "Name",
+		Type:        "string",
+		Format:      "name",
+		Description: metav1.ObjectMeta{}.SwaggerDoc()["name"],
+	}
+	ageColumn = metav1.TableColumnDefinition{
+		Name:        "Age",
+		Type:        "string",
+		Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"],
+	}
+
+	// defaultPrinter prints the objects of kinds without dedicated columns like the default table convertor of the
+	// kube-apiserver.
+	defaultPrinter = printer{
+		columns: []metav1.TableColumnDefinition{
+			nameColumn,
+			{Name: "Created At", Type: "date", Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"]},
+		},
+		printRow: func(obj runtime.Object) (metav1.TableRow, error) {
+			mo, err := meta.Accessor(obj)
+			if err != nil {
+				return metav1.TableRow{}, err
+			}
+			return metav1.TableRow{Cells: []any{mo.GetName(), mo.GetCreationTimestamp().UTC().Format(time.RFC3339)}}, nil
+		},
+	}
+)
+
+// customResourceAgeColumn is the printer column of custom resources whose CustomResourceDefinition defines none.
+var customResourceAgeColumn = apiextensionsv1.CustomResourceColumnDefinition{
+	Name:        "Age",
+	Type:        "date",
+	Description: metav1.ObjectMeta{}.SwaggerDoc()["creationTimestamp"],
+	JSONPath:    ".metadata.creationTimestamp",
+}
+
+// newCustomResourcePrinter returns a printer for custom resources with the given additional printer columns, which
+// evaluates the JSON paths of the columns like the apiextensions-apiserver.
+func newCustomResourcePrinter(crdColumns []apiextensionsv1.CustomResourceColumnDefinition) (printer, error) {
+	if len(crdColumns) == 0 {
+		crdColumns = []apiextensionsv1.CustomResourceColumnDefinition{customResourceAgeColumn}
+	}
+	columns := []metav1.TableColumnDefinition{nameColumn}
+	paths := make([]*jsonpath.JSONPath, len(crdColumns))
+	for i, col := range crdColumns {
+		paths[i] = jsonpath.New(col.Name).AllowMissingKeys(true)
+		if err := paths[i].Parse(fmt.Sprintf("{%s}", col.JSONPath)); err != nil {
+			return printer{}, fmt.Errorf("unrecognized column definition %q: %w", col.JSONPath, err)
+		}
+		columns = append(columns, metav1.TableColumnDefinition{
+			Name:        col.Name,
+			Type:        col.Type,
+			Format:      col.Format,
+			Description: col.Description,
+			Priority:    col.Priority,
+		})
+	}
+	return printer{
+		columns: columns,
+		printRow: func(obj runtime.Object) (metav1.TableRow, error) {
+			u, ok := obj.(runtime.Unstructured)
+			if !ok {
+				return metav1.TableRow{}, fmt.Errorf("cannot print object of type %T as a custom resource", obj)
+			}
+			content := u.UnstructuredContent()
+			name, _, _ := unstructured.NestedString(content, "metadata", "name")
+			cells := []any{name}
+			for i, path := range paths {
+				cells = append(cells, customResourceCell(path, crdColumns[i].Type, content))
+			}
+			return metav1.TableRow{Cells: cells}, nil
+		},
+	}, nil
+}
+
+// customResourceCell returns the cell of a custom resource column of the given type whose value is found at the given
+// path of the given content, or nil if there is no value.
+func customResourceCell(path *jsonpath.JSONPath, columnType string, content map[string]any) any {
+	results, err := path.FindResults(content)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+	// only simple JSON paths are supported, so there is a single result.
+	value := results[0][0].Interface()
+	switch columnType {
+	case "string":
+		var buf bytes.Buffer
+		if err = path.PrintResults(&buf, []reflect.Value{reflect.ValueOf(value)}); err != nil {
+			return nil
+		}
+		return buf.String()
+	case "integer":
+		switch typed := value.(type) {
+		case int64:
+			return typed
+		case float64:
+			return int64(typed)
+		}
+	case "number":
+		switch typed := value.(type) {
+		case int64:
+			return float64(typed)
+		case float64:
+			return typed
+		}
+	case "boolean":
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case "date":
+		if s, ok := value.(string); ok {
+			var ts metav1.Time
+			if err = ts.UnmarshalQueryParameter(s); err != nil {
+				return "<invalid>"
+			}
+			return translateTimestampSince(ts)
+		}
+	}
+	return nil
+}
+
+// translateTimestampSince returns the elapsed time since the given timestamp in a human-readable form.
+func translateTimestampSince(ts metav1.Time) string {
+	return metatable.ConvertToHumanReadableDateType(ts)
+}
+
+// translateMicroTimestampSince is like translateTimestampSince for a metav1.MicroTime.
+func translateMicroTimestampSince(ts metav1.MicroTime) string {
+	if ts.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(ts.Time))
+}
@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package printers
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func TestConvertToTable(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	podList := &corev1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "7", Continue: "next"},
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", CreationTimestamp: created, Labels: map[string]string{"app": "a"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", CreationTimestamp: created}},
+		},
+	}
+	tests := map[string]struct {
+		d             typeinfo.Descriptor
+		obj           runtime.Object
+		opts          Options
+		wantColumns   []string
+		wantRows      int
+		wantRV        string
+		wantEmbedded  string
+		wantFirstCell any
+	}{
+		"pod list with metadata": {
+			d:             typeinfo.PodsDescriptor,
+			obj:           podList,
+			opts:          Options{IncludeObject: metav1.IncludeMetadata},
+			wantColumns:   []string{"Name", "Ready", "Status", "Restarts", "Age", "IP", "Node", "Nominated Node", "Readiness Gates"},
+			wantRows:      2,
+			wantRV:        "7",
+			wantEmbedded:  "PartialObjectMetadata",
+			wantFirstCell: "a",
+		},
+		"single pod with object": {
+			d:             typeinfo.PodsDescriptor,
+			obj:           &corev1.Pod{TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "a", ResourceVersion: "3"}},
+			opts:          Options{IncludeObject: metav1.IncludeObject},
+			wantColumns:   []string{"Name", "Ready", "Status", "Restarts", "Age", "IP", "Node", "Nominated Node", "Readiness Gates"},
+			wantRows:      1,
+			wantRV:        "3",
+			wantEmbedded:  "Pod",
+			wantFirstCell: "a",
+		},
+		"pod without headers and object": {
+			d:             typeinfo.PodsDescriptor,
+			obj:           &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a"}},
+			opts:          Options{NoHeaders: true, IncludeObject: metav1.IncludeNone},
+			wantRows:      1,
+			wantFirstCell: "a",
+		},
+		"kind without dedicated columns": {
+			d:             typeinfo.RolesDescriptor,
+			obj:           &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "r", CreationTimestamp: created}},
+			wantColumns:   []string{"Name", "Created At"},
+			wantRows:      1,
+			wantEmbedded:  "PartialObjectMetadata",
+			wantFirstCell: "r",
+		},
+		"deployment": {
+			d:             typeinfo.DeploymentDescriptor,
+			obj:           &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "d"}},
+			wantColumns:   []string{"Name", "Ready", "Up-to-date", "Available", "Age", "Containers", "Images", "Selector"},
+			wantRows:      1,
+			wantEmbedded:  "PartialObjectMetadata",
+			wantFirstCell: "d",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			table, err := ConvertToTable(tc.d, tc.obj, tc.opts)
+			if err != nil {
+				t.Fatalf("failed to convert to table: %v", err)
+			}
+			if table.Kind != "Table" || table.APIVersion != "meta.k8s.io/v1" {
+				t.Errorf("expected kind Table and apiVersion meta.k8s.io/v1, got %q and %q", table.Kind, table.APIVersion)
+			}
+			var columns []string
+			for _, c := range table.ColumnDefinitions {
+				columns = append(columns, c.Name)
+			}
+			if !slices.Equal(columns, tc.wantColumns) {
+				t.Errorf("expected columns %v, got %v", tc.wantColumns, columns)
+			}
+			if len(table.Rows) != tc.wantRows {
+				t.Fatalf("expected %d rows, got %d", tc.wantRows, len(table.Rows))
+			}
+			if table.ResourceVersion != tc.wantRV {
+				t.Errorf("expected resourceVersion %q, got %q", tc.wantRV, table.ResourceVersion)
+			}
+			row := table.Rows[0]
+			if row.Cells[0] != tc.wantFirstCell {
+				t.Errorf("expected first cell %v, got %v", tc.wantFirstCell, row.Cells[0])
+			}
+			if tc.wantColumns != nil && len(row.Cells) != len(tc.wantColumns) {
+				t.Errorf("expected %d cells, got %d", len(tc.wantColumns), len(row.Cells))
+			}
+			var embedded metav1.TypeMeta
+			if len(row.Object.Raw) > 0 {
+				if err = json.Unmarshal(row.Object.Raw, &embedded); err != nil {
+					t.Fatalf("failed to decode embedded object: %v", err)
+				}
+			}
+			if embedded.Kind != tc.wantEmbedded {
+				t.Errorf("expected embedded object of kind %q, got %q", tc.wantEmbedded, embedded.Kind)
+			}
+		})
+	}
+}
+
+func TestPrintPod(t *testing.T) {
+	deleted := metav1.Now()
+	tests := map[string]struct {
+		pod         corev1.Pod
+		wantReady   string
+		wantStatus  string
+		wantRestart string
+		wantNode    string
+	}{
+		"pending": {
+			pod:         corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c"}}}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			wantReady:   "0/1",
+			wantStatus:  "Pending",
+			wantRestart: "0",
+			wantNode:    "<none>",
+		},
+		"scheduling gated": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodPending, Conditions: []corev1.PodCondition{
+					{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonSchedulingGated},
+				}},
+			},
+			wantReady:   "0/1",
+			wantStatus:  "SchedulingGated",
+			wantRestart: "0",
+			wantNode:    "<none>",
+		},
+		"running": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "n1", Containers: []corev1.Container{{Name: "c1"}, {Name: "c2"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "c1", Ready: true, RestartCount: 2, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "c2", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}},
+			},
+			wantReady:   "2/2",
+			wantStatus:  "Running",
+			wantRestart: "2",
+			wantNode:    "n1",
+		},
+		"crash loop": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{NodeName: "n1", Containers: []corev1.Container{{Name: "c"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+					Name:                 "c",
+					RestartCount:         3,
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(time.Now().Add(-5 * time.Minute))}},
+				}}},
+			},
+			wantReady:   "0/1",
+			wantStatus:  "CrashLoopBackOff",
+			wantRestart: "3 (5m ago)",
+			wantNode:    "n1",
+		},
+		"init container running": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "i1"}, {Name: "i2"}}, Containers: []corev1.Container{{Name: "c"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "i1", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+					{Name: "i2", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}},
+			},
+			wantReady:   "0/1",
+			wantStatus:  "Init:1/2",
+			wantRestart: "0",
+			wantNode:    "<none>",
+		},
+		"sidecar started": {
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "s", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)}},
+					Containers:     []corev1.Container{{Name: "c"}},
+				},
+				Status: corev1.PodStatus{
+					Phase:                 corev1.PodRunning,
+					InitContainerStatuses: []corev1.ContainerStatus{{Name: "s", Ready: true, Started: ptr.To(true), State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+					ContainerStatuses:     []corev1.ContainerStatus{{Name: "c", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+				},
+			},
+			wantReady:   "2/2",
+			wantStatus:  "Running",
+			wantRestart: "0",
+			wantNode:    "<none>",
+		},
+		"terminating": {
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted},
+				Spec:       corev1.PodSpec{NodeName: "n1", Containers: []corev1.Container{{Name: "c"}}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			wantReady:   "0/1",
+			wantStatus:  "Terminating",
+			wantRestart: "0",
+			wantNode:    "n1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			row := printPod(&tc.pod)
+			if row.Cells[1] != tc.wantReady || row.Cells[2] != tc.wantStatus || row.Cells[3] != tc.wantRestart || row.Cells[6] != tc.wantNode {
+				t.Errorf("expected ready %q, status %q, restarts %q and node %q, got %q, %q, %q and %q",
+					tc.wantReady, tc.wantStatus, tc.wantRestart, tc.wantNode, row.Cells[1], row.Cells[2], row.Cells[3], row.Cells[6])
+			}
+		})
+	}
+}
+
+func TestPrintNode(t *testing.T) {
+	tests := map[string]struct {
+		node       corev1.Node
+		wantStatus string
+		wantRoles  string
+	}{
+		"ready worker": {
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+				Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+			},
+			wantStatus: "Ready",
+			wantRoles:  "worker",
+		},
+		"cordoned control plane": {
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"node-role.kubernetes.io/control-plane": "", "kubernetes.io/role": "master"}},
+				Spec:       corev1.NodeSpec{Unschedulable: true},
+				Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}}},
+			},
+			wantStatus: "NotReady,SchedulingDisabled",
+			wantRoles:  "control-plane,master",
+		},
+		"without conditions": {
+			wantStatus: "Unknown",
+			wantRoles:  "<none>",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			row := printNode(&tc.node)
+			if row.Cells[1] != tc.wantStatus || row.Cells[2] != tc.wantRoles {
+				t.Errorf("expected status %q and roles %q, got %q and %q", tc.wantStatus, tc.wantRoles, row.Cells[1], row.Cells[2])
+			}
+		})
+	}
+}
+
+func TestCustomResourcePrinter(t *testing.T) {
+	widget := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w", "creationTimestamp": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)},
+		"spec":       map[string]any{"size": int64(3), "color": "blue"},
+	}}
+	tests := map[string]struct {
+		columns     []apiextensionsv1.CustomResourceColumnDefinition
+		wantColumns []string
+		wantCells   []any
+		wantErr     bool
+	}{
+		"default age column": {
+			wantColumns: []string{"Name", "Age"},
+			wantCells:   []any{"w", "60m"},
+		},
+		"additional printer columns": {
+			columns: []apiextensionsv1.CustomResourceColumnDefinition{
+				{Name: "Color", Type: "string", JSONPath: ".spec.color"},
+				{Name: "Size", Type: "integer", JSONPath: ".spec.size"},
+				{Name: "Missing", Type: "string", JSONPath: ".status.phase"},
+			},
+			wantColumns: []string{"Name", "Color", "Size", "Missing"},
+			wantCells:   []any{"w", "blue", int64(3), nil},
+		},
+		"invalid json path": {
+			columns: []apiextensionsv1.CustomResourceColumnDefinition{{Name: "Bad", Type: "string", JSONPath: ".spec[["}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := typeinfo.NewDescriptor("Widget", "WidgetList", false, widget.GroupVersionKind().GroupVersion().WithResource("widgets"))
+			d.PrinterColumns = tc.columns
+			table, err := ConvertToTable(d, widget, Options{})
+			if tc.wantErr {
+				if !apierrors.IsInternalError(err) {
+					t.Fatalf("expected internal error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to convert to table: %v", err)
+			}
+			var columns []string
+			for _, c := range table.ColumnDefinitions {
+				columns = append(columns, c.Name)
+			}
+			if !slices.Equal(columns, tc.wantColumns) {
+				t.Errorf("expected columns %v, got %v", tc.wantColumns, columns)
+			}
+			if !slices.Equal(table.Rows[0].Cells, tc.wantCells) {
+				t.Errorf("expected cells %v, got %v", tc.wantCells, table.Rows[0].Cells)
+			}
+		})
+	}
+}
+
+func TestParseIncludeObject(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    metav1.IncludeObjectPolicy
+		wantErr bool
+	}{
+		"default":     {value: "", want: metav1.IncludeMetadata},
+		"none":        {value: "None", want: metav1.IncludeNone},
+		"object":      {value: "Object", want: metav1.IncludeObject},
+		"unsupported": {value: "All", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseIncludeObject(tc.value)
+			if tc.wantErr != apierrors.IsBadRequest(err) {
+				t.Fatalf("expected bad request error %t, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/configtmpl"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	caCertFileName     = "ca.crt"
+	caKeyFileName      = "ca.key"
+	serverCertFileName = "server.crt"
+	serverKeyFileName  = "server.key"
+	clientCertFileName = "client.crt"
+	clientKeyFileName  = "client.key"
+	tokenFileName      = "token"
+
+	// otherAccessPerms are the permissions by which other users can access a file. Files holding keys or tokens must
+	// not grant any of them.
+	otherAccessPerms fs.FileMode = 0077
+	// otherWritePerms are the permissions by which other users can write a file or directory. Certificates and the cert
+	// dir must not grant any of them.
+	otherWritePerms fs.FileMode = 0022
+
+	clientUserName = "minkapi-user"
+	caValidity     = 10 * 365 * 24 * time.Hour
+	leafValidity   = 365 * 24 * time.Hour
+)
+
+// security holds the TLS and authentication material of the minkapi service.
+type security struct {
+	authMode mkapi.AuthMode
+	// tlsConfig is nil if TLS is disabled.
+	tlsConfig     *tls.Config
+	caCertPEM     []byte
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+	token         string
+}
+
+// newSecurity sets up the TLS and authentication material for the given mkapi.SecurityConfig, whose defaults are
+// expected to be set. Certificates, keys and the token are written to the configured paths so that clients other than
+// those using the generated kubeconfigs can use them.
+func newSecurity(host string, cfg mkapi.SecurityConfig) (s *security, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%w: %w", mkapi.ErrSetupSecurity, err)
+		}
+	}()
+	s = &security{authMode: cfg.AuthMode}
+	switch cfg.AuthMode {
+	case mkapi.AuthModeNone, mkapi.AuthModeToken:
+	case mkapi.AuthModeClientCert:
+		if !cfg.TLSEnabled {
+			return nil, fmt.Errorf("auth mode %q requires TLS", cfg.AuthMode)
+		}
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", cfg.AuthMode)
+	}
+	if cfg.AuthMode == mkapi.AuthModeToken {
+		if s.token, err = loadOrCreateToken(cfg.TokenFile); err != nil {
+			return nil, err
+		}
+	}
+	if !cfg.TLSEnabled {
+		return s, nil
+	}
+	if err = os.MkdirAll(cfg.CertDir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create cert dir %q: %w", cfg.CertDir, err)
+	}
+	certDirInfo, err := os.Stat(cfg.CertDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat cert dir %q: %w", cfg.CertDir, err)
+	}
+	if err = checkPrivate(cfg.CertDir, certDirInfo, otherWritePerms); err != nil {
+		return nil, err
+	}
+	caCert, caKey, err := loadOrCreateCA(cfg.CertDir)
+	if err != nil {
+		return nil, err
+	}
+	s.caCertPEM = encodeCertPEM(caCert.Raw)
+	serverTemplate := newLeafCertTemplate(mkapi.ProgramName, x509.ExtKeyUsageServerAuth)
+	serverTemplate.DNSNames, serverTemplate.IPAddresses = serverSANs(host)
+	serverCertPEM, serverKeyPEM, err := issueCert(cfg.CertDir, serverCertFileName, serverKeyFileName, serverTemplate, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	s.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.AuthMode == mkapi.AuthModeClientCert {
+		clientTemplate := newLeafCertTemplate(clientUserName, x509.ExtKeyUsageClientAuth)
+		if s.clientCertPEM, s.clientKeyPEM, err = issueCert(cfg.CertDir, clientCertFileName, clientKeyFileName, clientTemplate, caCert, caKey); err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(caCert)
+		s.tlsConfig.ClientCAs = clientCAs
+		// Like the kube-apiserver, requests without a client certificate are rejected with 401 Unauthorized by
+		// authenticate rather than failing the TLS handshake.
+		s.tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return s, nil
+}
+
+// scheme returns the URL scheme under which the minkapi service is served.
+func (s *security) scheme() string {
+	if s.tlsConfig != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// kubeConfigParams returns the params of a kubeconfig for the view with the given name served at the given URL which
+// embeds the CA and the client credentials.
+func (s *security) kubeConfigParams(name, kubeConfigPath, url string) configtmpl.KubeConfigParams {
+	params := configtmpl.KubeConfigParams{
+		Name:           name,
+		KubeConfigPath: kubeConfigPath,
+		URL:            url,
+		Token:          s.token,
+	}
+	if s.caCertPEM != nil {
+		params.CertificateAuthorityData = base64.StdEncoding.EncodeToString(s.caCertPEM)
+	}
+	if s.clientCertPEM != nil {
+		params.ClientCertificateData = base64.StdEncoding.EncodeToString(s.clientCertPEM)
+		params.ClientKeyData = base64.StdEncoding.EncodeToString(s.clientKeyPEM)
+	}
+	return params
+}
+
+// publicInfoEndpoints are the endpoints that, like the public info of the kube-apiserver, are served without
+// authentication, so that minkapi can be probed by clients that hold no credentials.
+var publicInfoEndpoints = sets.New("healthz", "livez", "readyz", "version")
+
+// isPublicInfoPath returns whether the given request path is exactly that of a public info endpoint, either served
+// without a path prefix or under the path prefix of a view for which isView returns true, such as /healthz or
+// /base/readyz. The endpoints of individual health checks, like /readyz/stores, are not public.
+func isPublicInfoPath(path string, isView func(name string) bool) bool {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch len(segments) {
+	case 1:
+		return publicInfoEndpoints.Has(segments[0])
+	case 2:
+		return publicInfoEndpoints.Has(segments[1]) && isView(segments[0])
+	default:
+		return false
+	}
+}
+
+// authenticate wraps the given handler rejecting requests that are not authenticated according to the auth mode
+// with 401 Unauthorized. Requests to public info endpoints, whose view prefixes are checked with isView, are not
+// authenticated.
+func (s *security) authenticate(isView func(name string) bool, next http.Handler) http.Handler {
+	if s.authMode == mkapi.AuthModeNone {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isPublicInfoPath(r.URL.Path, isView) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var reason string
+		switch s.authMode {
+		case mkapi.AuthModeToken:
+			token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) != 1 {
+				reason = "missing or invalid bearer token"
+			}
+		case mkapi.AuthModeClientCert:
+			// the TLS handshake only accepts client certificates issued by the CA and verifies their chains.
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+				reason = "missing or invalid client certificate"
+			}
+		}
+		if reason != "" {
+			writeStatusError(w, r, apierrors.NewUnauthorized(reason))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loadOrCreateToken returns the bearer token held by the given tokenFile, generating a random token and writing it to
+// the file if it does not exist.
+func loadOrCreateToken(tokenFile string) (string, error) {
+	data, err := readPrivateFile(tokenFile, otherAccessPerms)
+	if err == nil {
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %q is empty", tokenFile)
+		}
+		return token, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("cannot load token file %q: %w", tokenFile, err)
+	}
+	tokenBytes := make([]byte, 32)
+	if _, err = rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	if err = os.MkdirAll(filepath.Dir(tokenFile), 0700); err != nil {
+		return "", fmt.Errorf("cannot create dir of token file %q: %w", tokenFile, err)
+	}
+	if err = os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+		return "", fmt.Errorf("cannot write token file %q: %w", tokenFile, err)
+	}
+	return token, nil
+}
+
+// loadOrCreateCA loads the CA from the given certDir, generating a self-signed CA and writing it to the certDir if it
+// does not exist. Reusing the CA keeps certificates trusted by clients valid across restarts.
+func loadOrCreateCA(certDir string) (*x509.Certificate, crypto.Signer, error) {
+	caCert, caKey, err := loadCA(certDir)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return caCert, caKey, err
+	}
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: mkapi.ProgramName + "-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if _, _, err = issueCert(certDir, caCertFileName, caKeyFileName, template, nil, nil); err != nil {
+		return nil, nil, err
+	}
+	return loadCA(certDir)
+}
+
+func loadCA(certDir string) (*x509.Certificate, crypto.Signer, error) {
+	certPath, keyPath := filepath.Join(certDir, caCertFileName), filepath.Join(certDir, caKeyFileName)
+	certPEM, err := readPrivateFile(certPath, otherWritePerms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load CA from %q: %w", certDir, err)
+	}
+	keyPEM, err := readPrivateFile(keyPath, otherAccessPerms)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load CA from %q: %w", certDir, err)
+	}
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load CA from %q: %w", certDir, err)
+	}
+	caKey, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("CA key %q of type %T cannot sign", keyPath, keyPair.PrivateKey)
+	}
+	caCert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse CA certificate %q: %w", certPath, err)
+	}
+	return caCert, caKey, nil
+}
+
+// readPrivateFile reads the file at the given path, refusing to do so unless it is owned by the current user and
+// grants none of the given permissions to other users. Otherwise, another local user could plant a CA or a token that
+// would be trusted.
+func readPrivateFile(path string, otherPerms fs.FileMode) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPrivate(path, info, otherPerms); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
+
+// checkPrivate returns an error unless the file with the given path and info is owned by the current user and grants
+// none of the given permissions to other users.
+func checkPrivate(path string, info fs.FileInfo, otherPerms fs.FileMode) error {
+	if uid, ok := fileOwner(info); ok && uid != os.Getuid() {
+		return fmt.Errorf("%q is owned by user %d instead of the current user %d", path, uid, os.Getuid())
+	}
+	if info.Mode().Perm()&otherPerms != 0 {
+		return fmt.Errorf("%q has permissions %v, which grant access to other users", path, info.Mode().Perm())
+	}
+	return nil
+}
+
+// defaultCertDir returns the default cert dir, which is private to the current user unlike the directory of the
+// default kubeconfig.
+func defaultCertDir() string {
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(cacheDir, mkapi.ProgramName)
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d", mkapi.ProgramName, os.Getuid()))
+}
+
+// issueCert generates a key and a certificate for the given template signed by the given CA, or self-signed if caCert
+// is nil, and writes them to the given files in the certDir.
+func issueCert(certDir, certFileName, keyFileName string, template, caCert *x509.Certificate, caKey crypto.Signer) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return
+	}
+	if template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)); err != nil {
+		return
+	}
+	if caCert == nil {
+		caCert, caKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create certificate %q: %w", template.Subject.CommonName, err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return
+	}
+	certPEM = encodeCertPEM(der)
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err = os.WriteFile(filepath.Join(certDir, certFileName), certPEM, 0644); err != nil {
+		return nil, nil, fmt.Errorf("cannot write certificate %q: %w", certFileName, err)
+	}
+	if err = os.WriteFile(filepath.Join(certDir, keyFileName), keyPEM, 0600); err != nil {
+		return nil, nil, fmt.Errorf("cannot write key %q: %w", keyFileName, err)
+	}
+	return
+}
+
+func newLeafCertTemplate(commonName string, extKeyUsage x509.ExtKeyUsage) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		NotBefore:   time.Now().Add(-time.Minute),
+		NotAfter:    time.Now().Add(leafValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{extKeyUsage},
+	}
+}
+
+// serverSANs returns the subject alternative names of the server certificate, which cover the given host as well as
+// the loopback addresses and the hostname.
+func serverSANs(host string) (dnsNames []string, ips []net.IP) {
+	dnsNames = []string{"localhost"}
+	ips = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		dnsNames = append(dnsNames, hostname)
+	}
+	// an unspecified host such as 0.0.0.0 is also included since it is used in the URLs of the generated kubeconfigs.
+	if ip := net.ParseIP(host); ip != nil {
+		if !ip.IsLoopback() {
+			ips = append(ips, ip)
+		}
+	} else if host != "" && host != "localhost" {
+		dnsNames = append(dnsNames, host)
+	}
+	return
+}
+
+func encodeCertPEM(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/configtmpl"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := map[string]struct {
+		authMode      mkapi.AuthMode
+		path          string
+		authorization string
+		tlsState      *tls.ConnectionState
+		wantCode      int
+	}{
+		"no auth": {
+			authMode: mkapi.AuthModeNone,
+			wantCode: http.StatusOK,
+		},
+		"valid token": {
+			authMode:      mkapi.AuthModeToken,
+			authorization: "Bearer secret",
+			wantCode:      http.StatusOK,
+		},
+		"missing token": {
+			authMode: mkapi.AuthModeToken,
+			wantCode: http.StatusUnauthorized,
+		},
+		"wrong token": {
+			authMode:      mkapi.AuthModeToken,
+			authorization: "Bearer other",
+			wantCode:      http.StatusUnauthorized,
+		},
+		"verified client certificate": {
+			authMode: mkapi.AuthModeClientCert,
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}},
+			wantCode: http.StatusOK,
+		},
+		"missing client certificate": {
+			authMode: mkapi.AuthModeClientCert,
+			tlsState: &tls.ConnectionState{},
+			wantCode: http.StatusUnauthorized,
+		},
+		"public info endpoint without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/readyz",
+			wantCode: http.StatusOK,
+		},
+		"public info endpoint of view without client certificate": {
+			authMode: mkapi.AuthModeClientCert,
+			path:     "/base/healthz",
+			tlsState: &tls.ConnectionState{},
+			wantCode: http.StatusOK,
+		},
+		"health check endpoint without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/base/readyz/stores",
+			wantCode: http.StatusUnauthorized,
+		},
+		"public info endpoint of unknown view without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/other/version",
+			wantCode: http.StatusUnauthorized,
+		},
+		"resource named like public info endpoint without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/base/api/v1/namespaces/version",
+			wantCode: http.StatusUnauthorized,
+		},
+		"public info endpoint prefix without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/healthzx",
+			wantCode: http.StatusUnauthorized,
+		},
+		"openapi without token": {
+			authMode: mkapi.AuthModeToken,
+			path:     "/base/openapi/v2",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &security{authMode: tc.authMode, token: "secret"}
+			isView := func(name string) bool { return name == "base" }
+			handler := s.authenticate(isView, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			path := tc.path
+			if path == "" {
+				path = "/base/api/v1/pods"
+			}
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			r.TLS = tc.tlsState
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tc.wantCode {
+				t.Errorf("expected status code %d, got %d", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestNewSecurityRejectsClientCertWithoutTLS(t *testing.T) {
+	_, err := newSecurity("127.0.0.1", mkapi.SecurityConfig{AuthMode: mkapi.AuthModeClientCert, CertDir: t.TempDir()})
+	if err == nil {
+		t.Fatal("expected error for client certificate authentication without TLS")
+	}
+}
+
+func TestKubeConfigCredentials(t *testing.T) {
+	tests := map[string]struct {
+		authMode mkapi.AuthMode
+	}{
+		"token": {
+			authMode: mkapi.AuthModeToken,
+		},
+		"client certificate": {
+			authMode: mkapi.AuthModeClientCert,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			certDir := t.TempDir()
+			cfg := mkapi.SecurityConfig{
+				TLSEnabled: true,
+				CertDir:    certDir,
+				AuthMode:   tc.authMode,
+				TokenFile:  filepath.Join(certDir, tokenFileName),
+			}
+			s, err := newSecurity("127.0.0.1", cfg)
+			if err != nil {
+				t.Fatalf("failed to setup security: %v", err)
+			}
+			ts := httptest.NewUnstartedServer(s.authenticate(func(string) bool { return false }, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeResponse(w, r, &metav1.Status{Status: metav1.StatusSuccess})
+			})))
+			ts.TLS = s.tlsConfig
+			ts.StartTLS()
+			t.Cleanup(ts.Close)
+
+			kubeConfigPath := filepath.Join(certDir, "minkapi.yaml")
+			if err = configtmpl.GenKubeConfig(s.kubeConfigParams(mkapi.DefaultBasePrefix, kubeConfigPath, ts.URL)); err != nil {
+				t.Fatalf("failed to generate kubeconfig: %v", err)
+			}
+			restConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+			if err != nil {
+				t.Fatalf("failed to load kubeconfig: %v", err)
+			}
+			assertStatusCode(t, restConfig, http.StatusOK)
+			restConfig.BearerToken = ""
+			restConfig.CertData, restConfig.KeyData = nil, nil
+			assertStatusCode(t, restConfig, http.StatusUnauthorized)
+
+			// the CA is reused on restart so that kubeconfigs of clients remain valid.
+			restarted, err := newSecurity("127.0.0.1", cfg)
+			if err != nil {
+				t.Fatalf("failed to setup security on restart: %v", err)
+			}
+			if !bytes.Equal(restarted.caCertPEM, s.caCertPEM) || restarted.token != s.token {
+				t.Errorf("expected CA and token to be reused on restart")
+			}
+		})
+	}
+}
+
+func TestNewSecurityRejectsFilesAccessibleByOthers(t *testing.T) {
+	tests := map[string]struct {
+		// loosen loosens the permissions of the given cert dir after the security material was created in it.
+		loosen func(certDir string) error
+	}{
+		"group readable CA key": {
+			loosen: func(certDir string) error { return os.Chmod(filepath.Join(certDir, caKeyFileName), 0640) },
+		},
+		"world writable CA certificate": {
+			loosen: func(certDir string) error { return os.Chmod(filepath.Join(certDir, caCertFileName), 0666) },
+		},
+		"world readable token": {
+			loosen: func(certDir string) error { return os.Chmod(filepath.Join(certDir, tokenFileName), 0644) },
+		},
+		"world writable cert dir": {
+			loosen: func(certDir string) error { return os.Chmod(certDir, 0777) },
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			certDir := t.TempDir()
+			cfg := mkapi.SecurityConfig{
+				TLSEnabled: true,
+				CertDir:    certDir,
+				AuthMode:   mkapi.AuthModeToken,
+				TokenFile:  filepath.Join(certDir, tokenFileName),
+			}
+			if _, err := newSecurity("127.0.0.1", cfg); err != nil {
+				t.Fatalf("failed to setup security: %v", err)
+			}
+			if err := tc.loosen(certDir); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := newSecurity("127.0.0.1", cfg); err == nil {
+				t.Errorf("expected security material accessible by other users to be refused")
+			}
+		})
+	}
+}
+
+func TestDefaultCertDirIsPrivate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := mkapi.Config{}
+	setMinKAPIConfigDefaults(&cfg)
+	if filepath.Dir(cfg.SecurityConfig.CertDir) == filepath.Dir(cfg.KubeConfigPath) {
+		t.Errorf("expected default cert dir to differ from the directory %q of the default kubeconfig", filepath.Dir(cfg.KubeConfigPath))
+	}
+	cfg.SecurityConfig.TLSEnabled = true
+	if _, err := newSecurity("127.0.0.1", cfg.SecurityConfig); err != nil {
+		t.Fatalf("failed to setup security: %v", err)
+	}
+	info, err := os.Stat(cfg.SecurityConfig.CertDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("expected default cert dir to have permissions 0700, got %v", perm)
+	}
+}
+
+func assertStatusCode(t *testing.T, restConfig *rest.Config, wantCode int) {
+	t.Helper()
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		t.Fatalf("failed to create HTTP client: %v", err)
+	}
+	resp, err := httpClient.Get(restConfig.Host + "/api")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != wantCode {
+		t.Errorf("expected status code %d, got %d", wantCode, resp.StatusCode)
+	}
+}
@@ -5,9 +5,10 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	commoncli "github.com/gardener/scaling-advisor/common/cli"
@@ -16,6 +17,7 @@ import (
 	"io"
 	runtimejson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/types"
+	"maps"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -23,9 +25,14 @@ import (
 	"os"
 	"path/filepath"
 	rt "runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gardener/scaling-advisor/common/objutil"
 	"github.com/gardener/scaling-advisor/common/webutil"
 	"github.com/gardener/scaling-advisor/minkapi/server/view"
 	kjson "k8s.io/apimachinery/pkg/util/json"
@@ -33,6 +40,8 @@ import (
 
 	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
 	"github.com/gardener/scaling-advisor/minkapi/server/configtmpl"
+	"github.com/gardener/scaling-advisor/minkapi/server/mirror"
+	"github.com/gardener/scaling-advisor/minkapi/server/printers"
 	"github.com/gardener/scaling-advisor/minkapi/server/store"
 	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
 
@@ -42,8 +51,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
@@ -56,9 +67,18 @@ type InMemoryKAPI struct {
 	scheme              *runtime.Scheme
 	rootMux             *http.ServeMux
 	server              *http.Server
+	security            *security
 	baseView            mkapi.View
 	createSandboxViewFn mkapi.CreateSandboxViewFunc
-	sandboxViews        map[string]mkapi.View
+	metrics             *serverMetrics
+	// mirror keeps the base view in sync with the source cluster in mirror mode, and is nil otherwise.
+	mirror *mirror.Mirror
+	// shuttingDown is set once Stop is called, which fails the readiness of all views.
+	shuttingDown atomic.Bool
+	// sandboxMu guards sandboxViews and sandboxCreationTimes.
+	sandboxMu            sync.RWMutex
+	sandboxViews         map[string]mkapi.View
+	sandboxCreationTimes map[string]time.Time
 }
 
 // LaunchApp is a helper function used to parse cli args, construct, and start the MinKAPI server.
@@ -119,11 +139,19 @@ func ShutdownApp(app *mkapi.App) (exitCode int) {
 // NewDefaultInMemory constructs a KAPI server with default implementations of sub-components.
 func NewDefaultInMemory(log logr.Logger, cfg mkapi.Config) (mkapi.Server, error) {
 	scheme := typeinfo.SupportedScheme
+	auditSink, err := openAuditLog(cfg.AuditLogPath)
+	if err != nil {
+		return nil, err
+	}
 	baseView, err := view.New(log, &mkapi.ViewArgs{
-		Name:           mkapi.DefaultBasePrefix,
-		KubeConfigPath: cfg.KubeConfigPath,
-		Scheme:         scheme,
-		WatchConfig:    cfg.WatchConfig,
+		Name:             mkapi.DefaultBasePrefix,
+		KubeConfigPath:   cfg.KubeConfigPath,
+		Scheme:           scheme,
+		WatchConfig:      cfg.WatchConfig,
+		EventConfig:      cfg.EventConfig,
+		AuditSink:        auditSink,
+		IndexedLabelKeys: cfg.IndexedLabelKeys,
+		Clock:            cfg.Clock,
 	})
 	// TODO: wrap errors with sentinel error code here.
 	if err != nil {
@@ -137,6 +165,19 @@ func NewDefaultInMemory(log logr.Logger, cfg mkapi.Config) (mkapi.Server, error)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.LoadSnapshotPath != "" {
+		if err = loadViewFromFile(baseView, cfg.LoadSnapshotPath); err != nil {
+			return nil, err
+		}
+		log.Info("loaded base view snapshot", "path", cfg.LoadSnapshotPath)
+	}
+	if cfg.ReplayAuditLogPath != "" {
+		count, err := replayAuditLogFromFile(baseView, cfg.ReplayAuditLogPath, cfg.ReplayAuditLogViews)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("replayed audit log into base view", "path", cfg.ReplayAuditLogPath, "views", cfg.ReplayAuditLogViews, "numEntries", count)
+	}
 	return NewInMemoryUsingViews(cfg, baseView, view.NewSandbox)
 }
 
@@ -148,22 +189,38 @@ func NewInMemoryUsingViews(cfg mkapi.Config, baseView mkapi.View, sandboxViewCre
 		}
 	}()
 	setMinKAPIConfigDefaults(&cfg)
+	sec, err := newSecurity(cfg.Host, cfg.SecurityConfig)
+	if err != nil {
+		return
+	}
 	scheme := typeinfo.SupportedScheme
 	rootMux := http.NewServeMux()
 	s := &InMemoryKAPI{
-		cfg:     cfg,
-		scheme:  scheme,
-		rootMux: rootMux,
+		cfg:      cfg,
+		scheme:   scheme,
+		rootMux:  rootMux,
+		security: sec,
 		server: &http.Server{
 			Addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
 		},
-		baseView:            baseView,
-		createSandboxViewFn: sandboxViewCreateFn,
-		sandboxViews:        make(map[string]mkapi.View),
+		baseView:             baseView,
+		createSandboxViewFn:  sandboxViewCreateFn,
+		sandboxViews:         make(map[string]mkapi.View),
+		sandboxCreationTimes: make(map[string]time.Time),
+	}
+	s.metrics = newServerMetrics(s)
+	if cfg.MirrorConfig.Enabled() {
+		if s.mirror, err = mirror.New(cfg.MirrorConfig, baseView); err != nil {
+			return
+		}
 	}
 	// DO NOT REMOVE: Single route registration crap needed for kubectl compatability as it ignores server path prefixes
 	// and always makes a call to http://localhost:8084/api/v1/?timeout=32s
 	rootMux.HandleFunc("GET /api/v1/", s.handleAPIResources(typeinfo.SupportedCoreAPIResourceList))
+	rootMux.Handle("GET /metrics", s.metrics.handler())
+	// the version and health of the base view are also served without a path prefix, so that they can be probed.
+	rootMux.HandleFunc("GET /version", handleVersion)
+	s.registerHealthRoutes(rootMux, baseView)
 	k = s
 	return
 }
@@ -176,8 +233,8 @@ func (k *InMemoryKAPI) Start(ctx context.Context) error {
 	}
 	baseViewMux := http.NewServeMux()
 	k.registerRoutes(log, baseViewMux, k.baseView)
-	// Wrap the entire mux with the logger middleware
-	serverHandler := webutil.LoggerMiddleware(log, k.rootMux)
+	// Wrap the entire mux with the logger, metrics and authentication middlewares
+	serverHandler := webutil.LoggerMiddleware(log, k.metrics.instrument(k, k.security.authenticate(k.hasView, k.rootMux)))
 	k.server.Handler = serverHandler
 	// We do this because we want the bind address
 	listener, err := net.Listen("tcp", k.server.Addr)
@@ -185,12 +242,14 @@ func (k *InMemoryKAPI) Start(ctx context.Context) error {
 		return fmt.Errorf("%w: cannot listen on TCP Address %q: %w", mkapi.ErrStartFailed, k.server.Addr, err)
 	}
 	k.listenerAddr = listener.Addr()
-	kapiURL := fmt.Sprintf("http://%s:%d/%s", k.cfg.Host, k.cfg.Port, k.cfg.BasePrefix)
-	err = configtmpl.GenKubeConfig(configtmpl.KubeConfigParams{
-		Name:           mkapi.DefaultBasePrefix,
-		KubeConfigPath: k.cfg.KubeConfigPath,
-		URL:            kapiURL,
-	})
+	if k.security.tlsConfig != nil {
+		k.server.TLSConfig = k.security.tlsConfig
+		listener = tls.NewListener(listener, k.security.tlsConfig)
+		log.Info("TLS enabled", "certDir", k.cfg.SecurityConfig.CertDir)
+	}
+	log.Info("authentication configured", "authMode", k.cfg.SecurityConfig.AuthMode)
+	kapiURL := k.viewURL(k.cfg.BasePrefix)
+	err = configtmpl.GenKubeConfig(k.security.kubeConfigParams(mkapi.DefaultBasePrefix, k.cfg.KubeConfigPath, kapiURL))
 	if err != nil {
 		return fmt.Errorf("%w: %w", mkapi.ErrStartFailed, err)
 	}
@@ -207,6 +266,9 @@ func (k *InMemoryKAPI) Start(ctx context.Context) error {
 		return fmt.Errorf("%w: %w", mkapi.ErrStartFailed, err)
 	}
 	log.Info("sample kube-scheduler-config generated", "path", schedulerTmplParams.KubeSchedulerConfigPath)
+	if k.mirror != nil {
+		go k.runMirror(ctx)
+	}
 	log.Info(fmt.Sprintf("%s service listening", mkapi.ProgramName), "address", k.server.Addr, "kapiURL", kapiURL)
 	if err := k.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("%w: %w", mkapi.ErrServiceFailed, err)
@@ -214,24 +276,50 @@ func (k *InMemoryKAPI) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop shuts down the HTTP server and closes resources
+// Stop shuts down the HTTP server and closes resources, including the base view and all sandbox views.
 func (k *InMemoryKAPI) Stop(ctx context.Context) (err error) {
+	k.shuttingDown.Store(true)
 	err = k.server.Shutdown(ctx) // shutdown server first to avoid accepting new requests.
-	k.baseView.Close()
+	if k.cfg.SaveOnExitPath != "" {
+		err = errors.Join(err, saveViewToFile(k.baseView, k.cfg.SaveOnExitPath))
+	}
+	if k.mirror != nil {
+		k.mirror.Shutdown()
+	}
+	sandboxViews, _ := k.listSandboxViews()
+	for _, v := range sandboxViews {
+		err = errors.Join(err, v.Close())
+	}
+	err = errors.Join(err, k.baseView.Close())
+	if c, ok := k.baseView.GetAuditSink().(io.Closer); ok {
+		err = errors.Join(err, c.Close())
+	}
 	return
 }
 
+// runMirror syncs the base view with the source cluster and keeps it in sync until the given ctx is cancelled.
+func (k *InMemoryKAPI) runMirror(ctx context.Context) {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("mirroring source cluster into base view", "sourceKubeConfigPath", k.cfg.MirrorConfig.SourceKubeConfigPath,
+		"kinds", k.cfg.MirrorConfig.Kinds, "namespaces", k.cfg.MirrorConfig.Namespaces)
+	if err := k.mirror.Start(ctx); err != nil {
+		log.Error(err, "failed to sync base view with source cluster")
+	}
+}
+
 func (k *InMemoryKAPI) GetBaseView() mkapi.View {
 	return k.baseView
 }
 
 func (k *InMemoryKAPI) GetSandboxView(ctx context.Context, name string) (mkapi.View, error) {
 	log := logr.FromContextOrDiscard(ctx).WithValues("sandboxName", name)
-	sandboxView, ok := k.sandboxViews[name] // TODO: protected with mutex.
+	k.sandboxMu.Lock()
+	defer k.sandboxMu.Unlock()
+	sandboxView, ok := k.sandboxViews[name]
 	if ok {
 		return sandboxView, nil
 	}
-	kapiURL := fmt.Sprintf("http://%s:%d/%s", k.cfg.Host, k.cfg.Port, name)
+	kapiURL := k.viewURL(name)
 	_, err := url.Parse(kapiURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: invalid sandbox-kapi URI for view %q: %w", mkapi.ErrCreateSandbox, name, err)
@@ -239,21 +327,21 @@ func (k *InMemoryKAPI) GetSandboxView(ctx context.Context, name string) (mkapi.V
 	baseKubeConfigDir := filepath.Dir(k.cfg.KubeConfigPath)
 	kubeConfigPath := filepath.Join(baseKubeConfigDir, fmt.Sprintf("minkapi-%s.yaml", name))
 	log.Info("generating kubeconfig for sandbox", "name", name, "path", kubeConfigPath)
-	err = configtmpl.GenKubeConfig(configtmpl.KubeConfigParams{
-		Name:           name,
-		KubeConfigPath: kubeConfigPath,
-		URL:            kapiURL,
-	})
+	err = configtmpl.GenKubeConfig(k.security.kubeConfigParams(name, kubeConfigPath, kapiURL))
 	if err != nil {
 		return nil, fmt.Errorf("%w: cannot generate kubeconfig for view %q: %w", mkapi.ErrCreateSandbox, name, err)
 	}
 	log.Info("sandbox kubeconfig generated", "name", name, "path", k.cfg.KubeConfigPath)
 
 	sandboxView, err = k.createSandboxViewFn(log, k.baseView, &mkapi.ViewArgs{
-		Name:           name,
-		KubeConfigPath: kubeConfigPath,
-		Scheme:         k.scheme,
-		WatchConfig:    k.cfg.WatchConfig,
+		Name:             name,
+		KubeConfigPath:   kubeConfigPath,
+		Scheme:           k.scheme,
+		WatchConfig:      k.cfg.WatchConfig,
+		EventConfig:      k.cfg.EventConfig,
+		AuditSink:        k.baseView.GetAuditSink(),
+		IndexedLabelKeys: k.cfg.IndexedLabelKeys,
+		Clock:            k.baseView.GetClock(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%w: cannot create sandbox view for view %q: %w", mkapi.ErrCreateSandbox, name, err)
@@ -261,9 +349,47 @@ func (k *InMemoryKAPI) GetSandboxView(ctx context.Context, name string) (mkapi.V
 	sandboxViewMux := http.NewServeMux()
 	k.registerRoutes(log, sandboxViewMux, sandboxView)
 	k.sandboxViews[name] = sandboxView
+	k.sandboxCreationTimes[name] = k.baseView.GetClock().Now()
 	return sandboxView, nil
 }
 
+func (k *InMemoryKAPI) HasBaseViewSynced() bool {
+	return k.mirror == nil || k.mirror.HasSynced()
+}
+
+// hasView returns whether a view with the given name is served.
+func (k *InMemoryKAPI) hasView(name string) bool {
+	_, ok := k.lookupView(name)
+	return ok
+}
+
+// lookupView returns the served view with the given name and whether it exists.
+func (k *InMemoryKAPI) lookupView(name string) (mkapi.View, bool) {
+	if name == k.baseView.GetName() {
+		return k.baseView, true
+	}
+	k.sandboxMu.RLock()
+	defer k.sandboxMu.RUnlock()
+	v, ok := k.sandboxViews[name]
+	return v, ok
+}
+
+// listSandboxViews returns the sandbox views sorted by name along with their creation times.
+func (k *InMemoryKAPI) listSandboxViews() (views []mkapi.View, creationTimes []time.Time) {
+	k.sandboxMu.RLock()
+	defer k.sandboxMu.RUnlock()
+	for _, name := range slices.Sorted(maps.Keys(k.sandboxViews)) {
+		views = append(views, k.sandboxViews[name])
+		creationTimes = append(creationTimes, k.sandboxCreationTimes[name])
+	}
+	return
+}
+
+// viewURL returns the URL at which the view with the given name is served.
+func (k *InMemoryKAPI) viewURL(name string) string {
+	return fmt.Sprintf("%s://%s/%s", k.security.scheme(), net.JoinHostPort(k.cfg.Host, strconv.Itoa(k.cfg.Port)), name)
+}
+
 func (k *InMemoryKAPI) registerRoutes(log logr.Logger, viewMux *http.ServeMux, view mkapi.View) {
 	// TODO: Design: Discuss this since this is not necessary when running as operator since operator has its own profiling enablement.
 	if k.cfg.ProfilingEnabled {
@@ -281,7 +407,14 @@ func (k *InMemoryKAPI) registerRoutes(log logr.Logger, viewMux *http.ServeMux, v
 	}
 
 	viewMux.HandleFunc("GET /api", k.handleAPIVersions)
-	viewMux.HandleFunc("GET /apis", k.handleAPIGroups)
+	viewMux.HandleFunc("GET /apis", k.handleAPIGroups(view))
+	viewMux.HandleFunc("GET /snapshot", handleSaveView(view))
+	viewMux.HandleFunc("GET /version", handleVersion)
+	k.registerHealthRoutes(viewMux, view)
+	openAPI := newOpenAPIService(view)
+	viewMux.Handle("GET /openapi/v2", openAPI)
+	viewMux.Handle("GET /openapi/v3", openAPI)
+	viewMux.Handle("GET /openapi/v3/", openAPI)
 
 	// Core API Group and Other API Groups
 	k.registerAPIGroups(viewMux)
@@ -289,6 +422,8 @@ func (k *InMemoryKAPI) registerRoutes(log logr.Logger, viewMux *http.ServeMux, v
 	for _, d := range typeinfo.SupportedDescriptors {
 		k.registerResourceRoutes(viewMux, d, view)
 	}
+	// Custom resources are defined at runtime, so the routes of built-in resources take precedence over this catch-all.
+	viewMux.HandleFunc("/apis/", handleCustomResources(view))
 	// Register the view's mux under the pathPrefix, stripping the pathPrefix
 	k.rootMux.Handle("/"+view.GetName()+"/", http.StripPrefix("/"+view.GetName(), viewMux))
 
@@ -306,51 +441,71 @@ func (k *InMemoryKAPI) registerAPIGroups(viewMux *http.ServeMux) {
 }
 
 func (k *InMemoryKAPI) registerResourceRoutes(viewMux *http.ServeMux, d typeinfo.Descriptor, view mkapi.View) {
-	g := d.GVK.Group
-	r := d.GVR.Resource
-	if d.GVK.Group == "" {
-		viewMux.HandleFunc(fmt.Sprintf("POST /api/v1/namespaces/{namespace}/%s", r), handleCreate(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /api/v1/namespaces/{namespace}/%s", r), handleListOrWatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /api/v1/namespaces/{namespace}/%s/{name}", r), handleGet(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PATCH /api/v1/namespaces/{namespace}/%s/{name}", r), handlePatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PATCH /api/v1/namespaces/{namespace}/%s/{name}/status", r), handlePatchStatus(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("DELETE /api/v1/namespaces/{namespace}/%s/{name}", r), handleDelete(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PUT /api/v1/namespaces/{namespace}/%s/{name}", r), handlePut(d, view))        // Update
-		viewMux.HandleFunc(fmt.Sprintf("PUT /api/v1/namespaces/{namespace}/%s/{name}/status", r), handlePut(d, view)) // UpdateStatus
-
-		if d.Kind == typeinfo.PodsDescriptor.Kind {
-			viewMux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/binding", handleCreatePodBinding(view))
-		}
-
-		viewMux.HandleFunc(fmt.Sprintf("POST /api/v1/%s", r), handleCreate(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /api/v1/%s", r), handleListOrWatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /api/v1/%s/{name}", r), handleGet(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PATCH /api/v1/%s/{name}", r), handlePatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("DELETE /api/v1/%s/{name}", r), handleDelete(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PUT /api/v1/%s/{name}", r), handlePut(d, view))        // Update
-		viewMux.HandleFunc(fmt.Sprintf("PUT /api/v1/%s/{name}/status", r), handlePut(d, view)) // UpdateStatus
-	} else {
-		viewMux.HandleFunc(fmt.Sprintf("POST /apis/%s/v1/namespaces/{namespace}/%s", g, r), handleCreate(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /apis/%s/v1/namespaces/{namespace}/%s", g, r), handleListOrWatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /apis/%s/v1/namespaces/{namespace}/%s/{name}", g, r), handleGet(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PATCH /apis/%s/v1/namespaces/{namespace}/%s/{name}", g, r), handlePatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("DELETE /apis/%s/v1/namespaces/{namespace}/%s/{name}", g, r), handleDelete(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("PUT /apis/%s/v1/namespaces/{namespace}/%s/{name}", g, r), handlePut(d, view))
+	if d.GVK == typeinfo.EventsDescriptor.GVK || d.GVK == typeinfo.CoreEventsDescriptor.GVK {
+		registerEventRoutes(viewMux, d, view)
+		return
+	}
+	for _, collection := range collectionPaths(d) {
+		item := collection + "/{name}"
+		viewMux.HandleFunc("POST "+collection, handleCreate(d, view))
+		viewMux.HandleFunc("GET "+collection, handleListOrWatch(d, view))
+		viewMux.HandleFunc("DELETE "+collection, handleDeleteCollection(d, view))
+		viewMux.HandleFunc("GET "+item, handleGet(d, view))
+		viewMux.HandleFunc("PATCH "+item, handlePatch(d, view))
+		viewMux.HandleFunc("DELETE "+item, handleDelete(d, view))
+		viewMux.HandleFunc("PUT "+item, handlePut(d, view))
+		if d.StatusSubresource {
+			viewMux.HandleFunc("GET "+item+"/status", handleGet(d, view))
+			viewMux.HandleFunc("PATCH "+item+"/status", handlePatchStatus(d, view))
+			viewMux.HandleFunc("PUT "+item+"/status", handlePutStatus(d, view))
+		}
+		if d.ScaleSubresource {
+			viewMux.HandleFunc("GET "+item+"/scale", handleGetScale(d, view))
+			viewMux.HandleFunc("PATCH "+item+"/scale", handlePatchScale(d, view))
+			viewMux.HandleFunc("PUT "+item+"/scale", handleUpdateScale(d, view))
+		}
+	}
+	if d.GVK == typeinfo.PodsDescriptor.GVK {
+		viewMux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/binding", handleCreatePodBinding(view))
+		viewMux.HandleFunc("POST /api/v1/namespaces/{namespace}/pods/{name}/eviction", handleCreatePodEviction(view))
+	}
+}
 
-		viewMux.HandleFunc(fmt.Sprintf("POST /apis/%s/v1/%s", g, r), handleCreate(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /apis/%s/v1/%s", g, r), handleListOrWatch(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("GET /apis/%s/v1/%s/{name}", g, r), handleGet(d, view))
-		viewMux.HandleFunc(fmt.Sprintf("DELETE /apis/%s/v1/%s/{name}", g, r), handleDelete(d, view))
+// collectionPaths returns the route patterns of the namespaced and the cluster-wide collections of the resource of the
+// given descriptor.
+func collectionPaths(d typeinfo.Descriptor) []string {
+	gvPath := "/apis/" + d.GVK.GroupVersion().String()
+	if d.GVK.Group == "" {
+		gvPath = "/api/" + d.GVK.Version
 	}
+	return []string{gvPath + "/namespaces/{namespace}/" + d.GVR.Resource, gvPath + "/" + d.GVR.Resource}
 }
 
-// handleAPIGroups returns the list of supported API groups
-func (k *InMemoryKAPI) handleAPIGroups(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// handleAPIGroups returns the list of supported API groups including those of the custom resources defined in the given
+// view.
+func (k *InMemoryKAPI) handleAPIGroups(v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		customDescriptors, err := view.CustomResourceDescriptors(v)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		if len(customDescriptors) == 0 {
+			writeResponse(w, r, &typeinfo.SupportedAPIGroups)
+			return
+		}
+		apiGroups := typeinfo.BuildAPIGroupList(slices.Concat(typeinfo.SupportedDescriptors, customDescriptors))
+		writeResponse(w, r, &apiGroups)
 	}
-	writeJsonResponse(w, r, &typeinfo.SupportedAPIGroups)
+}
+
+// handleVersion returns the version of the Kubernetes API served by minkapi.
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, typeinfo.ServerVersion)
 }
 
 // handleAPIVersions returns the list of versions for the core API group
@@ -359,7 +514,7 @@ func (k *InMemoryKAPI) handleAPIVersions(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	writeJsonResponse(w, r, &typeinfo.SupportedAPIVersions)
+	writeResponse(w, r, &typeinfo.SupportedAPIVersions)
 }
 
 func (k *InMemoryKAPI) handleAPIResources(apiResourceList metav1.APIResourceList) http.HandlerFunc {
@@ -368,10 +523,115 @@ func (k *InMemoryKAPI) handleAPIResources(apiResourceList metav1.APIResourceList
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			return
 		}
-		writeJsonResponse(w, r, apiResourceList)
+		writeResponse(w, r, apiResourceList)
 	}
 }
 
+// handleCustomResources serves the custom resources defined by the CustomResourceDefinitions in the given view, along
+// with the discovery of their group versions. Since the routes of custom resources cannot be registered upfront, the
+// descriptor and path values are resolved from the request path of the form
+// /apis/{group}/{version}[/namespaces/{namespace}]/{resource}[/{name}[/status]].
+func handleCustomResources(v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(segments) < 3 {
+			handleStatusError(w, r, apierrors.NewGenericServerResponse(http.StatusNotFound, r.Method, schema.GroupResource{}, "", "", 0, false))
+			return
+		}
+		gv := schema.GroupVersion{Group: segments[1], Version: segments[2]}
+		descriptors, err := view.CustomResourceDescriptors(v)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		descriptors = slices.DeleteFunc(descriptors, func(d typeinfo.Descriptor) bool {
+			return d.GVK.GroupVersion() != gv
+		})
+		rest := segments[3:]
+		if len(rest) == 0 && len(descriptors) > 0 && r.Method == http.MethodGet {
+			writeResponse(w, r, buildCustomAPIResourceList(gv, descriptors))
+			return
+		}
+		namespace := ""
+		if len(rest) >= 3 && rest[0] == "namespaces" {
+			namespace, rest = rest[1], rest[2:]
+		}
+		var (
+			d     typeinfo.Descriptor
+			found bool
+		)
+		if len(rest) > 0 {
+			d, found = findDescriptorByResource(descriptors, rest[0])
+		}
+		if !found || len(rest) > 3 || (namespace != "" && !d.APIResource.Namespaced) || (len(rest) == 3 && (rest[2] != "status" || !d.StatusSubresource)) {
+			handleStatusError(w, r, apierrors.NewGenericServerResponse(http.StatusNotFound, r.Method, gv.WithResource(strings.Join(rest, "/")).GroupResource(), "", "", 0, false))
+			return
+		}
+		r.SetPathValue("namespace", namespace)
+		var handler http.HandlerFunc
+		switch len(rest) {
+		case 1:
+			switch r.Method {
+			case http.MethodGet:
+				handler = handleListOrWatch(d, v)
+			case http.MethodPost:
+				handler = handleCreate(d, v)
+			case http.MethodDelete:
+				handler = handleDeleteCollection(d, v)
+			}
+		case 2:
+			r.SetPathValue("name", rest[1])
+			switch r.Method {
+			case http.MethodGet:
+				handler = handleGet(d, v)
+			case http.MethodPatch:
+				handler = handlePatch(d, v)
+			case http.MethodPut:
+				handler = handlePut(d, v)
+			case http.MethodDelete:
+				handler = handleDelete(d, v)
+			}
+		case 3:
+			r.SetPathValue("name", rest[1])
+			switch r.Method {
+			case http.MethodGet:
+				handler = handleGet(d, v)
+			case http.MethodPatch:
+				handler = handlePatchStatus(d, v)
+			case http.MethodPut:
+				handler = handlePutStatus(d, v)
+			}
+		}
+		if handler == nil {
+			handleStatusError(w, r, apierrors.NewMethodNotSupported(d.GVR.GroupResource(), r.Method))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}
+}
+
+func buildCustomAPIResourceList(gv schema.GroupVersion, descriptors []typeinfo.Descriptor) *metav1.APIResourceList {
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: gv.String(),
+	}
+	for _, d := range descriptors {
+		list.APIResources = append(list.APIResources, d.APIResource)
+		list.APIResources = append(list.APIResources, d.SubresourceAPIResources()...)
+	}
+	return list
+}
+
+func findDescriptorByResource(descriptors []typeinfo.Descriptor, resource string) (typeinfo.Descriptor, bool) {
+	i := slices.IndexFunc(descriptors, func(d typeinfo.Descriptor) bool {
+		return d.GVR.Resource == resource
+	})
+	if i < 0 {
+		return typeinfo.Descriptor{}, false
+	}
+	return descriptors[i], true
+}
+
 func handleGet(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := GetObjectName(r, d)
@@ -380,7 +640,7 @@ func handleGet(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, obj)
+		writeObjectResponse(w, r, d, obj)
 	}
 }
 
@@ -406,12 +666,22 @@ func handleCreate(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 			namespace = GetObjectName(r, d).Namespace
 			mo.SetNamespace(namespace)
 		}
+		liveObj, err := d.CreateObject()
+		if err != nil {
+			handleInternalServerError(w, r, err)
+			return
+		}
+		if err = recordUpdate(r, d, "", liveObj.(runtime.Object), mo.(runtime.Object)); err != nil {
+			handleError(w, r, err)
+			return
+		}
 		err = view.CreateObject(d.GVK, mo)
 		if err != nil {
 			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, mo)
+		auditMutation(r, view, mkapi.AuditVerbCreate, d.GVK, nil, mo.(runtime.Object))
+		writeResponse(w, r, mo)
 	}
 }
 
@@ -425,16 +695,22 @@ func handlePut(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 			handleError(w, r, err)
 			return
 		}
+		prevObj := obj.DeepCopyObject()
 		if !readBodyIntoObj(w, r, obj) {
 			return
 		}
+		if err = recordUpdate(r, d, "", prevObj, obj); err != nil {
+			handleError(w, r, err)
+			return
+		}
 		metaObj := obj.(metav1.Object)
 		err = view.UpdateObject(d.GVK, metaObj)
 		if err != nil {
 			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, obj)
+		auditMutation(r, view, mkapi.AuditVerbUpdate, d.GVK, prevObj, obj)
+		writeResponse(w, r, obj)
 	}
 }
 func handleDelete(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
@@ -450,11 +726,26 @@ func handleDelete(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 			handleError(w, r, fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err))
 			return
 		}
-		err = view.DeleteObject(d.GVK, objName)
+		opts, err := parseDeleteOptions(r)
+		if err != nil {
+			handleBadRequest(w, r, err)
+			return
+		}
+		err = view.DeleteObject(d.GVK, objName, opts)
 		if err != nil {
 			handleError(w, r, err)
 			return
 		}
+		pendingObj, err := view.GetObject(d.GVK, objName)
+		if err != nil {
+			pendingObj = nil
+		}
+		auditDeletion(r, view, d.GVK, obj, pendingObj, opts)
+		if pendingObj != nil {
+			// like the kube-apiserver, an object that is only marked for deletion is returned instead of a status.
+			writeResponse(w, r, pendingObj)
+			return
+		}
 		status := metav1.Status{
 			TypeMeta: metav1.TypeMeta{ //No idea why this is explicitly needed just for this payload, but kubectl complains if missing.
 				Kind:       "Status",
@@ -467,7 +758,59 @@ func handleDelete(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 				UID:  mo.GetUID(),
 			},
 		}
-		writeJsonResponse(w, r, &status)
+		writeResponse(w, r, &status)
+	}
+}
+
+// handleDeleteCollection deletes the objects of a collection matching the label and field selectors of the request
+// like handleDelete, honouring the same delete options for each of them. Like the kube-apiserver, the deleted objects
+// are returned as a list, holding the objects that are only marked for deletion in their pending state.
+func handleDeleteCollection(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		labelSelector, err := parseLabelSelector(r)
+		if err != nil {
+			handleBadRequest(w, r, err)
+			return
+		}
+		fieldSelector, err := parseFieldSelector(r, d)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		opts, err := parseDeleteOptions(r)
+		if err != nil {
+			handleBadRequest(w, r, err)
+			return
+		}
+		c := mkapi.MatchCriteria{
+			Namespace:     r.PathValue("namespace"),
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+		}
+		delObjs, err := view.DeleteObjects(d.GVK, c, opts)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		items := make([]metav1.Object, 0, len(delObjs))
+		for _, mo := range delObjs {
+			obj := mo.(runtime.Object)
+			pendingObj, err := view.GetObject(d.GVK, objutil.CacheName(mo))
+			if err != nil {
+				pendingObj = nil
+			}
+			auditDeletion(r, view, d.GVK, obj, pendingObj, opts)
+			if pendingObj != nil {
+				mo = pendingObj.(metav1.Object)
+			}
+			items = append(items, mo)
+		}
+		listObj, err := store.WrapMetaObjectsIntoRuntimeListObject(metav1.ListMeta{}, d.GVK, d.ListGVK, items)
+		if err != nil {
+			handleInternalServerError(w, r, err)
+			return
+		}
+		writeResponse(w, r, listObj)
 	}
 }
 
@@ -482,25 +825,54 @@ func handleListOrWatch(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc
 			handleBadRequest(w, r, err)
 			return
 		}
+		fieldSelector, err := parseFieldSelector(r, d)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		c := mkapi.MatchCriteria{
+			Namespace:     r.PathValue("namespace"),
+			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
+		}
 
 		if isWatch == "true" || isWatch == "1" {
-			delegate = handleWatch(d, view, labelSelector)
+			delegate = handleWatch(d, view, c)
 		} else {
-			delegate = handleList(d, view, labelSelector)
+			c.Limit, err = parseLimit(r)
+			if err != nil {
+				handleBadRequest(w, r, err)
+				return
+			}
+			c.Continue = query.Get("continue")
+			delegate = handleList(d, view, c)
 		}
 		delegate.ServeHTTP(w, r)
 	}
 }
 
-func handleList(d typeinfo.Descriptor, view mkapi.View, labelSelector labels.Selector) http.HandlerFunc {
+func handleList(d typeinfo.Descriptor, view mkapi.View, c mkapi.MatchCriteria) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		namespace := r.PathValue("namespace")
-		c := mkapi.MatchCriteria{Namespace: namespace, LabelSelector: labelSelector}
-		listObj, err := view.ListObjects(d.GVK, c) //s.List(c)
+		query := r.URL.Query()
+		rvMatch := metav1.ResourceVersionMatch(query.Get("resourceVersionMatch"))
+		requestedVersion, err := parseListResourceVersion(query.Get("resourceVersion"), rvMatch, c)
+		if err != nil {
+			handleBadRequest(w, r, err)
+			return
+		}
+		listObj, err := view.ListObjects(d.GVK, c)
 		if err != nil {
+			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, listObj)
+		if rvMatch != "" {
+			// lists are always served from the latest state, so a list at an exact older version cannot be served.
+			if err = checkListResourceVersion(listObj, requestedVersion, rvMatch); err != nil {
+				handleError(w, r, err)
+				return
+			}
+		}
+		writeObjectResponse(w, r, d, listObj)
 	}
 }
 
@@ -508,7 +880,7 @@ func handlePatch(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := GetObjectName(r, d)
 		contentType := r.Header.Get("Content-Type")
-		if contentType != "application/strategic-merge-patch+json" && contentType != "application/merge-patch+json" {
+		if contentType != "application/strategic-merge-patch+json" && contentType != "application/merge-patch+json" && contentType != "application/json-patch+json" && contentType != string(types.ApplyYAMLPatchType) {
 			err := fmt.Errorf("unsupported content type %q for object %q", contentType, name)
 			handleBadRequest(w, r, err)
 			return
@@ -519,12 +891,29 @@ func handlePatch(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc {
 			writeStatusError(w, r, statusErr)
 			return
 		}
-		patchedObj, err := view.PatchObject(d.GVK, name, types.PatchType(contentType), patchData)
+		// prevObj is nil if a server-side apply creates the object.
+		var prevObj, patchedObj runtime.Object
+		if contentType == string(types.ApplyYAMLPatchType) {
+			var opts mkapi.ApplyOptions
+			opts, err = parseApplyOptions(r)
+			if err != nil {
+				handleBadRequest(w, r, err)
+				return
+			}
+			prevObj, patchedObj, err = view.ApplyObject(d.GVK, name, patchData, opts)
+		} else {
+			prevObj, patchedObj, err = view.PatchObject(d.GVK, name, types.PatchType(contentType), patchData, mkapi.PatchOptions{FieldManager: fieldManagerOf(r)})
+		}
 		if err != nil {
 			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, patchedObj)
+		if prevObj == nil {
+			auditMutation(r, view, mkapi.AuditVerbCreate, d.GVK, nil, patchedObj)
+		} else {
+			auditMutation(r, view, mkapi.AuditVerbPatch, d.GVK, prevObj, patchedObj)
+		}
+		writeResponse(w, r, patchedObj)
 	}
 }
 
@@ -532,7 +921,7 @@ func handlePatchStatus(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		objName := GetObjectName(r, d)
 		contentType := r.Header.Get("Content-Type")
-		if contentType != "application/strategic-merge-patch+json" {
+		if contentType != "application/strategic-merge-patch+json" && contentType != "application/merge-patch+json" && contentType != "application/json-patch+json" {
 			err := fmt.Errorf("unsupported content type %q for o %q", contentType, objName)
 			handleBadRequest(w, r, err)
 			return
@@ -545,56 +934,76 @@ func handlePatchStatus(d typeinfo.Descriptor, view mkapi.View) http.HandlerFunc
 			return
 		}
 
-		patchedObj, err := view.PatchObjectStatus(d.GVK, objName, patchData)
+		prevObj, patchedObj, err := view.PatchObjectStatus(d.GVK, objName, types.PatchType(contentType), patchData, mkapi.PatchOptions{FieldManager: fieldManagerOf(r)})
 		if err != nil {
 			handleError(w, r, err)
 			return
 		}
-		writeJsonResponse(w, r, patchedObj)
+		auditMutation(r, view, mkapi.AuditVerbPatch, d.GVK, prevObj, patchedObj)
+		writeResponse(w, r, patchedObj)
 	}
 }
 
 // handleWatch implements watch request/response handling. It delegates watch functionality to the given mkapi.View, only
 // passing a callback which encodes the watch event and flushed it to the response stream.
-func handleWatch(d typeinfo.Descriptor, view mkapi.View, labelSelector labels.Selector) http.HandlerFunc {
+func handleWatch(d typeinfo.Descriptor, view mkapi.View, c mkapi.MatchCriteria) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var (
-			ok           bool
-			startVersion int64
-			namespace    string
-		)
-
-		namespace = r.PathValue("namespace")
-		startVersion, ok = getParseResourceVersion(w, r)
-		if !ok {
+		opts, err := parseWatchOptions(r, c)
+		if err != nil {
+			handleBadRequest(w, r, err)
 			return
 		}
+		if !opts.SendInitialEvents && opts.StartVersion <= 0 && r.URL.Query().Get("sendInitialEvents") != "" {
+			// sendInitialEvents=false explicitly requests a watch from the most recent version without initial events.
+			s, err := view.GetResourceStore(d.GVK)
+			if err != nil {
+				handleError(w, r, err)
+				return
+			}
+			opts.StartVersion = s.GetVersionCounter().Load()
+		}
 
+		log := logr.FromContextOrDiscard(r.Context())
+		contentType, encodeEvent, err := newWatchEventEncoder(log, r, d)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
 		flusher := getFlusher(w)
 		if flusher == nil {
 			return
 		}
 		flusher.Flush() // 🚨important! unblocks client-go I/O so that it can construct a watcher!
 
-		log := logr.FromContextOrDiscard(r.Context())
-		err := view.WatchObjects(r.Context(), d.GVK, startVersion, namespace, labelSelector, func(event watch.Event) error {
+		err = view.WatchObjects(r.Context(), d.GVK, opts, func(event watch.Event) error {
 			metaObj, err := store.AsMeta(event.Object)
 			if err != nil {
 				return err
 			}
-			eventJson, err := buildWatchEventJsonAlt(log, &event)
-			if err != nil {
+			if err = encodeEvent(w, &event); err != nil {
 				err = fmt.Errorf("cannot  encode watch %q event for object name %q, namespace %q, resourceVersion %q: %w",
 					event.Type, metaObj.GetName(), metaObj.GetNamespace(), metaObj.GetResourceVersion(), err)
 				return err
 			}
-			_, _ = fmt.Fprintln(w, eventJson)
 			flusher.Flush()
 			return nil
 		})
 
 		if err != nil {
-			log.Error(err, "watch failed", "gvk", d.GVK, "namespace", namespace, "startVersion", startVersion, "labelSelector", labelSelector)
+			log.Error(err, "watch failed", "gvk", d.GVK, "namespace", c.Namespace, "startVersion", opts.StartVersion, "labelSelector", c.LabelSelector, "fieldSelector", c.FieldSelector)
+			// Like the kube-apiserver, errors such as 410 Expired are sent as a watch error event since the response status has
+			// already been written. Reflectors relist on receiving such an event.
+			var statusErr *apierrors.StatusError
+			if !errors.As(err, &statusErr) {
+				statusErr = apierrors.NewInternalError(err)
+			}
+			status := statusErr.Status()
+			status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+			if err = encodeEvent(w, &watch.Event{Type: watch.Error, Object: &status}); err != nil {
+				return
+			}
+			flusher.Flush()
 		}
 	}
 }
@@ -625,26 +1034,132 @@ func handleCreatePodBinding(view mkapi.View) http.HandlerFunc {
 		//	Type:   corev1.PodScheduled,
 		//	Status: corev1.ConditionTrue,
 		//})
-		pod, err := view.UpdatePodNodeBinding(podName, binding)
+		prevPod, pod, err := view.UpdatePodNodeBinding(podName, binding)
 		if err != nil {
 			log.Error(err, "cannot assign pod to node", "podName", podName, "nodeName", binding.Target.Name)
 			handleError(w, r, err)
 			return
 		}
+		auditMutation(r, view, mkapi.AuditVerbBind, d.GVK, prevPod, pod)
 		log.V(3).Info("assigned pod to node", "podName", podName, "nodeName", pod.Spec.NodeName)
 		// Return {"kind":"Status","apiVersion":"v1","metadata":{},"status":"Success","code":201}
 		statusOK := &metav1.Status{
-			TypeMeta: metav1.TypeMeta{Kind: "Status"},
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
 			Status:   metav1.StatusSuccess,
 			Code:     http.StatusCreated,
 		}
-		writeJsonResponse(w, r, statusOK)
+		writeResponse(w, r, statusOK)
+	}
+}
+
+// handleSaveView serves a minkapi.ViewArchive of the given view as a JSON attachment.
+// Ex: curl -o base-snapshot.json http://localhost:8084/base/snapshot
+func handleSaveView(view mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := view.Save(&buf); err != nil {
+			handleInternalServerError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", view.GetName()+"-snapshot.json"))
+		_, _ = w.Write(buf.Bytes())
 	}
 }
 
+// auditMutation records the mutation of an object of the given gvk made by the given request to the audit sink of the
+// given view, if any. The user agent of the request identifies the actor. See view.NewAuditEntry for prevObj and obj.
+func auditMutation(r *http.Request, v mkapi.View, verb mkapi.AuditVerb, gvk schema.GroupVersionKind, prevObj, obj runtime.Object) {
+	recordAuditEntry(r, v, func() (mkapi.AuditEntry, error) {
+		return view.NewAuditEntry(v, r.UserAgent(), verb, gvk, prevObj, obj)
+	})
+}
+
+// auditDeletion records the deletion of the given object like auditMutation, along with the propagation policy and grace
+// period of the given opts. The pendingObj is the object marked for deletion, or nil if it was removed.
+func auditDeletion(r *http.Request, v mkapi.View, gvk schema.GroupVersionKind, obj, pendingObj runtime.Object, opts mkapi.DeleteOptions) {
+	recordAuditEntry(r, v, func() (entry mkapi.AuditEntry, err error) {
+		entry, err = view.NewAuditEntry(v, r.UserAgent(), mkapi.AuditVerbDelete, gvk, obj, pendingObj)
+		entry.PropagationPolicy = opts.PropagationPolicy
+		entry.GracePeriodSeconds = opts.GracePeriodSeconds
+		return
+	})
+}
+
+// recordAuditEntry records the entry returned by the given newEntry func to the audit sink of the given view, if any.
+// Since the mutation has already been made, failures are only logged.
+func recordAuditEntry(r *http.Request, v mkapi.View, newEntry func() (mkapi.AuditEntry, error)) {
+	sink := v.GetAuditSink()
+	if sink == nil {
+		return
+	}
+	entry, err := newEntry()
+	if err == nil {
+		err = sink.Record(entry)
+	}
+	if err != nil {
+		log := logr.FromContextOrDiscard(r.Context())
+		log.Error(err, "cannot record audit entry", "view", v.GetName())
+	}
+}
+
+// loadViewFromFile loads the minkapi.ViewArchive at the given path into the given view.
+func loadViewFromFile(view mkapi.View, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", mkapi.ErrLoadView, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return view.Load(bufio.NewReader(f))
+}
+
+// openAuditLog returns an audit log appending to the file at the given path, or nil if the path is empty. Like the keys
+// and the token, the file is created private to the user since it holds full objects, including Secrets.
+func openAuditLog(path string) (mkapi.AuditSink, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+	return view.NewAuditLog(f), nil
+}
+
+// replayAuditLogFromFile replays the entries of the given views in the audit log at the given path into the given view.
+func replayAuditLogFromFile(v mkapi.View, path string, viewNames []string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", mkapi.ErrReplayAuditLog, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return view.ReplayAuditLog(v, bufio.NewReader(f), viewNames...)
+}
+
+// saveViewToFile saves the given view as a minkapi.ViewArchive to the given path.
+func saveViewToFile(view mkapi.View, path string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", mkapi.ErrSaveView, err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+	w := bufio.NewWriter(f)
+	if err = view.Save(w); err != nil {
+		return
+	}
+	return w.Flush()
+}
+
 func writeStatusError(w http.ResponseWriter, r *http.Request, statusError *apierrors.StatusError) {
-	w.WriteHeader(int(statusError.ErrStatus.Code))
-	writeJsonResponse(w, r, statusError.ErrStatus)
+	status := statusError.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeResponseWithStatusCode(w, r, int(status.Code), &status)
 }
 
 func readBodyIntoObj(w http.ResponseWriter, r *http.Request, obj any) (ok bool) {
@@ -655,9 +1170,9 @@ func readBodyIntoObj(w http.ResponseWriter, r *http.Request, obj any) (ok bool)
 		ok = false
 		return
 	}
-	if err := json.Unmarshal(data, obj); err != nil {
-		err = fmt.Errorf("cannot unmarshal JSON for request %q: %w", r.RequestURI, err)
-		log.Error(err, "cannot unmarshal JSON for request body", "payload", string(data))
+	if err := decodeBody(r.Header.Get("Content-Type"), data, obj); err != nil {
+		err = fmt.Errorf("cannot decode body of request %q: %w", r.RequestURI, err)
+		log.Error(err, "cannot decode request body", "payload", string(data))
 		handleBadRequest(w, r, err)
 		ok = false
 		return
@@ -669,22 +1184,177 @@ func readBodyIntoObj(w http.ResponseWriter, r *http.Request, obj any) (ok bool)
 	return
 }
 
-func getParseResourceVersion(w http.ResponseWriter, r *http.Request) (resourceVersion int64, ok bool) {
-	paramValue := r.URL.Query().Get("resourceVersion")
-	if paramValue == "" {
-		ok = true
-		resourceVersion = 0
+// fieldManagerOf returns the field manager of the given request. Like in the kube-apiserver, it defaults to the prefix
+// of the user agent of the request up to the first '/', truncated to 128 characters.
+func fieldManagerOf(r *http.Request) string {
+	if manager := r.URL.Query().Get("fieldManager"); manager != "" {
+		return manager
+	}
+	manager, _, _ := strings.Cut(r.UserAgent(), "/")
+	if len(manager) > 128 {
+		manager = manager[:128]
+	}
+	return manager
+}
+
+// recordUpdate records an Update entry of the field manager of the given request in the managedFields of the given
+// newObj, which replaces the given liveObj through the given subresource. See view.RecordUpdate.
+func recordUpdate(r *http.Request, d typeinfo.Descriptor, subresource string, liveObj, newObj runtime.Object) error {
+	manager := fieldManagerOf(r)
+	if manager == "" {
+		return nil
+	}
+	return view.RecordUpdate(d.GVK, subresource, liveObj, newObj, manager)
+}
+
+// parseApplyOptions parses the fieldManager and force query params of the given server-side apply request.
+func parseApplyOptions(r *http.Request) (opts mkapi.ApplyOptions, err error) {
+	opts.FieldManager = r.URL.Query().Get("fieldManager")
+	if opts.FieldManager == "" {
+		err = fmt.Errorf("fieldManager is required for apply requests")
 		return
 	}
-	resourceVersion, err := parseResourceVersion(paramValue)
+	if forceStr := r.URL.Query().Get("force"); forceStr != "" {
+		opts.Force, err = strconv.ParseBool(forceStr)
+		if err != nil {
+			err = fmt.Errorf("invalid force parameter %q: %w", forceStr, err)
+		}
+	}
+	return
+}
+
+// parseWatchOptions parses the watch related query params of the given request following the validation rules of the
+// kube-apiserver for the WatchList feature.
+func parseWatchOptions(r *http.Request, c mkapi.MatchCriteria) (opts mkapi.WatchOptions, err error) {
+	query := r.URL.Query()
+	opts.MatchCriteria = c
+	rv := query.Get("resourceVersion")
+	opts.StartVersion, err = parseResourceVersion(rv)
 	if err != nil {
-		handleBadRequest(w, r, fmt.Errorf("invalid resource version %q: %w", paramValue, err))
+		err = fmt.Errorf("invalid resource version %q: %w", rv, err)
 		return
 	}
-	ok = true
+	if allowBookmarks := query.Get("allowWatchBookmarks"); allowBookmarks != "" {
+		opts.AllowBookmarks, err = strconv.ParseBool(allowBookmarks)
+		if err != nil {
+			err = fmt.Errorf("invalid allowWatchBookmarks %q: %w", allowBookmarks, err)
+			return
+		}
+	}
+	rvMatch := metav1.ResourceVersionMatch(query.Get("resourceVersionMatch"))
+	sendInitialEvents := query.Get("sendInitialEvents")
+	if sendInitialEvents == "" {
+		if rvMatch != "" {
+			err = fmt.Errorf("resourceVersionMatch is forbidden for watch unless sendInitialEvents is provided")
+		}
+		return
+	}
+	opts.SendInitialEvents, err = strconv.ParseBool(sendInitialEvents)
+	if err != nil {
+		err = fmt.Errorf("invalid sendInitialEvents %q: %w", sendInitialEvents, err)
+		return
+	}
+	if rvMatch != metav1.ResourceVersionMatchNotOlderThan {
+		err = fmt.Errorf("sendInitialEvents requires setting resourceVersionMatch to %s", metav1.ResourceVersionMatchNotOlderThan)
+		return
+	}
+	if !opts.AllowBookmarks {
+		err = fmt.Errorf("sendInitialEvents requires setting allowWatchBookmarks to true")
+	}
+	return
+}
+
+// parseDeleteOptions parses the mkapi.DeleteOptions of a delete request from the metav1.DeleteOptions in its optional
+// body and its gracePeriodSeconds and propagationPolicy query parameters, which take precedence like in the
+// kube-apiserver.
+func parseDeleteOptions(r *http.Request) (opts mkapi.DeleteOptions, err error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	var deleteOpts metav1.DeleteOptions
+	if len(data) > 0 {
+		if err = decodeBody(r.Header.Get("Content-Type"), data, &deleteOpts); err != nil {
+			err = fmt.Errorf("invalid delete options: %w", err)
+			return
+		}
+	}
+	if gracePeriod := r.URL.Query().Get("gracePeriodSeconds"); gracePeriod != "" {
+		var gracePeriodSeconds int64
+		if gracePeriodSeconds, err = strconv.ParseInt(gracePeriod, 10, 64); err != nil {
+			err = fmt.Errorf("invalid gracePeriodSeconds %q: %w", gracePeriod, err)
+			return
+		}
+		deleteOpts.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	if policy := r.URL.Query().Get("propagationPolicy"); policy != "" {
+		propagationPolicy := metav1.DeletionPropagation(policy)
+		deleteOpts.PropagationPolicy = &propagationPolicy
+	}
+	opts = toDeleteOptions(deleteOpts)
 	return
 }
 
+// toDeleteOptions converts the given metav1.DeleteOptions of a request into the mkapi.DeleteOptions of a view.
+func toDeleteOptions(deleteOpts metav1.DeleteOptions) (opts mkapi.DeleteOptions) {
+	if deleteOpts.PropagationPolicy == nil && deleteOpts.OrphanDependents != nil && *deleteOpts.OrphanDependents {
+		orphan := metav1.DeletePropagationOrphan
+		deleteOpts.PropagationPolicy = &orphan
+	}
+	if deleteOpts.Preconditions != nil {
+		opts.Preconditions = *deleteOpts.Preconditions
+	}
+	opts.GracePeriodSeconds = deleteOpts.GracePeriodSeconds
+	opts.PropagationPolicy = deleteOpts.PropagationPolicy
+	return
+}
+
+// parseListResourceVersion parses and validates the resourceVersion of a list request against the given
+// resourceVersionMatch.
+func parseListResourceVersion(rv string, rvMatch metav1.ResourceVersionMatch, c mkapi.MatchCriteria) (resourceVersion int64, err error) {
+	resourceVersion, err = parseResourceVersion(rv)
+	if err != nil {
+		err = fmt.Errorf("invalid resource version %q: %w", rv, err)
+		return
+	}
+	switch rvMatch {
+	case "":
+		return
+	case metav1.ResourceVersionMatchNotOlderThan, metav1.ResourceVersionMatchExact:
+	default:
+		err = fmt.Errorf("unsupported resourceVersionMatch %q", rvMatch)
+		return
+	}
+	if rv == "" {
+		err = fmt.Errorf("resourceVersionMatch is forbidden unless resourceVersion is provided")
+	} else if c.Continue != "" {
+		err = fmt.Errorf("resourceVersionMatch is forbidden when continue is provided")
+	} else if rvMatch == metav1.ResourceVersionMatchExact && resourceVersion == 0 {
+		err = fmt.Errorf("resourceVersionMatch %q is forbidden for resourceVersion \"0\"", rvMatch)
+	}
+	return
+}
+
+// checkListResourceVersion checks whether the given list object served at the latest version satisfies the requested
+// resource version and resourceVersionMatch.
+func checkListResourceVersion(listObj runtime.Object, requestedVersion int64, rvMatch metav1.ResourceVersionMatch) error {
+	listMeta, err := meta.ListAccessor(listObj)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	listVersion, err := parseResourceVersion(listMeta.GetResourceVersion())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if requestedVersion > listVersion {
+		return apierrors.NewTimeoutError(fmt.Sprintf("Too large resource version: %d, current: %d", requestedVersion, listVersion), 1)
+	}
+	if rvMatch == metav1.ResourceVersionMatchExact && requestedVersion < listVersion {
+		return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", requestedVersion, listVersion))
+	}
+	return nil
+}
+
 func parseResourceVersion(rvStr string) (resourceVersion int64, err error) {
 	if rvStr != "" {
 		resourceVersion, err = strconv.ParseInt(rvStr, 10, 64)
@@ -760,6 +1430,32 @@ func parseLabelSelector(req *http.Request) (labels.Selector, error) {
 	return labels.Parse(raw)
 }
 
+func parseLimit(req *http.Request) (limit int64, err error) {
+	raw := req.URL.Query().Get("limit")
+	if raw == "" {
+		return
+	}
+	limit, err = strconv.ParseInt(raw, 10, 64)
+	if err != nil || limit < 0 {
+		err = fmt.Errorf("invalid limit %q: must be a non-negative integer", raw)
+	}
+	return
+}
+
+// parseFieldSelector parses the fieldSelector query param of the given request and converts it into a selector that
+// only references fields supported by the kind described by the given descriptor.
+func parseFieldSelector(req *http.Request, d typeinfo.Descriptor) (fields.Selector, error) {
+	raw := req.URL.Query().Get("fieldSelector")
+	if raw == "" {
+		return fields.Everything(), nil
+	}
+	selector, err := fields.ParseSelector(raw)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("invalid field selector %q: %v", raw, err))
+	}
+	return d.ConvertFieldSelector(selector)
+}
+
 func setMinKAPIConfigDefaults(cfg *mkapi.Config) {
 	if cfg.WatchConfig.QueueSize <= 0 {
 		cfg.WatchConfig.QueueSize = mkapi.DefaultWatchQueueSize
@@ -767,12 +1463,33 @@ func setMinKAPIConfigDefaults(cfg *mkapi.Config) {
 	if cfg.WatchConfig.Timeout <= 0 {
 		cfg.WatchConfig.Timeout = mkapi.DefaultWatchTimeout
 	}
+	if cfg.WatchConfig.HistorySize <= 0 {
+		cfg.WatchConfig.HistorySize = mkapi.DefaultWatchHistorySize
+	}
+	if cfg.WatchConfig.BookmarkInterval <= 0 {
+		cfg.WatchConfig.BookmarkInterval = mkapi.DefaultWatchBookmarkInterval
+	}
+	if cfg.EventConfig.MaxCount <= 0 {
+		cfg.EventConfig.MaxCount = mkapi.DefaultEventMaxCount
+	}
+	if cfg.EventConfig.TTL <= 0 {
+		cfg.EventConfig.TTL = mkapi.DefaultEventTTL
+	}
 	if cfg.KubeConfigPath == "" {
 		cfg.KubeConfigPath = mkapi.DefaultKubeConfigPath
 	}
 	if cfg.Port == 0 {
 		cfg.Port = commonconstants.DefaultMinKAPIPort
 	}
+	if cfg.SecurityConfig.AuthMode == "" {
+		cfg.SecurityConfig.AuthMode = mkapi.AuthModeNone
+	}
+	if cfg.SecurityConfig.CertDir == "" {
+		cfg.SecurityConfig.CertDir = defaultCertDir()
+	}
+	if cfg.SecurityConfig.TokenFile == "" {
+		cfg.SecurityConfig.TokenFile = filepath.Join(cfg.SecurityConfig.CertDir, tokenFileName)
+	}
 }
 
 func handleError(w http.ResponseWriter, r *http.Request, err error) {
@@ -786,18 +1503,14 @@ func handleError(w http.ResponseWriter, r *http.Request, err error) {
 func handleStatusError(w http.ResponseWriter, r *http.Request, statusErr *apierrors.StatusError) {
 	log := logr.FromContextOrDiscard(r.Context())
 	log.Error(statusErr, "status error", "gvk", statusErr.ErrStatus.GroupVersionKind, "code", statusErr.ErrStatus.Code, "reason", statusErr.ErrStatus.Reason, "message", statusErr.ErrStatus.Message)
-	w.WriteHeader(int(statusErr.ErrStatus.Code))
-	w.Header().Set("Content-Type", "application/json")
-	writeJsonResponse(w, r, statusErr.ErrStatus)
+	writeStatusError(w, r, statusErr)
 }
 
 func handleInternalServerError(w http.ResponseWriter, r *http.Request, err error) {
 	log := logr.FromContextOrDiscard(r.Context())
 	statusErr := apierrors.NewInternalError(err)
 	log.Error(err, "internal server error")
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Header().Set("Content-Type", "application/json")
-	writeJsonResponse(w, r, statusErr.ErrStatus)
+	writeStatusError(w, r, statusErr)
 }
 
 func handleBadRequest(w http.ResponseWriter, r *http.Request, err error) {
@@ -805,17 +1518,45 @@ func handleBadRequest(w http.ResponseWriter, r *http.Request, err error) {
 	err = fmt.Errorf("cannot handle request %q: %w", r.Method+" "+r.RequestURI, err)
 	log.Error(err, "bad request", "method", r.Method, "requestURI", r.RequestURI)
 	statusErr := apierrors.NewBadRequest(err.Error())
-	w.WriteHeader(http.StatusBadRequest)
-	w.Header().Set("Content-Type", "application/json")
-	writeJsonResponse(w, r, statusErr.ErrStatus)
+	writeStatusError(w, r, statusErr)
+}
+
+// writeResponse encodes the object to the response writer in protobuf if the request accepts it and the object supports
+// it, otherwise in JSON.
+func writeResponse(w http.ResponseWriter, r *http.Request, obj any) {
+	writeResponseWithStatusCode(w, r, http.StatusOK, obj)
+}
+
+// writeObjectResponse is like writeResponse but writes the given object or list of objects of the kind described by the
+// given descriptor as a metav1.Table if the request accepts one.
+func writeObjectResponse(w http.ResponseWriter, r *http.Request, d typeinfo.Descriptor, obj runtime.Object) {
+	if !acceptsTable(r) {
+		writeResponse(w, r, obj)
+		return
+	}
+	includeObject, err := printers.ParseIncludeObject(r.URL.Query().Get("includeObject"))
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	table, err := printers.ConvertToTable(d, obj, printers.Options{IncludeObject: includeObject})
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	writeResponse(w, r, table)
 }
 
-// writeJsonResponse sets Content-Type to application/json  and encodes the object to the response writer.
-func writeJsonResponse(w http.ResponseWriter, r *http.Request, obj any) {
+// writeResponseWithStatusCode is like writeResponse but writes the given status code.
+func writeResponseWithStatusCode(w http.ResponseWriter, r *http.Request, statusCode int, obj any) {
 	log := logr.FromContextOrDiscard(r.Context())
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(obj); err != nil {
+	contentType, data, err := encodeResponse(r, obj)
+	if err != nil {
 		log.Error(err, "cannot  encode response", "obj", obj)
 		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
 	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
 }
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// historyEntry records a single change made to a store at a given resource version. It is never serialized and is
+// converted into a proper watch.Event for each watcher based on its match criteria.
+type historyEntry struct {
+	eventType watch.EventType
+	// object is the state of the object after the change. For watch.Deleted this is the last state of the object.
+	object runtime.Object
+	// prevObject is the state of the object before the change. Only set for watch.Modified.
+	prevObject      runtime.Object
+	resourceVersion int64
+}
+
+// eventHistory is a bounded ring of the most recent changes made to a store, ordered by resource version.
+type eventHistory struct {
+	entries []*historyEntry
+	// head is the index of the oldest entry in entries.
+	head int
+	size int
+	// floor is the resource version up to which changes are no longer available in the history. All changes with a
+	// resource version greater than floor are available.
+	floor int64
+}
+
+func newEventHistory(capacity int, floor int64) *eventHistory {
+	return &eventHistory{
+		entries: make([]*historyEntry, capacity),
+		floor:   floor,
+	}
+}
+
+// add appends the given entry to the history, evicting the oldest entry and raising the floor if the history is full.
+func (h *eventHistory) add(e *historyEntry) {
+	capacity := len(h.entries)
+	if capacity == 0 {
+		h.floor = e.resourceVersion
+		return
+	}
+	if h.size == capacity {
+		h.floor = h.entries[h.head].resourceVersion
+		h.entries[h.head] = e
+		h.head = (h.head + 1) % capacity
+		return
+	}
+	h.entries[(h.head+h.size)%capacity] = e
+	h.size++
+}
+
+// since returns the entries having a resource version greater than the given version in the order they were made. It
+// returns a 410 Expired error if changes after the given version have already been evicted from the history.
+func (h *eventHistory) since(version int64) ([]*historyEntry, error) {
+	if version < h.floor {
+		return nil, apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", version, h.floor))
+	}
+	capacity := len(h.entries)
+	var entries []*historyEntry
+	for i := range h.size {
+		e := h.entries[(h.head+i)%capacity]
+		if e.resourceVersion > version {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
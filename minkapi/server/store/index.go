@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// fieldIndexPrefix prefixes the names of the indexes by the value of a selectable field label.
+	fieldIndexPrefix = "field:"
+	// labelIndexPrefix prefixes the names of the indexes by the value of a label key.
+	labelIndexPrefix = "label:"
+)
+
+// newIndexers returns the cache.Indexers for the given index configuration, evaluating field labels with fieldsFn.
+func newIndexers(cfg mkapi.IndexConfig, fieldsFn mkapi.ObjectFieldsFunc) cache.Indexers {
+	indexers := cache.Indexers{}
+	if cfg.Namespace {
+		indexers[cache.NamespaceIndex] = cache.MetaNamespaceIndexFunc
+	}
+	for _, field := range cfg.FieldLabels {
+		indexers[fieldIndexPrefix+field] = func(obj any) ([]string, error) {
+			mo, err := AsMeta(obj)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := fieldsFn(mo)[field]
+			if !ok {
+				return nil, nil
+			}
+			return []string{value}, nil
+		}
+	}
+	for _, key := range cfg.LabelKeys {
+		indexers[labelIndexPrefix+key] = func(obj any) ([]string, error) {
+			mo, err := AsMeta(obj)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := mo.GetLabels()[key]
+			if !ok {
+				return nil, nil
+			}
+			return []string{value}, nil
+		}
+	}
+	return indexers
+}
+
+// indexLookup is a lookup of the objects having any of the given values in an index.
+type indexLookup struct {
+	indexName string
+	values    []string
+}
+
+// candidates returns the objects of the store that can match the given criteria. These are the objects of the most
+// selective index lookup supported by the criteria, or all objects if the criteria cannot be served by an index. The
+// candidates must still be matched against the criteria.
+func (s *InMemResourceStore) candidates(c mkapi.MatchCriteria) ([]any, error) {
+	var items []any
+	indexed := false
+	for _, l := range s.indexLookups(c) {
+		var lookupItems []any
+		for _, v := range l.values {
+			vItems, err := s.cache.ByIndex(l.indexName, v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot lookup %q in index %q of %q: %w", v, l.indexName, s.args.ObjectGVK, err)
+			}
+			lookupItems = append(lookupItems, vItems...)
+		}
+		if !indexed || len(lookupItems) < len(items) {
+			items, indexed = lookupItems, true
+		}
+		if len(items) == 0 {
+			break
+		}
+	}
+	if !indexed {
+		items = s.cache.List()
+	}
+	return items, nil
+}
+
+// indexLookups returns the lookups in the indexes of the store that restrict the objects to a superset of those
+// matching the given criteria.
+func (s *InMemResourceStore) indexLookups(c mkapi.MatchCriteria) (lookups []indexLookup) {
+	indexes := s.cache.GetIndexers()
+	if _, ok := indexes[cache.NamespaceIndex]; ok && c.Namespace != "" {
+		lookups = append(lookups, indexLookup{indexName: cache.NamespaceIndex, values: []string{c.Namespace}})
+	}
+	if c.FieldSelector != nil {
+		for _, field := range s.args.Indexes.FieldLabels {
+			if value, ok := c.FieldSelector.RequiresExactMatch(field); ok {
+				lookups = append(lookups, indexLookup{indexName: fieldIndexPrefix + field, values: []string{value}})
+			}
+		}
+	}
+	if c.LabelSelector != nil {
+		requirements, _ := c.LabelSelector.Requirements()
+		for _, r := range requirements {
+			indexName := labelIndexPrefix + r.Key()
+			if _, ok := indexes[indexName]; !ok {
+				continue
+			}
+			switch r.Operator() {
+			case selection.Equals, selection.DoubleEquals, selection.In:
+				lookups = append(lookups, indexLookup{indexName: indexName, values: r.Values().UnsortedList()})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// continueToken is the decoded form of the continue parameter handed out for chunked lists. It pins the list to the
+// resource version at which the first chunk was served and records the key of the last object returned so far.
+type continueToken struct {
+	ResourceVersion int64  `json:"rv"`
+	StartKey        string `json:"start"`
+}
+
+// PageMetaObjects returns the chunk of objects selected by the Limit and Continue of the given criteria, ordered by
+// their namespace/name key, along with the metav1.ListMeta to set on the list object.
+//
+// A list without Limit is taken from the current objects returned by listFn, and is served at currVersion, the current
+// resource version of the store(s) backing them. Like in the kube-apiserver, the chunks of a list with Limit are
+// consistent with each other: they are taken from the objects returned by listAtFn for currVersion, or for the resource
+// version of the continue token, which are reconstructed from the watch history. A 410 Expired error is returned once
+// changes made after that version are no longer available in the history.
+func PageMetaObjects(currVersion int64, c mkapi.MatchCriteria, listFn func() ([]metav1.Object, error), listAtFn func(version int64) ([]metav1.Object, error)) (page []metav1.Object, listMeta metav1.ListMeta, err error) {
+	listVersion := currVersion
+	var (
+		items    []metav1.Object
+		startKey string
+	)
+	if c.Continue != "" {
+		var token continueToken
+		token, err = decodeContinueToken(c.Continue)
+		if err != nil {
+			return
+		}
+		items, err = listAtFn(token.ResourceVersion)
+		if apierrors.IsResourceExpired(err) {
+			err = apierrors.NewResourceExpired(fmt.Sprintf("the provided continue parameter is too old to display a consistent list result, changes after resourceVersion %d are no longer available. Start a new list without the continue parameter", token.ResourceVersion))
+			return
+		}
+		listVersion = token.ResourceVersion
+		startKey = token.StartKey
+	} else if c.Limit > 0 {
+		items, err = listAtFn(currVersion)
+	} else {
+		items, err = listFn()
+	}
+	if err != nil {
+		return
+	}
+	listMeta.ResourceVersion = strconv.FormatInt(listVersion, 10)
+
+	slices.SortFunc(items, func(a, b metav1.Object) int {
+		return strings.Compare(objutil.CacheName(a).String(), objutil.CacheName(b).String())
+	})
+	if startKey != "" {
+		startIdx, found := slices.BinarySearchFunc(items, startKey, func(o metav1.Object, key string) int {
+			return strings.Compare(objutil.CacheName(o).String(), key)
+		})
+		if found {
+			startIdx++
+		}
+		items = items[startIdx:]
+	}
+	if c.Limit <= 0 || int64(len(items)) <= c.Limit {
+		page = items
+		return
+	}
+	page = items[:c.Limit]
+	remaining := int64(len(items)) - c.Limit
+	listMeta.RemainingItemCount = &remaining
+	listMeta.Continue, err = encodeContinueToken(continueToken{
+		ResourceVersion: listVersion,
+		StartKey:        objutil.CacheName(page[len(page)-1]).String(),
+	})
+	return
+}
+
+func encodeContinueToken(token continueToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", apierrors.NewInternalError(fmt.Errorf("cannot encode continue token: %w", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeContinueToken(encoded string) (token continueToken, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		err = apierrors.NewBadRequest(fmt.Sprintf("invalid continue token %q: %v", encoded, err))
+		return
+	}
+	if err = json.Unmarshal(data, &token); err != nil {
+		err = apierrors.NewBadRequest(fmt.Sprintf("invalid continue token %q: %v", encoded, err))
+		return
+	}
+	if token.ResourceVersion <= 0 || token.StartKey == "" {
+		err = apierrors.NewBadRequest(fmt.Sprintf("invalid continue token %q: missing resource version or start key", encoded))
+	}
+	return
+}
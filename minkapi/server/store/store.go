@@ -5,12 +5,15 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
 	"github.com/gardener/scaling-advisor/common/objutil"
 	"math"
 	"reflect"
+	"slices"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -21,22 +24,40 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
 )
 
 var _ mkapi.ResourceStore = (*InMemResourceStore)(nil)
 
+// optimisticLockErrorMsg is the message of the kube-apiserver for updates of objects with a stale resource version.
+const optimisticLockErrorMsg = "the object has been modified; please apply your changes to the latest version and try again"
+
 type InMemResourceStore struct {
-	args        *mkapi.ResourceStoreArgs
-	cache       cache.Store
-	broadcaster *watch.Broadcaster
+	args *mkapi.ResourceStoreArgs
+	// cache holds the objects of the store by key, along with the secondary indexes configured in args.
+	cache cache.Indexer
 	// versionCounter is the atomic counter for generating monotonically increasing resource versions
 	versionCounter *atomic.Int64
 	log            logr.Logger
+	// mu serializes changes to the store so that the order of the history and of broadcast events matches the order of
+	// resource versions. It also guards registration of new watchers.
+	mu sync.Mutex
+	// history holds the most recent changes made to the store which are replayed to watchers starting from a past version.
+	history *eventHistory
+	// watchersMu guards watchers. It is separate from mu since watchers are unregistered while changes may be broadcast.
+	watchersMu sync.Mutex
+	// watchers holds the watchers of the active watches on the store.
+	watchers map[*storeWatcher]struct{}
+	// droppedWatchers is the number of watchers terminated since they lagged behind the changes made to the store.
+	droppedWatchers atomic.Int64
 }
 
 func (s *InMemResourceStore) GetVersionCounter() *atomic.Int64 {
@@ -50,98 +71,354 @@ func (s *InMemResourceStore) GetObjAndListGVK() (objKind schema.GroupVersionKind
 // NewInMemResourceStore returns an in-memory store for a given object GVK. TODO: think on simplifying parameters.
 func NewInMemResourceStore(log logr.Logger, args *mkapi.ResourceStoreArgs) *InMemResourceStore {
 	s := InMemResourceStore{
-		log:   log,
-		args:  args,
-		cache: cache.NewStore(cache.MetaNamespaceKeyFunc),
-		//broadcaster: watch.NewBroadcaster(watchQueueSize, watch.DropIfChannelFull),
-		broadcaster:    watch.NewBroadcaster(args.WatchConfig.QueueSize, watch.WaitIfChannelFull),
+		log:            log,
+		args:           args,
 		versionCounter: args.VersionCounter,
+		watchers:       make(map[*storeWatcher]struct{}),
 	}
+	s.cache = cache.NewIndexer(cache.MetaNamespaceKeyFunc, newIndexers(args.Indexes, s.selectableFields()))
 	if s.versionCounter == nil {
 		s.versionCounter = &atomic.Int64{}
 	}
-	s.log.V(4).Info("created in memory resource store", "GVK", args.ObjectGVK, "resourceName", args.Name, "watchTimeout", args.WatchConfig.Timeout, "watchQueueSize", args.WatchConfig.QueueSize)
+	s.history = newEventHistory(s.historySize(), 0)
+	s.log.V(4).Info("created in memory resource store", "GVK", args.ObjectGVK, "resourceName", args.Name, "watchTimeout", args.WatchConfig.Timeout, "watchQueueSize", args.WatchConfig.QueueSize, "watchHistorySize", s.historySize())
 	return &s
 }
 
+// Reset clears all objects and the history of the store. Existing watchers are terminated and watches from any version
+// prior to the reset will fail as expired.
 func (s *InMemResourceStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.log.V(4).Info("resetting store", "kind", s.args.ObjectGVK.Kind)
-	s.cache = cache.NewStore(cache.MetaNamespaceKeyFunc)
-	s.broadcaster = watch.NewBroadcaster(s.args.WatchConfig.QueueSize, watch.WaitIfChannelFull)
+	if err := s.replaceContents(nil, s.CurrentResourceVersion()); err != nil {
+		s.log.Error(err, "failed to reset store", "kind", s.args.ObjectGVK.Kind)
+	}
+}
+
+// Restore replaces the objects of the store with copies of the given objects, retaining their resource versions, and
+// sets the resource version of the store to the given version. The resource version is never lowered so that versions
+// already observed by clients are not reused. Like Reset, existing watchers are terminated.
+func (s *InMemResourceStore) Restore(version int64, objs []metav1.Object) error {
+	items := make([]any, 0, len(objs))
+	for _, mo := range objs {
+		o, err := s.validateRuntimeObj(mo)
+		if err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		objVersion, err := ParseObjectResourceVersion(mo)
+		if err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		version = max(version, objVersion)
+		items = append(items, o.DeepCopyObject())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version = max(version, s.CurrentResourceVersion())
+	s.log.V(4).Info("restoring store", "kind", s.args.ObjectGVK.Kind, "numObjects", len(objs), "resourceVersion", version)
+	s.versionCounter.Store(version)
+	return s.replaceContents(items, version)
+}
+
+// replaceContents replaces the objects in the cache of the store with the given items and clears its history. Existing
+// watchers are terminated so that they re-watch and fail as expired for any version prior to the given floor. The
+// cache itself is retained since it is read without holding s.mu. Must be called with s.mu held.
+func (s *InMemResourceStore) replaceContents(items []any, floor int64) error {
+	if err := s.cache.Replace(items, ""); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("cannot replace objects in store: %w", err))
+	}
+	s.history = newEventHistory(s.historySize(), floor)
+	s.terminateWatchers()
+	return nil
+}
+
+// clock returns the clock of the store, which defaults to the real clock.
+func (s *InMemResourceStore) clock() clock.WithTickerAndDelayedExecution {
+	if s.args.Clock == nil {
+		return clock.RealClock{}
+	}
+	return s.args.Clock
 }
 
+func (s *InMemResourceStore) watchQueueSize() int {
+	if s.args.WatchConfig.QueueSize <= 0 {
+		return mkapi.DefaultWatchQueueSize
+	}
+	return s.args.WatchConfig.QueueSize
+}
+
+func (s *InMemResourceStore) historySize() int {
+	if s.args.WatchConfig.HistorySize <= 0 {
+		return mkapi.DefaultWatchHistorySize
+	}
+	return s.args.WatchConfig.HistorySize
+}
+
+// Add stores a copy of the given object, after setting its resource version to the next version of the store. The
+// caller retains ownership of the given object.
 func (s *InMemResourceStore) Add(mo metav1.Object) error {
 	o, err := s.validateRuntimeObj(mo)
 	if err != nil {
 		return err
 	}
 	key := objutil.CacheName(mo)
-	mo.SetResourceVersion(s.NextResourceVersionAsString())
-	err = s.cache.Add(o)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version := s.nextResourceVersion()
+	mo.SetResourceVersion(strconv.FormatInt(version, 10))
+	stored := o.DeepCopyObject()
+	err = s.cache.Add(stored)
 	if err != nil {
 		return apierrors.NewInternalError(fmt.Errorf("cannot add object %q to store: %w", key, err))
 	}
 	s.log.V(4).Info("added object to store", "kind", s.args.ObjectGVK.Kind, "key", key, "resourceVersion", mo.GetResourceVersion())
-
-	go func() {
-		err = s.broadcaster.Action(watch.Added, o)
-		if err != nil {
-			s.log.Error(err, "failed to broadcast object add", "key", key)
-		}
-	}()
+	s.recordChange(&historyEntry{eventType: watch.Added, object: stored, resourceVersion: version})
 	return nil
 }
 
+// Update replaces the stored object having the name of the given object with a copy of it, after setting its resource
+// version to the next version of the store and carrying over its deletion state. The caller retains ownership of the
+// given object.
 func (s *InMemResourceStore) Update(mo metav1.Object) error {
 	o, err := s.validateRuntimeObj(mo)
 	if err != nil {
 		return err
 	}
 	key := objutil.CacheName(mo)
-	mo.SetResourceVersion(s.NextResourceVersionAsString())
-	err = s.cache.Update(o)
+	// the clock is only used without s.mu held. See removeAfterGracePeriod.
+	now := s.clock().Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var prevObj runtime.Object
+	if cachedObj, exists, _ := s.cache.GetByKey(key.String()); exists {
+		storedObj := cachedObj.(metav1.Object)
+		if err = s.CheckResourceVersionPrecondition(mo, storedObj); err != nil {
+			return err
+		}
+		if err = s.preserveDeletionState(mo, storedObj); err != nil {
+			return err
+		}
+		prevObj = cachedObj.(runtime.Object)
+	}
+	version := s.nextResourceVersion()
+	mo.SetResourceVersion(strconv.FormatInt(version, 10))
+	stored := o.DeepCopyObject()
+	if prevObj != nil && isDeletionComplete(mo, now) {
+		// like the kube-apiserver, an update clearing the last finalizer of an object marked for deletion removes it.
+		return s.remove(key.String(), stored, version)
+	}
+	err = s.cache.Update(stored)
 	if err != nil {
 		return apierrors.NewInternalError(fmt.Errorf("cannot update object %q in store: %w", key, err))
 	}
 	s.log.V(4).Info("updated object in store", "kind", s.args.ObjectGVK.Kind, "key", key, "resourceVersion", mo.GetResourceVersion())
-	go func() {
-		err = s.broadcaster.Action(watch.Modified, o)
-		if err != nil {
-			s.log.Error(err, "failed to broadcast object update", "key", key)
+	s.recordChange(&historyEntry{eventType: watch.Modified, object: stored, prevObject: prevObj, resourceVersion: version})
+	return nil
+}
+
+// preserveDeletionState carries over the deletionTimestamp and deletionGracePeriodSeconds of the given stored object to
+// the given updated object, since they cannot be changed by updates. Like the kube-apiserver, it returns a 422 Invalid
+// error if the update adds finalizers to an object marked for deletion.
+func (s *InMemResourceStore) preserveDeletionState(mo metav1.Object, storedObj metav1.Object) error {
+	mo.SetDeletionTimestamp(storedObj.GetDeletionTimestamp())
+	mo.SetDeletionGracePeriodSeconds(storedObj.GetDeletionGracePeriodSeconds())
+	if storedObj.GetDeletionTimestamp() == nil {
+		return nil
+	}
+	storedFinalizers := sets.New(storedObj.GetFinalizers()...)
+	for _, f := range mo.GetFinalizers() {
+		if !storedFinalizers.Has(f) {
+			return apierrors.NewInvalid(s.args.ObjectGVK.GroupKind(), mo.GetName(), field.ErrorList{
+				field.Forbidden(field.NewPath("metadata", "finalizers"), fmt.Sprintf("no new finalizers can be added if the object is being deleted, found new finalizers %q", f)),
+			})
 		}
-	}()
+	}
 	return nil
 }
 
-func (s *InMemResourceStore) DeleteByKey(key string) error {
-	o, err := s.GetByKey(key)
+// DeleteByKey deletes the object with the given key honouring the given opts. An object with finalizers, a pod
+// deleted with a positive grace period or an object deleted with foreground or orphan propagation is only marked for
+// deletion by setting its deletionTimestamp. It is removed once its finalizers are cleared and its grace period has
+// elapsed.
+func (s *InMemResourceStore) DeleteByKey(key string, opts mkapi.DeleteOptions) error {
+	propagationFinalizer, err := s.propagationFinalizer(key, opts)
 	if err != nil {
 		return err
 	}
-	mo, err := AsMeta(o)
-	if err != nil {
+	// the clock is only used without s.mu held. See removeAfterGracePeriod.
+	uid, gracePeriod, err := s.deleteByKey(key, opts, propagationFinalizer, s.clock().Now())
+	if err != nil || gracePeriod == 0 {
 		return err
 	}
-	err = s.cache.Delete(mo)
+	s.clock().AfterFunc(gracePeriod, func() {
+		s.removeAfterGracePeriod(key, uid)
+	})
+	return nil
+}
+
+// deleteByKey deletes the object with the given key at the given time like DeleteByKey, adding the given propagation
+// finalizer if it is only marked for deletion. If the object is marked for deletion with a positive grace period, its
+// UID and grace period are returned.
+func (s *InMemResourceStore) deleteByKey(key string, opts mkapi.DeleteOptions, propagationFinalizer string, now time.Time) (uid types.UID, gracePeriod time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.GetByKey(key)
 	if err != nil {
+		return
+	}
+	mo, err := AsMeta(o)
+	if err != nil {
+		return
+	}
+	if err = s.CheckDeletePreconditions(mo, opts.Preconditions); err != nil {
+		return
+	}
+	gracePeriodSeconds := s.gracePeriodSeconds(opts)
+	if mo.GetDeletionTimestamp() != nil {
+		// the object is already marked for deletion.
+		return
+	}
+	if len(mo.GetFinalizers()) == 0 && propagationFinalizer == "" && gracePeriodSeconds == 0 {
+		// deletions also advance the resource version so that continue tokens issued before the deletion expire.
+		version := s.nextResourceVersion()
+		mo.SetResourceVersion(strconv.FormatInt(version, 10))
+		mo.SetDeletionTimestamp(&metav1.Time{Time: time.Time{}})
+		err = s.remove(key, o, version)
+		return
+	}
+	if err = s.markForDeletion(key, o, gracePeriodSeconds, propagationFinalizer, now); err != nil {
+		return
+	}
+	return mo.GetUID(), time.Duration(gracePeriodSeconds) * time.Second, nil
+}
+
+// remove removes the given object with the given key from the store recording its removal at the given version. Must
+// be called with s.mu held.
+func (s *InMemResourceStore) remove(key string, o runtime.Object, version int64) error {
+	if err := s.cache.Delete(o); err != nil {
 		err = fmt.Errorf("cannot delete object with key %q from store: %w", key, err)
 		return apierrors.NewInternalError(err)
 	}
-	mo.SetDeletionTimestamp(&metav1.Time{Time: time.Time{}})
 	s.log.V(4).Info("deleted object", "kind", s.args.ObjectGVK.Kind, "key", key)
-	go func() {
-		err = s.broadcaster.Action(watch.Deleted, o)
-		if err != nil {
-			s.log.Error(err, "failed to broadcast object delete", "key", key, "resourceVersion", mo.GetResourceVersion())
-		}
-	}()
+	s.recordChange(&historyEntry{eventType: watch.Deleted, object: o, resourceVersion: version})
+	return nil
+}
+
+// markForDeletion sets the deletionTimestamp of the given object with the given key to the end of the given grace
+// period from the given time and adds the given propagation finalizer, if any. Must be called with s.mu held.
+func (s *InMemResourceStore) markForDeletion(key string, o runtime.Object, gracePeriodSeconds int64, propagationFinalizer string, now time.Time) error {
+	marked := o.DeepCopyObject()
+	mo, err := AsMeta(marked)
+	if err != nil {
+		return err
+	}
+	if propagationFinalizer != "" && !slices.Contains(mo.GetFinalizers(), propagationFinalizer) {
+		mo.SetFinalizers(append(mo.GetFinalizers(), propagationFinalizer))
+	}
+	gracePeriod := time.Duration(gracePeriodSeconds) * time.Second
+	mo.SetDeletionTimestamp(&metav1.Time{Time: now.Add(gracePeriod).Truncate(time.Second)})
+	mo.SetDeletionGracePeriodSeconds(&gracePeriodSeconds)
+	version := s.nextResourceVersion()
+	mo.SetResourceVersion(strconv.FormatInt(version, 10))
+	if err = s.cache.Update(marked); err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("cannot mark object %q for deletion: %w", key, err))
+	}
+	s.log.V(4).Info("marked object for deletion", "kind", s.args.ObjectGVK.Kind, "key", key, "finalizers", mo.GetFinalizers(), "gracePeriodSeconds", gracePeriodSeconds)
+	s.recordChange(&historyEntry{eventType: watch.Modified, object: marked, prevObject: o, resourceVersion: version})
+	return nil
+}
+
+// removeAfterGracePeriod removes the object with the given key and UID once its grace period has elapsed, if it has
+// no finalizers left. It is run by the clock of the store, and a fake clock runs it with its own lock held while
+// stepping. So that the clock is neither used by it nor waits for it, the clock is only used without s.mu held.
+func (s *InMemResourceStore) removeAfterGracePeriod(key string, uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.GetByKey(key)
+	if err != nil {
+		return
+	}
+	mo, err := AsMeta(o)
+	if err != nil || mo.GetUID() != uid || mo.GetDeletionTimestamp() == nil || len(mo.GetFinalizers()) > 0 {
+		return
+	}
+	version := s.nextResourceVersion()
+	mo.SetResourceVersion(strconv.FormatInt(version, 10))
+	if err = s.remove(key, o, version); err != nil {
+		s.log.Error(err, "failed to remove object after grace period", "kind", s.args.ObjectGVK.Kind, "key", key)
+	}
+}
+
+// isDeletionComplete returns whether the given object is marked for deletion, has no finalizers and its grace period
+// has elapsed at the given time.
+func isDeletionComplete(mo metav1.Object, now time.Time) bool {
+	deletionTimestamp := mo.GetDeletionTimestamp()
+	return deletionTimestamp != nil && len(mo.GetFinalizers()) == 0 && !deletionTimestamp.After(now)
+}
+
+// gracePeriodSeconds returns the grace period of a deletion with the given opts, which is only honoured for pods.
+func (s *InMemResourceStore) gracePeriodSeconds(opts mkapi.DeleteOptions) int64 {
+	if s.args.ObjectGVK != typeinfo.PodsDescriptor.GVK || opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds < 0 {
+		return 0
+	}
+	return *opts.GracePeriodSeconds
+}
+
+// propagationFinalizer returns the finalizer by which the garbage collector of the view recognizes the propagation
+// policy of a deletion of the object with the given key with the given opts. It is empty for background propagation.
+func (s *InMemResourceStore) propagationFinalizer(key string, opts mkapi.DeleteOptions) (string, error) {
+	if opts.PropagationPolicy == nil {
+		return "", nil
+	}
+	switch *opts.PropagationPolicy {
+	case metav1.DeletePropagationBackground:
+		return "", nil
+	case metav1.DeletePropagationForeground:
+		return metav1.FinalizerDeleteDependents, nil
+	case metav1.DeletePropagationOrphan:
+		return metav1.FinalizerOrphanDependents, nil
+	default:
+		return "", apierrors.NewBadRequest(fmt.Sprintf("cannot delete object %q: unsupported propagationPolicy %q", key, *opts.PropagationPolicy))
+	}
+}
+
+// CheckDeletePreconditions returns a 409 Conflict error if the given stored object does not fulfill the given preconditions.
+func (s *InMemResourceStore) CheckDeletePreconditions(mo metav1.Object, preconditions metav1.Preconditions) error {
+	if preconditions.UID != nil && *preconditions.UID != mo.GetUID() {
+		return apierrors.NewConflict(s.groupResource(), mo.GetName(), fmt.Errorf("Precondition failed: UID in precondition: %v, UID in object meta: %v", *preconditions.UID, mo.GetUID()))
+	}
+	if preconditions.ResourceVersion != nil && *preconditions.ResourceVersion != mo.GetResourceVersion() {
+		return apierrors.NewConflict(s.groupResource(), mo.GetName(), fmt.Errorf("Precondition failed: ResourceVersion in precondition: %v, ResourceVersion in object meta: %v", *preconditions.ResourceVersion, mo.GetResourceVersion()))
+	}
 	return nil
 }
 
-func (s *InMemResourceStore) Delete(objName cache.ObjectName) error {
-	return s.DeleteByKey(objName.String())
+// recordChange appends the given entry to the history and broadcasts it to all watchers. Must be called with s.mu held.
+func (s *InMemResourceStore) recordChange(e *historyEntry) {
+	s.history.add(e)
+	s.broadcast(e)
 }
 
+func (s *InMemResourceStore) Delete(objName cache.ObjectName, opts mkapi.DeleteOptions) error {
+	return s.DeleteByKey(objName.String(), opts)
+}
+
+// CheckResourceVersionPrecondition returns a 409 Conflict error if the given object carries a resource version that
+// differs from that of the given stored object. An object without a resource version is updated unconditionally.
+func (s *InMemResourceStore) CheckResourceVersionPrecondition(mo metav1.Object, storedObj metav1.Object) error {
+	if rv := mo.GetResourceVersion(); rv != "" && rv != storedObj.GetResourceVersion() {
+		s.log.V(4).Info("rejecting update of stale object", "kind", s.args.ObjectGVK.Kind, "key", objutil.CacheName(mo), "resourceVersion", rv, "storedResourceVersion", storedObj.GetResourceVersion())
+		return apierrors.NewConflict(s.groupResource(), mo.GetName(), errors.New(optimisticLockErrorMsg))
+	}
+	return nil
+}
+
+func (s *InMemResourceStore) groupResource() schema.GroupResource {
+	return schema.GroupResource{Group: s.args.ObjectGVK.Group, Resource: s.args.Name}
+}
+
+// GetByKey returns a copy of the object with the given key, which is owned by the caller.
 func (s *InMemResourceStore) GetByKey(key string) (o runtime.Object, err error) {
 	obj, exists, err := s.cache.GetByKey(key)
 	if err != nil {
@@ -151,7 +428,7 @@ func (s *InMemResourceStore) GetByKey(key string) (o runtime.Object, err error)
 	}
 	if !exists {
 		s.log.V(4).Info("did not find object by key", "key", key)
-		err = apierrors.NewNotFound(schema.GroupResource{Group: s.args.ObjectGVK.Group, Resource: s.args.Name}, key)
+		err = apierrors.NewNotFound(s.groupResource(), key)
 		return
 	}
 	o, ok := obj.(runtime.Object)
@@ -161,6 +438,7 @@ func (s *InMemResourceStore) GetByKey(key string) (o runtime.Object, err error)
 		err = apierrors.NewInternalError(err)
 		return
 	}
+	o = o.DeepCopyObject()
 	return
 }
 
@@ -168,73 +446,30 @@ func (s *InMemResourceStore) Get(objName cache.ObjectName) (o runtime.Object, er
 	return s.GetByKey(objName.String())
 }
 
+// List returns the objects matching the given criteria as a list object (Ex: *corev1.PodList) ordered by key. If the
+// criteria specifies a Limit, only a chunk of objects is returned along with a continue token for the next chunk.
 func (s *InMemResourceStore) List(c mkapi.MatchCriteria) (listObj runtime.Object, err error) {
-	items := s.cache.List()
-	currVersionStr := fmt.Sprintf("%d", s.CurrentResourceVersion())
-	typesMap := typeinfo.SupportedScheme.KnownTypes(s.args.ObjectGVK.GroupVersion())
-	listType, ok := typesMap[s.args.ObjectListGVK.Kind] // Ex: Get Go reflect.type for the PodList
-	if !ok {
-		return nil, runtime.NewNotRegisteredErrForKind(typeinfo.SupportedScheme.Name(), s.args.ObjectListGVK)
-	}
-	listObjPtr := reflect.New(listType) // Ex: reflect.Value wrapper of *PodList
-	listObjVal := listObjPtr.Elem()     // Ex: reflect.Elem wrapper of PodList
-	typeMetaVal := listObjVal.FieldByName("TypeMeta")
-	if !typeMetaVal.IsValid() {
-		return nil, fmt.Errorf("failed to get TypeMeta field on %v", listObjVal)
-	}
-	listMetaVal := listObjVal.FieldByName("ListMeta")
-	if !listMetaVal.IsValid() {
-		return nil, fmt.Errorf("failed to get ListMeta field on %v", listObjVal)
-	}
-	typeMetaVal.Set(reflect.ValueOf(metav1.TypeMeta{
-		Kind:       s.args.ObjectListGVK.Kind,
-		APIVersion: s.args.ObjectGVK.GroupVersion().String(),
-	}))
-	listMetaVal.Set(reflect.ValueOf(metav1.ListMeta{
-		ResourceVersion: currVersionStr,
-	}))
-	itemsField := listObjVal.FieldByName("Items") // // Ex: corev1.Pod
-	if !itemsField.IsValid() || !itemsField.CanSet() || itemsField.Kind() != reflect.Slice {
-		return nil, fmt.Errorf("list object type %T for kind %q does not have a settable slice field named Items", listObj, s.args.ObjectGVK.Kind)
-	}
-	itemType := itemsField.Type().Elem() // e.g., corev1.Pod
-	resultSlice := reflect.MakeSlice(itemsField.Type(), 0, len(items))
-
-	objs, err := objutil.SliceOfAnyToRuntimeObj(items)
+	page, listMeta, err := PageMetaObjects(s.CurrentResourceVersion(), c, func() ([]metav1.Object, error) {
+		items, _, err := s.ListMetaObjects(c)
+		return items, err
+	}, func(version int64) ([]metav1.Object, error) {
+		return s.ListMetaObjectsAt(c, version)
+	})
 	if err != nil {
 		return
 	}
-	for _, obj := range objs {
-		metaV1Obj, err := AsMeta(obj)
-		if err != nil {
-			s.log.Error(err, "cannot access meta object", "obj", obj)
-			continue
-		}
-
-		if c.Namespace != "" && metaV1Obj.GetNamespace() != c.Namespace {
-			continue
-		}
-		if !c.LabelSelector.Matches(labels.Set(metaV1Obj.GetLabels())) {
-			continue
-		}
-		val := reflect.ValueOf(obj)
-		if val.Kind() != reflect.Ptr || val.IsNil() {
-			// ensure each cached obj is a non-nil pointer (Ex *corev1.Pod).
-			return nil, fmt.Errorf("element for kind %q is not a non-nil pointer: %T", s.args.ObjectGVK, obj)
-		}
-		if val.Elem().Type() != itemType {
-			// ensure each cached obj dereferences to the expected type (Ex corev1.Pod).
-			return nil, fmt.Errorf("type mismatch, list kind %q expects items of type %v, but got %v", s.args.ObjectListGVK, itemType, val.Elem().Type())
-		}
-		resultSlice = reflect.Append(resultSlice, val.Elem()) // Append the struct (not the pointer) into the .Items slice of the list.
-	}
-	itemsField.Set(resultSlice)
-	listObj = listObjPtr.Interface().(runtime.Object) // Ex: listObjPtr.Interface() gets the actual *core1.PodList which is then type-asserted to runtime.Object
-	return listObj, nil
+	return WrapMetaObjectsIntoRuntimeListObject(listMeta, s.args.ObjectGVK, s.args.ObjectListGVK, page)
 }
 
+// ListMetaObjects returns copies of the objects matching the given criteria, which are owned by the caller, along with
+// the highest resource version among them. Only the objects of the most selective index supported by the criteria are
+// visited. See mkapi.IndexConfig.
 func (s *InMemResourceStore) ListMetaObjects(c mkapi.MatchCriteria) (metaObjs []metav1.Object, maxVersion int64, err error) {
-	items := s.cache.List()
+	items, err := s.candidates(c)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", mkapi.ErrListObjects, err)
+		return
+	}
 	sliceSize := int(math.Min(float64(len(items)), float64(100)))
 	metaObjs = make([]metav1.Object, 0, sliceSize)
 	var mo metav1.Object
@@ -245,14 +480,14 @@ func (s *InMemResourceStore) ListMetaObjects(c mkapi.MatchCriteria) (metaObjs []
 			err = fmt.Errorf("%w: %w", mkapi.ErrListObjects, err)
 			return
 		}
-		if !c.Matches(mo) {
+		if !s.matches(c, mo) {
 			continue
 		}
 		version, err = ParseObjectResourceVersion(mo)
 		if err != nil {
 			return
 		}
-		metaObjs = append(metaObjs, mo)
+		metaObjs = append(metaObjs, item.(runtime.Object).DeepCopyObject().(metav1.Object))
 		if version > maxVersion {
 			maxVersion = version
 		}
@@ -260,8 +495,80 @@ func (s *InMemResourceStore) ListMetaObjects(c mkapi.MatchCriteria) (metaObjs []
 	return
 }
 
-func (s *InMemResourceStore) DeleteObjects(c mkapi.MatchCriteria) (delCount int, err error) {
-	items := s.cache.List()
+// ListMetaObjectsAt returns copies of the objects matching the given criteria as they were at the given resource
+// version, which are owned by the caller. The objects are reconstructed by rolling back the changes recorded in the
+// history after the given version, so a 410 Expired error is returned if any of these has already been evicted.
+func (s *InMemResourceStore) ListMetaObjectsAt(c mkapi.MatchCriteria, version int64) (metaObjs []metav1.Object, err error) {
+	s.mu.Lock()
+	items, err := s.candidates(c)
+	if err != nil {
+		s.mu.Unlock()
+		err = fmt.Errorf("%w: %w", mkapi.ErrListObjects, err)
+		return
+	}
+	entries, err := s.history.since(version)
+	s.mu.Unlock()
+	if err != nil {
+		return
+	}
+	objs := make(map[string]runtime.Object, len(items))
+	for _, item := range items {
+		o := item.(runtime.Object)
+		objs[objutil.CacheName(o.(metav1.Object)).String()] = o
+	}
+	// the earliest change made to an object after the version determines its state at the version.
+	for _, e := range slices.Backward(entries) {
+		key := objutil.CacheName(e.object.(metav1.Object)).String()
+		switch {
+		case e.eventType == watch.Deleted:
+			objs[key] = e.object
+		case e.eventType == watch.Modified && e.prevObject != nil:
+			objs[key] = e.prevObject
+		default:
+			delete(objs, key)
+		}
+	}
+	metaObjs = make([]metav1.Object, 0, len(objs))
+	for _, o := range objs {
+		mo := o.(metav1.Object)
+		if s.matches(c, mo) {
+			metaObjs = append(metaObjs, o.DeepCopyObject().(metav1.Object))
+		}
+	}
+	return
+}
+
+// AddedSince returns the UIDs by key of the objects that were added to the store by the earliest change made to them
+// after the given version, and so were absent at that version. Like ListMetaObjectsAt, it returns a 410 Expired error
+// if any change after the given version has already been evicted from the history.
+func (s *InMemResourceStore) AddedSince(version int64) (map[string]types.UID, error) {
+	s.mu.Lock()
+	entries, err := s.history.since(version)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	added := make(map[string]types.UID)
+	for _, e := range slices.Backward(entries) {
+		mo := e.object.(metav1.Object)
+		key := objutil.CacheName(mo).String()
+		if e.eventType == watch.Added || (e.eventType == watch.Modified && e.prevObject == nil) {
+			added[key] = mo.GetUID()
+		} else {
+			delete(added, key)
+		}
+	}
+	return added, nil
+}
+
+// DeleteObjects deletes the objects matching the given criteria one by one like Delete, so that a watch event is
+// emitted for each of them. Objects removed concurrently are skipped.
+func (s *InMemResourceStore) DeleteObjects(c mkapi.MatchCriteria, opts mkapi.DeleteOptions) (delObjs []metav1.Object, err error) {
+	items, err := s.candidates(c)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", mkapi.ErrDeleteObject, err)
+		return
+	}
 	var mo metav1.Object
 	for _, item := range items {
 		mo, err = AsMeta(item)
@@ -269,11 +576,12 @@ func (s *InMemResourceStore) DeleteObjects(c mkapi.MatchCriteria) (delCount int,
 			err = fmt.Errorf("%w: %w", mkapi.ErrDeleteObject, err)
 			return
 		}
-		if !c.Matches(mo) {
+		if !s.matches(c, mo) {
 			continue
 		}
 		objName := objutil.CacheName(mo)
-		_, err = s.Get(objName)
+		var obj runtime.Object
+		obj, err = s.Get(objName)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				continue
@@ -281,7 +589,7 @@ func (s *InMemResourceStore) DeleteObjects(c mkapi.MatchCriteria) (delCount int,
 			err = fmt.Errorf("%w: %w", mkapi.ErrDeleteObject, err)
 			return
 		}
-		err = s.Delete(objName)
+		err = s.Delete(objName, opts)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				continue
@@ -289,8 +597,12 @@ func (s *InMemResourceStore) DeleteObjects(c mkapi.MatchCriteria) (delCount int,
 			err = fmt.Errorf("%w: %w", mkapi.ErrDeleteObject, err)
 			return
 		}
-		delCount++
+		if mo, err = AsMeta(obj); err != nil {
+			return
+		}
+		delObjs = append(delObjs, mo)
 	}
+	err = nil
 	return
 }
 
@@ -308,76 +620,190 @@ func (s *InMemResourceStore) validateRuntimeObj(mo metav1.Object) (o runtime.Obj
 	return
 }
 
-func (s *InMemResourceStore) buildPendingWatchEvents(startVersion int64, namespace string, labelSelector labels.Selector) (watchEvents []watch.Event, err error) {
-	var skip bool
-	allItems := s.cache.List()
-	objs, err := objutil.SliceOfAnyToRuntimeObj(allItems)
-	if err != nil {
-		return
+// matches checks whether the given object satisfies the given criteria, evaluating field selectors against the
+// selectable fields of this store's kind.
+func (s *InMemResourceStore) matches(c mkapi.MatchCriteria, mo metav1.Object) bool {
+	return c.MatchesWithFields(mo, s.selectableFields()(mo))
+}
+
+func (s *InMemResourceStore) selectableFields() mkapi.ObjectFieldsFunc {
+	if s.args.SelectableFields == nil {
+		return mkapi.DefaultObjectFields
 	}
-	for _, o := range objs {
-		skip, err = shouldSkipObject(s.log, o, startVersion, namespace, labelSelector)
+	return s.args.SelectableFields
+}
+
+// buildPendingWatchEvents returns the events that a watch with the given options must be sent before any live events.
+// If initial events are requested or the start version is not positive, the events are synthetic watch.Added events for
+// the objects currently in the store. Otherwise, the changes made after the start version are replayed from the history.
+// Must be called with s.mu held.
+func (s *InMemResourceStore) buildPendingWatchEvents(opts mkapi.WatchOptions) (watchEvents []watch.Event, err error) {
+	var entries []*historyEntry
+	if opts.SendInitialEvents || opts.StartVersion <= 0 {
+		items, err := s.candidates(opts.MatchCriteria)
 		if err != nil {
 			return nil, err
 		}
-		if skip {
-			continue
+		objs, err := objutil.SliceOfAnyToRuntimeObj(items)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			entries = append(entries, &historyEntry{eventType: watch.Added, object: o})
+		}
+	} else {
+		entries, err = s.history.since(opts.StartVersion)
+		if err != nil {
+			return
+		}
+	}
+	for _, e := range entries {
+		event, ok, err := s.toWatchEvent(e, opts.MatchCriteria)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			watchEvents = append(watchEvents, event)
 		}
-		watchEvent := watch.Event{Type: watch.Added, Object: o}
-		watchEvents = append(watchEvents, watchEvent)
 	}
 	return
 }
 
 type EventCallbackFn func(watch.Event) (err error)
 
-func (s *InMemResourceStore) Watch(ctx context.Context, startVersion int64, namespace string, labelSelector labels.Selector, eventCallback mkapi.WatchEventCallback) error {
-	events, err := s.buildPendingWatchEvents(startVersion, namespace, labelSelector)
+// Watch invokes the given eventCallback for changes made to objects matching the given options. See
+// mkapi.WatchOptions. A 410 Expired error is returned if changes after the start version are no longer available in
+// the history.
+func (s *InMemResourceStore) Watch(ctx context.Context, opts mkapi.WatchOptions, eventCallback mkapi.WatchEventCallback) error {
+	events, watcher, version, err := s.startWatch(opts)
 	if err != nil {
 		return err
 	}
-	watcher, err := s.broadcaster.WatchWithPrefix(events)
-	if err != nil {
-		return fmt.Errorf("cannot start watch for gvk %q: %w", s.args.ObjectGVK, err)
+	defer s.removeWatcher(watcher)
+	for _, event := range events {
+		if err = eventCallback(event); err != nil {
+			return err
+		}
+	}
+	// minVersion is the version up to which changes have been covered by the pending events.
+	minVersion := opts.StartVersion
+	if opts.SendInitialEvents || opts.StartVersion <= 0 {
+		minVersion = version
+	}
+	if opts.SendInitialEvents {
+		if err = eventCallback(NewBookmarkEvent(s.args.ObjectGVK, version, true)); err != nil {
+			return err
+		}
+	}
+	// lastVersion is the highest version of changes received by this watcher, including the ones that did not match.
+	lastVersion := version
+	var bookmarkC <-chan time.Time
+	if opts.AllowBookmarks {
+		bookmarkTicker := s.clock().NewTicker(s.bookmarkInterval())
+		defer bookmarkTicker.Stop()
+		bookmarkC = bookmarkTicker.C()
 	}
-	defer watcher.Stop()
+	timeout := s.clock().NewTimer(s.args.WatchConfig.Timeout)
+	defer timeout.Stop()
 	for {
 		select {
-		case event, ok := <-watcher.ResultChan():
-			if !ok {
-				s.log.V(4).Info("no more events on watch result channel for gvk.", "gvk", s.args.ObjectGVK)
-				return nil
+		case <-watcher.done:
+			s.log.V(4).Info("watcher terminated", "gvk", s.args.ObjectGVK, "reason", watcher.err)
+			return watcher.err
+		case e := <-watcher.changes:
+			lastVersion = max(lastVersion, e.resourceVersion)
+			if e.resourceVersion <= minVersion {
+				continue
 			}
-			skip, err := shouldSkipObject(s.log, event.Object, startVersion, namespace, labelSelector)
+			event, ok, err := s.toWatchEvent(e, opts.MatchCriteria)
 			if err != nil {
 				return err
 			}
-			if skip {
+			if !ok {
 				continue
 			}
 			err = eventCallback(event)
 			if err != nil {
 				return err
 			}
-		case <-time.After(s.args.WatchConfig.Timeout):
-			s.log.V(4).Info("watcher timed out", "gvk", s.args.ObjectGVK, "watchTimeout", s.args.WatchConfig.Timeout, "startVersion", startVersion, "namespace", namespace, "labelSelector", labelSelector.String())
+		case <-bookmarkC:
+			err = eventCallback(NewBookmarkEvent(s.args.ObjectGVK, lastVersion, false))
+			if err != nil {
+				return err
+			}
+		case <-timeout.C():
+			s.log.V(4).Info("watcher timed out", "gvk", s.args.ObjectGVK, "watchTimeout", s.args.WatchConfig.Timeout, "startVersion", opts.StartVersion, "namespace", opts.Namespace, "labelSelector", opts.LabelSelector, "fieldSelector", opts.FieldSelector)
 			return nil
 		case <-ctx.Done():
-			s.log.V(4).Info("watch context cancelled", "gvk", s.args.ObjectGVK, "startVersion", startVersion, "namespace", namespace, "labelSelector", labelSelector.String())
+			s.log.V(4).Info("watch context cancelled", "gvk", s.args.ObjectGVK, "startVersion", opts.StartVersion, "namespace", opts.Namespace, "labelSelector", opts.LabelSelector, "fieldSelector", opts.FieldSelector)
 			return nil
 		}
 	}
 }
 
+// startWatch computes the pending events for a watch and registers a watcher for live changes. Both are done with
+// s.mu held so that no change is either missed or delivered twice. The returned version is the resource version of the
+// store at the time the watcher was registered.
+func (s *InMemResourceStore) startWatch(opts mkapi.WatchOptions) (events []watch.Event, watcher *storeWatcher, version int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events, err = s.buildPendingWatchEvents(opts)
+	if err != nil {
+		return
+	}
+	version = s.CurrentResourceVersion()
+	watcher = s.addWatcher()
+	return
+}
+
+// GetStats returns a point-in-time snapshot of the object and watch statistics of the store. The watch queue depth is
+// the number of changes buffered for each watcher.
+func (s *InMemResourceStore) GetStats() mkapi.ResourceStoreStats {
+	stats := mkapi.ResourceStoreStats{
+		ObjectCount:         len(s.cache.ListKeys()),
+		DroppedWatcherCount: s.droppedWatchers.Load(),
+	}
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	stats.WatcherCount = len(s.watchers)
+	for w := range s.watchers {
+		stats.MaxWatchQueueDepth = max(stats.MaxWatchQueueDepth, len(w.changes))
+	}
+	return stats
+}
+
+func (s *InMemResourceStore) bookmarkInterval() time.Duration {
+	if s.args.WatchConfig.BookmarkInterval <= 0 {
+		return mkapi.DefaultWatchBookmarkInterval
+	}
+	return s.args.WatchConfig.BookmarkInterval
+}
+
+// NewBookmarkEvent returns a watch.Bookmark event for the given object GVK at the given resource version. If
+// initialEventsEnd is true, the bookmark is annotated to mark the end of the initial events of a watch.
+func NewBookmarkEvent(gvk schema.GroupVersionKind, version int64, initialEventsEnd bool) watch.Event {
+	var obj runtime.Object
+	if typeinfo.SupportedScheme.Recognizes(gvk) {
+		obj, _ = typeinfo.SupportedScheme.New(gvk)
+	} else {
+		obj = &unstructured.Unstructured{}
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	mo, _ := meta.Accessor(obj)
+	mo.SetResourceVersion(strconv.FormatInt(version, 10))
+	if initialEventsEnd {
+		mo.SetAnnotations(map[string]string{metav1.InitialEventsAnnotationKey: "true"})
+	}
+	return watch.Event{Type: watch.Bookmark, Object: obj}
+}
+
 func (s *InMemResourceStore) CurrentResourceVersion() int64 {
 	return s.versionCounter.Load()
 }
 
 func (s *InMemResourceStore) Close() error {
-	if s.broadcaster != nil {
-		s.log.V(4).Info("shutting down broadcaster for store", "gvk", s.args.ObjectGVK)
-		s.broadcaster.Shutdown()
-	}
+	s.log.V(4).Info("terminating watchers of store", "gvk", s.args.ObjectGVK)
+	s.terminateWatchers()
 	return nil
 }
 
@@ -390,8 +816,10 @@ func (s *InMemResourceStore) nextResourceVersion() int64 {
 	return s.versionCounter.Add(1)
 }
 
-func WrapMetaObjectsIntoRuntimeListObject(resourceVersion int64, objectGVK schema.GroupVersionKind, objectListGVK schema.GroupVersionKind, items []metav1.Object) (listObj runtime.Object, err error) {
-	resourceVersionStr := strconv.FormatInt(resourceVersion, 10)
+func WrapMetaObjectsIntoRuntimeListObject(listMeta metav1.ListMeta, objectGVK schema.GroupVersionKind, objectListGVK schema.GroupVersionKind, items []metav1.Object) (listObj runtime.Object, err error) {
+	if !typeinfo.SupportedScheme.Recognizes(objectGVK) {
+		return wrapIntoUnstructuredList(listMeta, objectListGVK, items)
+	}
 	typesMap := typeinfo.SupportedScheme.KnownTypes(objectGVK.GroupVersion())
 	listType, ok := typesMap[objectListGVK.Kind] // Ex: Get Go reflect.type for the PodList
 	if !ok {
@@ -411,9 +839,7 @@ func WrapMetaObjectsIntoRuntimeListObject(resourceVersion int64, objectGVK schem
 		Kind:       objectListGVK.Kind,
 		APIVersion: objectGVK.GroupVersion().String(),
 	}))
-	listMetaVal.Set(reflect.ValueOf(metav1.ListMeta{
-		ResourceVersion: resourceVersionStr,
-	}))
+	listMetaVal.Set(reflect.ValueOf(listMeta))
 	itemsField := listObjVal.FieldByName("Items") // // Ex: corev1.Pod
 	if !itemsField.IsValid() || !itemsField.CanSet() || itemsField.Kind() != reflect.Slice {
 		return nil, fmt.Errorf("list object type %T for kind %q does not have a settable slice field named Items", listObj, objectGVK.Kind)
@@ -454,32 +880,74 @@ func WrapMetaObjectsIntoRuntimeListObject(resourceVersion int64, objectGVK schem
 	return listObj, nil
 }
 
-func shouldSkipObject(log logr.Logger, obj runtime.Object, startVersion int64, namespace string, labelSelector labels.Selector) (skip bool, err error) {
-	o, err := meta.Accessor(obj)
-	if err != nil {
-		log.Error(err, "cannot access object metadata for obj", "obj", obj)
-		err = fmt.Errorf("cannot access object metadata for obj type %T: %w", obj, err)
-		return
+// wrapIntoUnstructuredList wraps the given items of a kind not registered in the typeinfo.SupportedScheme, such as a
+// custom resource, into an unstructured.UnstructuredList.
+func wrapIntoUnstructuredList(listMeta metav1.ListMeta, objectListGVK schema.GroupVersionKind, items []metav1.Object) (runtime.Object, error) {
+	list := &unstructured.UnstructuredList{
+		Object: map[string]any{},
+		Items:  make([]unstructured.Unstructured, 0, len(items)),
 	}
-	if namespace != "" && o.GetNamespace() != namespace {
-		skip = true
-		return
+	list.SetGroupVersionKind(objectListGVK)
+	list.SetResourceVersion(listMeta.ResourceVersion)
+	if listMeta.Continue != "" {
+		list.SetContinue(listMeta.Continue)
 	}
-	if !labelSelector.Matches(labels.Set(o.GetLabels())) {
-		skip = true
-		return
+	list.SetRemainingItemCount(listMeta.RemainingItemCount)
+	for _, item := range items {
+		u, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("type mismatch, list kind %q expects items of type *unstructured.Unstructured, but got %T", objectListGVK, item)
+		}
+		list.Items = append(list.Items, *u)
 	}
-	rv, err := ParseObjectResourceVersion(o)
+	return list, nil
+}
+
+// toWatchEvent converts the given history entry into the watch event to be sent to a watcher with the given criteria.
+// Like the kube-apiserver, a modification which makes an object start or stop matching the criteria is sent as a
+// watch.Added or watch.Deleted event respectively. ok is false if the entry is not relevant for the watcher.
+func (s *InMemResourceStore) toWatchEvent(e *historyEntry, c mkapi.MatchCriteria) (event watch.Event, ok bool, err error) {
+	curMatches, err := s.matchesObject(c, e.object)
 	if err != nil {
 		return
 	}
-	if rv <= startVersion {
-		skip = true
+	prevMatches := false
+	if e.eventType == watch.Modified && e.prevObject != nil {
+		prevMatches, err = s.matchesObject(c, e.prevObject)
+		if err != nil {
+			return
+		}
+	}
+	switch {
+	case e.eventType != watch.Modified:
+		event, ok = watch.Event{Type: e.eventType, Object: e.object}, curMatches
+	case curMatches && prevMatches:
+		event, ok = watch.Event{Type: watch.Modified, Object: e.object}, true
+	case curMatches:
+		event, ok = watch.Event{Type: watch.Added, Object: e.object}, true
+	case prevMatches:
+		prevObj := e.prevObject.DeepCopyObject()
+		mo, _ := AsMeta(prevObj)
+		mo.SetResourceVersion(strconv.FormatInt(e.resourceVersion, 10))
+		event, ok = watch.Event{Type: watch.Deleted, Object: prevObj}, true
 		return
 	}
+	if ok {
+		// the entry is shared by all watchers and the history, so each watcher is sent a copy of its object.
+		event.Object = event.Object.DeepCopyObject()
+	}
 	return
 }
 
+func (s *InMemResourceStore) matchesObject(c mkapi.MatchCriteria, obj runtime.Object) (bool, error) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		s.log.Error(err, "cannot access object metadata for obj", "obj", obj)
+		return false, fmt.Errorf("cannot access object metadata for obj type %T: %w", obj, err)
+	}
+	return s.matches(c, o), nil
+}
+
 func ParseObjectResourceVersion(obj metav1.Object) (resourceVersion int64, err error) {
 	resourceVersion, err = parseResourceVersion(obj.GetResourceVersion())
 	if err != nil {
@@ -8,8 +8,9 @@ import (
 	"context"
 	"fmt"
 	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
 	"github.com/gardener/scaling-advisor/common/testutil"
-	"reflect"
+	"slices"
 	"strconv"
 	"sync"
 	"testing"
@@ -17,17 +18,24 @@ import (
 
 	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
 
+	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
+var testPodUID = types.UID("a2f3f6c4-1b0e-4d8a-9c6e-3f2d1e0b9a87")
+
 var testPod = corev1.Pod{
 	ObjectMeta: metav1.ObjectMeta{
 		Name:            "bingo",
@@ -92,6 +100,10 @@ func TestUpdate(t *testing.T) {
 		ignoredFieldsForOutputComparison cmp.Option
 		retErr                           error
 		expectedNumberOfObjects          int
+		// resourceVersion is the resource version of the update. Defaults to the current resource version of the object.
+		resourceVersion string
+		// unconditional clears the resource version of the update.
+		unconditional bool
 	}{
 		"correct typeMeta": {
 			typeMeta:                         metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
@@ -111,6 +123,19 @@ func TestUpdate(t *testing.T) {
 			retErr:                           nil,
 			expectedNumberOfObjects:          2,
 		},
+		"stale resourceVersion": {
+			typeMeta:                metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			resourceVersion:         "100",
+			retErr:                  fmt.Errorf("the object has been modified"),
+			expectedNumberOfObjects: 1,
+		},
+		"empty resourceVersion": { // updates unconditionally
+			typeMeta:                         metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			unconditional:                    true,
+			ignoredFieldsForOutputComparison: cmpopts.IgnoreFields(corev1.Pod{}, "ResourceVersion"),
+			retErr:                           nil,
+			expectedNumberOfObjects:          1,
+		},
 	}
 
 	for name, tc := range tests {
@@ -131,8 +156,16 @@ func TestUpdate(t *testing.T) {
 			if tc.name != "" {
 				p.Name = tc.name
 			}
+			switch {
+			case tc.unconditional:
+				p.ResourceVersion = ""
+			case tc.resourceVersion != "":
+				p.ResourceVersion = tc.resourceVersion
+			default:
+				p.ResourceVersion = createdPod.ResourceVersion
+			}
 			obj1 := metav1.Object(p.DeepCopy())
-			if err := s.Update(obj1); err != nil {
+			if err := s.Update(obj1); err != nil || tc.retErr != nil {
 				assertNumberOfItems(t, s, tc.expectedNumberOfObjects)
 				testutil.AssertError(t, err, tc.retErr)
 				return
@@ -210,22 +243,219 @@ func TestDelete(t *testing.T) {
 
 			key := cache.NewObjectName(createdPod.Namespace, tc.name).String()
 			gotObj, _ := s.GetByKey(key)
-			if err := s.DeleteByKey(key); err != nil {
+			if err := s.DeleteByKey(key, mkapi.DeleteOptions{}); err != nil {
 				assertNumberOfItems(t, s, tc.expectedNumberOfObjects)
 				testutil.AssertError(t, err, tc.retErr)
 				return
 			}
 			assertNumberOfItems(t, s, tc.expectedNumberOfObjects)
 
+			// objects returned by the store are copies, which are not affected by the deletion.
 			mo, _ := AsMeta(gotObj)
-			if !reflect.DeepEqual(mo.GetDeletionTimestamp().Time, time.Time{}) { // FIXME
-				t.Errorf("Expected deletionTimestamp to be set for object that's successfully deleted, got: %v", mo.GetDeletionTimestamp())
+			if mo.GetDeletionTimestamp() != nil {
+				t.Errorf("Expected object read before deletion to be unaffected, got deletionTimestamp: %v", mo.GetDeletionTimestamp())
 				return
 			}
 		})
 	}
 }
 
+func TestObjectsAreCopied(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	t.Cleanup(func() { _ = s.Close() })
+	pod := testPod.DeepCopy()
+	pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+	pod.Labels = map[string]string{"app": "web"}
+	if err := s.Add(pod); err != nil {
+		t.Fatalf("Error adding object to store: %v", err)
+	}
+	if pod.ResourceVersion != "1" {
+		t.Errorf("Expected resource version of added object to be set to 1, got %q", pod.ResourceVersion)
+	}
+	pod.Labels["app"] = "added"
+
+	key := objutil.CacheName(pod).String()
+	obj, err := s.GetByKey(key)
+	if err != nil {
+		t.Fatalf("Error getting object from store: %v", err)
+	}
+	obj.(*corev1.Pod).Labels["app"] = "got"
+	metaObjs, _, err := s.ListMetaObjects(mkapi.MatchCriteria{})
+	if err != nil {
+		t.Fatalf("Error listing objects in store: %v", err)
+	}
+	metaObjs[0].SetLabels(map[string]string{"app": "listed"})
+
+	updatedPod := obj.(*corev1.Pod)
+	updatedPod.Labels["app"] = "db"
+	if err = s.Update(updatedPod); err != nil {
+		t.Fatalf("Error updating object in store: %v", err)
+	}
+	updatedPod.Labels["app"] = "updated"
+
+	obj, err = s.GetByKey(key)
+	if err != nil {
+		t.Fatalf("Error getting object from store: %v", err)
+	}
+	if got := obj.(*corev1.Pod).Labels["app"]; got != "db" {
+		t.Errorf("Expected stored object to only reflect its update, got label app=%q", got)
+	}
+}
+
+func TestDeleteWithFinalizers(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	t.Cleanup(func() { s.Close() })
+	pod := testPod.DeepCopy()
+	pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+	pod.Finalizers = []string{"example.com/a", "example.com/b"}
+	if err := s.Add(pod); err != nil {
+		t.Fatalf("failed to add pod: %v", err)
+	}
+	key := objutil.CacheName(pod)
+	if err := s.Delete(key, mkapi.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	assertNumberOfItems(t, s, 1)
+	obj, err := s.Get(key)
+	if err != nil {
+		t.Fatalf("expected pod with finalizers to remain, got %v", err)
+	}
+	marked := obj.(*corev1.Pod).DeepCopy()
+	if marked.DeletionTimestamp == nil {
+		t.Fatalf("expected deletionTimestamp to be set")
+	}
+	if pod.DeletionTimestamp != nil {
+		t.Errorf("expected object of caller not to be mutated")
+	}
+
+	marked.Finalizers = append(marked.Finalizers, "example.com/c")
+	if err = s.Update(marked); !apierrors.IsInvalid(err) {
+		t.Errorf("expected Invalid error when adding finalizer to object marked for deletion, got %v", err)
+	}
+	marked.Finalizers = []string{"example.com/a"}
+	marked.DeletionTimestamp = nil
+	if err = s.Update(marked); err != nil {
+		t.Fatalf("failed to remove finalizer: %v", err)
+	}
+	if marked.DeletionTimestamp == nil {
+		t.Errorf("expected deletionTimestamp to be preserved on update")
+	}
+	assertNumberOfItems(t, s, 1)
+	marked.Finalizers = nil
+	if err = s.Update(marked); err != nil {
+		t.Fatalf("failed to remove last finalizer: %v", err)
+	}
+	assertNumberOfItems(t, s, 0)
+}
+
+func TestDeletePreconditions(t *testing.T) {
+	staleVersion := "999"
+	otherUID := types.UID("other")
+	tests := map[string]struct {
+		preconditions metav1.Preconditions
+		wantConflict  bool
+	}{
+		"matching uid": {
+			preconditions: metav1.Preconditions{UID: &testPodUID},
+		},
+		"mismatching uid": {
+			preconditions: metav1.Preconditions{UID: &otherUID},
+			wantConflict:  true,
+		},
+		"mismatching resource version": {
+			preconditions: metav1.Preconditions{ResourceVersion: &staleVersion},
+			wantConflict:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := createStoreForTesting(typeinfo.PodsDescriptor)
+			t.Cleanup(func() { s.Close() })
+			pod := testPod.DeepCopy()
+			pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+			pod.UID = testPodUID
+			if err := s.Add(pod); err != nil {
+				t.Fatalf("failed to add pod: %v", err)
+			}
+			err := s.Delete(objutil.CacheName(pod), mkapi.DeleteOptions{Preconditions: tc.preconditions})
+			if tc.wantConflict {
+				if !apierrors.IsConflict(err) {
+					t.Errorf("expected Conflict error, got %v", err)
+				}
+				assertNumberOfItems(t, s, 1)
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to delete pod: %v", err)
+			}
+			assertNumberOfItems(t, s, 0)
+		})
+	}
+}
+
+func TestDeleteWithGracePeriod(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	fakeClock := testingclock.NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	s.args.Clock = fakeClock
+	t.Cleanup(func() { s.Close() })
+	pod := testPod.DeepCopy()
+	pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+	if err := s.Add(pod); err != nil {
+		t.Fatalf("failed to add pod: %v", err)
+	}
+	gracePeriodSeconds := int64(30)
+	if err := s.Delete(objutil.CacheName(pod), mkapi.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds}); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	obj, err := s.GetByKey(objutil.CacheName(pod).String())
+	if err != nil {
+		t.Fatalf("failed to get pod marked for deletion: %v", err)
+	}
+	wantDeletionTimestamp := fakeClock.Now().Add(30 * time.Second)
+	if got := obj.(*corev1.Pod).DeletionTimestamp; got == nil || !got.Time.Equal(wantDeletionTimestamp) {
+		t.Errorf("expected deletionTimestamp %v, got %v", wantDeletionTimestamp, got)
+	}
+	fakeClock.Step(29 * time.Second)
+	assertNumberOfItems(t, s, 1)
+	fakeClock.Step(time.Second)
+	assertNumberOfItems(t, s, 0)
+}
+
+func TestWatchTimeout(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	s.args.Clock = fakeClock
+	t.Cleanup(func() { _ = s.Close() })
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- s.Watch(context.Background(), mkapi.WatchOptions{}, func(watch.Event) error { return nil })
+	}()
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool { return stats.WatcherCount == 1 })
+	deadline := time.Now().Add(2 * time.Second)
+	for !fakeClock.HasWaiters() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected watch to wait on its timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fakeClock.Step(s.args.WatchConfig.Timeout - time.Millisecond)
+	select {
+	case err := <-watchErr:
+		t.Fatalf("expected watch not to end before its timeout, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	fakeClock.Step(time.Millisecond)
+	select {
+	case err := <-watchErr:
+		if err != nil {
+			t.Errorf("expected watch to time out without error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected watch to time out after the watch timeout elapsed on the clock")
+	}
+}
+
 func TestGetByKey(t *testing.T) {
 	tests := map[string]struct {
 		key                       string
@@ -287,6 +517,7 @@ func TestList(t *testing.T) {
 	tests := map[string]struct {
 		namespace               string
 		labelSelector           labels.Selector
+		fieldSelector           fields.Selector
 		retErr                  error
 		expectedNumberOfObjects int
 	}{
@@ -314,11 +545,39 @@ func TestList(t *testing.T) {
 			retErr:                  nil,
 			expectedNumberOfObjects: 0,
 		},
+		"metadata.name fieldSelector": {
+			namespace:               testPod.Namespace,
+			labelSelector:           labels.NewSelector(),
+			fieldSelector:           fields.OneTermEqualSelector("metadata.name", "bingo-1"),
+			retErr:                  nil,
+			expectedNumberOfObjects: 1,
+		},
+		"unassigned spec.nodeName fieldSelector": {
+			namespace:               testPod.Namespace,
+			labelSelector:           labels.NewSelector(),
+			fieldSelector:           fields.OneTermEqualSelector("spec.nodeName", ""),
+			retErr:                  nil,
+			expectedNumberOfObjects: 3,
+		},
+		"non-matching spec.nodeName fieldSelector": {
+			namespace:               testPod.Namespace,
+			labelSelector:           labels.NewSelector(),
+			fieldSelector:           fields.OneTermEqualSelector("spec.nodeName", "node-a"),
+			retErr:                  nil,
+			expectedNumberOfObjects: 0,
+		},
+		"label and field selectors combined": {
+			namespace:               testPod.Namespace,
+			labelSelector:           labels.SelectorFromSet(labels.Set{"k1": "v1"}),
+			fieldSelector:           fields.OneTermNotEqualSelector("metadata.name", "bingo-2"),
+			retErr:                  nil,
+			expectedNumberOfObjects: 1,
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			c := mkapi.MatchCriteria{Namespace: tc.namespace, LabelSelector: tc.labelSelector}
+			c := mkapi.MatchCriteria{Namespace: tc.namespace, LabelSelector: tc.labelSelector, FieldSelector: tc.fieldSelector}
 			objList, err := s.List(c)
 			if err != nil {
 				testutil.AssertError(t, err, tc.retErr)
@@ -341,6 +600,119 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListChunks(t *testing.T) {
+	tests := map[string]struct {
+		limit             int64
+		historySize       int
+		modifyBetween     bool
+		expectedChunks    [][]string
+		expectedRemaining []int64
+		retErr            error
+	}{
+		"no limit": {
+			limit:             0,
+			expectedChunks:    [][]string{{"bingo-0", "bingo-1", "bingo-2"}},
+			expectedRemaining: []int64{0},
+		},
+		"limit larger than number of objects": {
+			limit:             5,
+			expectedChunks:    [][]string{{"bingo-0", "bingo-1", "bingo-2"}},
+			expectedRemaining: []int64{0},
+		},
+		"limit smaller than number of objects": {
+			limit:             2,
+			expectedChunks:    [][]string{{"bingo-0", "bingo-1"}, {"bingo-2"}},
+			expectedRemaining: []int64{1, 0},
+		},
+		"limit of one": {
+			limit:             1,
+			expectedChunks:    [][]string{{"bingo-0"}, {"bingo-1"}, {"bingo-2"}},
+			expectedRemaining: []int64{2, 1, 0},
+		},
+		"store modified between chunks": {
+			limit:             2,
+			modifyBetween:     true,
+			expectedChunks:    [][]string{{"bingo-0", "bingo-1"}, {"bingo-2"}},
+			expectedRemaining: []int64{1, 0},
+		},
+		"history compacted between chunks": {
+			limit:             2,
+			historySize:       1,
+			modifyBetween:     true,
+			expectedChunks:    [][]string{{"bingo-0", "bingo-1"}},
+			expectedRemaining: []int64{1},
+			retErr:            apierrors.NewResourceExpired("the provided continue parameter is too old"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := createStoreForTesting(typeinfo.PodsDescriptor)
+			s.args.WatchConfig.HistorySize = tc.historySize
+			s.Reset()
+			createdPods, _ := createPodsForTesting(t, s)
+			listVersion := strconv.FormatInt(s.CurrentResourceVersion(), 10)
+
+			c := mkapi.MatchCriteria{Namespace: testPod.Namespace, Limit: tc.limit}
+			var gotChunks [][]string
+			var gotRemaining []int64
+			var gotErr error
+			for {
+				objList, err := s.List(c)
+				if err != nil {
+					gotErr = err
+					break
+				}
+				podList := objList.(*corev1.PodList)
+				if podList.ResourceVersion != listVersion {
+					t.Errorf("Expected list resourceVersion %q, got %q", listVersion, podList.ResourceVersion)
+				}
+				var names []string
+				for _, p := range podList.Items {
+					names = append(names, p.Name)
+				}
+				gotChunks = append(gotChunks, names)
+				var remaining int64
+				if podList.RemainingItemCount != nil {
+					remaining = *podList.RemainingItemCount
+				}
+				gotRemaining = append(gotRemaining, remaining)
+				if podList.Continue == "" {
+					break
+				}
+				if tc.modifyBetween && c.Continue == "" {
+					if err := s.Delete(objutil.CacheName(&createdPods[2]), mkapi.DeleteOptions{}); err != nil {
+						t.Fatalf("Error deleting object from store: %v", err)
+					}
+					addedPod := createdPods[0].DeepCopy()
+					addedPod.Name, addedPod.UID, addedPod.ResourceVersion = "bingo-10", "", ""
+					if err := s.Add(addedPod); err != nil {
+						t.Fatalf("Error adding object to store: %v", err)
+					}
+				}
+				c.Continue = podList.Continue
+			}
+			testutil.AssertError(t, gotErr, tc.retErr)
+			if diff := cmp.Diff(tc.expectedChunks, gotChunks); diff != "" {
+				t.Errorf("List chunks mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedRemaining, gotRemaining); diff != "" {
+				t.Errorf("List remaining item counts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListInvalidContinue(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	_, _ = createPodsForTesting(t, s)
+
+	_, err := s.List(mkapi.MatchCriteria{Limit: 1, Continue: "not-a-token"})
+	if !apierrors.IsBadRequest(err) {
+		t.Errorf("Expected bad request error for invalid continue token, got %v", err)
+	}
+}
+
 func TestBuildPendingWatchEvents(t *testing.T) {
 	s := createStoreForTesting(typeinfo.PodsDescriptor)
 	_, _ = createPodsForTesting(t, s)
@@ -405,7 +777,11 @@ func TestBuildPendingWatchEvents(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			watchEvents, err := s.buildPendingWatchEvents(tc.startVersion, tc.namespace, tc.labelSelector)
+			opts := mkapi.WatchOptions{
+				MatchCriteria: mkapi.MatchCriteria{Namespace: tc.namespace, LabelSelector: tc.labelSelector},
+				StartVersion:  tc.startVersion,
+			}
+			watchEvents, err := s.buildPendingWatchEvents(opts)
 			if err != nil {
 				testutil.AssertError(t, err, tc.retErr)
 			}
@@ -522,7 +898,11 @@ func TestWatch(t *testing.T) {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				watchErr = s.Watch(ctx, tc.startVersion, tc.namespace, tc.labelSelector, eventCallback)
+				opts := mkapi.WatchOptions{
+					MatchCriteria: mkapi.MatchCriteria{Namespace: tc.namespace, LabelSelector: tc.labelSelector},
+					StartVersion:  tc.startVersion,
+				}
+				watchErr = s.Watch(ctx, opts, eventCallback)
 			}()
 
 			if tc.modifyObjectAfterWatch {
@@ -560,17 +940,364 @@ func TestWatch(t *testing.T) {
 	}
 }
 
+func TestWatchFromHistory(t *testing.T) {
+	tests := map[string]struct {
+		historySize    int
+		labelSelector  labels.Selector
+		startVersion   int64
+		expectedEvents []string
+		retErr         error
+	}{
+		"replay changes after start version": {
+			startVersion:   3,
+			expectedEvents: []string{"MODIFIED bingo-0 4", "DELETED bingo-1 5", "ADDED bingo-3 6"},
+		},
+		"replay all changes": {
+			startVersion:   1,
+			expectedEvents: []string{"ADDED bingo-1 2", "ADDED bingo-2 3", "MODIFIED bingo-0 4", "DELETED bingo-1 5", "ADDED bingo-3 6"},
+		},
+		"replay to selector with object leaving selection": {
+			labelSelector:  labels.SelectorFromSet(labels.Set{"k0": "v0"}),
+			startVersion:   3,
+			expectedEvents: []string{"DELETED bingo-0 4", "DELETED bingo-1 5"},
+		},
+		"replay to selector with object entering selection": {
+			labelSelector:  labels.SelectorFromSet(labels.Set{"modified": "true"}),
+			startVersion:   3,
+			expectedEvents: []string{"ADDED bingo-0 4"},
+		},
+		"start version within bounded history": {
+			historySize:    3,
+			startVersion:   3,
+			expectedEvents: []string{"MODIFIED bingo-0 4", "DELETED bingo-1 5", "ADDED bingo-3 6"},
+		},
+		"start version older than bounded history": {
+			historySize:  3,
+			startVersion: 2,
+			retErr:       apierrors.NewResourceExpired("too old resource version: 2 (3)"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := createStoreForTesting(typeinfo.PodsDescriptor)
+			s.args.WatchConfig.HistorySize = tc.historySize
+			s.Reset()
+			t.Cleanup(func() { _ = s.Close() })
+			createdPods, _ := createPodsForTesting(t, s)
+
+			modifiedPod := createdPods[0].DeepCopy()
+			modifiedPod.Labels = map[string]string{"modified": "true"}
+			if err := s.Update(modifiedPod); err != nil {
+				t.Fatalf("Error updating object in store: %v", err)
+			}
+			if err := s.Delete(objutil.CacheName(&createdPods[1]), mkapi.DeleteOptions{}); err != nil {
+				t.Fatalf("Error deleting object from store: %v", err)
+			}
+			newPod := testPod.DeepCopy()
+			newPod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+			newPod.Name = "bingo-3"
+			if err := s.Add(newPod); err != nil {
+				t.Fatalf("Error adding object to store: %v", err)
+			}
+
+			var gotEvents []string
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			opts := mkapi.WatchOptions{
+				MatchCriteria: mkapi.MatchCriteria{Namespace: testPod.Namespace, LabelSelector: tc.labelSelector},
+				StartVersion:  tc.startVersion,
+			}
+			err := s.Watch(ctx, opts, func(event watch.Event) error {
+				mo, err := AsMeta(event.Object)
+				if err != nil {
+					return err
+				}
+				gotEvents = append(gotEvents, fmt.Sprintf("%s %s %s", event.Type, mo.GetName(), mo.GetResourceVersion()))
+				return nil
+			})
+			testutil.AssertError(t, err, tc.retErr)
+			if tc.retErr != nil && !apierrors.IsResourceExpired(err) {
+				t.Errorf("Expected resource expired error, got %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedEvents, gotEvents); diff != "" {
+				t.Errorf("Watch events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWatchAfterReset(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	t.Cleanup(func() { _ = s.Close() })
+	_, _ = createPodsForTesting(t, s)
+	s.Reset()
+
+	err := s.Watch(context.Background(), mkapi.WatchOptions{StartVersion: 1}, func(watch.Event) error { return nil })
+	if !apierrors.IsResourceExpired(err) {
+		t.Errorf("Expected resource expired error for watch from version prior to reset, got %v", err)
+	}
+}
+
+func TestWatchInitialEventsAndBookmarks(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	s.args.WatchConfig.BookmarkInterval = 50 * time.Millisecond
+	t.Cleanup(func() { _ = s.Close() })
+	createdPods, _ := createPodsForTesting(t, s)
+
+	var (
+		eventsMu            sync.Mutex
+		gotEvents           []string
+		lastBookmarkVersion string
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	watchStarted := make(chan struct{})
+	var watchErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		opts := mkapi.WatchOptions{
+			MatchCriteria:     mkapi.MatchCriteria{Namespace: testPod.Namespace},
+			StartVersion:      2,
+			SendInitialEvents: true,
+			AllowBookmarks:    true,
+		}
+		watchErr = s.Watch(ctx, opts, func(event watch.Event) error {
+			mo, err := AsMeta(event.Object)
+			if err != nil {
+				return err
+			}
+			eventsMu.Lock()
+			defer eventsMu.Unlock()
+			if event.Type == watch.Bookmark {
+				if mo.GetResourceVersion() == lastBookmarkVersion {
+					return nil // skip repeated periodic bookmarks
+				}
+				lastBookmarkVersion = mo.GetResourceVersion()
+				if mo.GetAnnotations()[metav1.InitialEventsAnnotationKey] == "true" {
+					gotEvents = append(gotEvents, "BOOKMARK "+mo.GetResourceVersion()+" initial-events-end")
+					close(watchStarted)
+					return nil
+				}
+				gotEvents = append(gotEvents, "BOOKMARK "+mo.GetResourceVersion())
+				return nil
+			}
+			gotEvents = append(gotEvents, fmt.Sprintf("%s %s %s", event.Type, mo.GetName(), mo.GetResourceVersion()))
+			return nil
+		})
+	}()
+
+	<-watchStarted
+	modifiedPod := createdPods[1].DeepCopy()
+	modifiedPod.Labels["modified"] = "true"
+	if err := s.Update(modifiedPod); err != nil {
+		t.Fatalf("Error updating object in store: %v", err)
+	}
+	wg.Wait()
+	if watchErr != nil {
+		t.Fatalf("Unexpected watch error: %v", watchErr)
+	}
+
+	// initial events are sent for all objects regardless of the start version.
+	wantEvents := []string{
+		"ADDED bingo-0 1", "ADDED bingo-1 2", "ADDED bingo-2 3",
+		"BOOKMARK 3 initial-events-end",
+		"MODIFIED bingo-1 4",
+		"BOOKMARK 4",
+	}
+	slices.Sort(gotEvents[:3])
+	if diff := cmp.Diff(wantEvents, gotEvents); diff != "" {
+		t.Errorf("Watch events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	t.Cleanup(func() { _ = s.Close() })
+	createdPods, _ := createPodsForTesting(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// the watcher blocks on the first change so that subsequent changes are queued.
+		_ = s.Watch(ctx, mkapi.WatchOptions{StartVersion: s.CurrentResourceVersion()}, func(watch.Event) error {
+			<-release
+			return nil
+		})
+	}()
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool { return stats.WatcherCount == 1 })
+	for i := range 3 {
+		modifiedPod := createdPods[0].DeepCopy()
+		modifiedPod.Labels["modified"] = strconv.Itoa(i)
+		modifiedPod.ResourceVersion = ""
+		if err := s.Update(modifiedPod); err != nil {
+			t.Fatalf("Error updating object in store: %v", err)
+		}
+	}
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool {
+		return stats.ObjectCount == len(createdPods) && stats.WatcherCount == 1 && stats.MaxWatchQueueDepth == 2
+	})
+	close(release)
+	cancel()
+	wg.Wait()
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool { return stats.WatcherCount == 0 })
+}
+
+func TestWatchTerminatesLaggingWatcher(t *testing.T) {
+	s := createStoreForTesting(typeinfo.PodsDescriptor)
+	s.args.WatchConfig.QueueSize = 2
+	t.Cleanup(func() { _ = s.Close() })
+	createdPods, _ := createPodsForTesting(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	startVersion := s.CurrentResourceVersion()
+	slowBlocked, release := make(chan struct{}), make(chan struct{})
+	healthyEvents := make(chan string)
+	var slowErr, healthyErr error
+	slowDone := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer close(slowDone)
+		// the slow watcher blocks on the first change so that subsequent changes overflow its queue.
+		var once sync.Once
+		slowErr = s.Watch(ctx, mkapi.WatchOptions{StartVersion: startVersion}, func(watch.Event) error {
+			once.Do(func() { close(slowBlocked) })
+			<-release
+			return nil
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		healthyErr = s.Watch(ctx, mkapi.WatchOptions{StartVersion: startVersion}, func(event watch.Event) error {
+			mo, err := AsMeta(event.Object)
+			if err != nil {
+				return err
+			}
+			healthyEvents <- fmt.Sprintf("%s %s %s", event.Type, mo.GetName(), mo.GetResourceVersion())
+			return nil
+		})
+	}()
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool { return stats.WatcherCount == 2 })
+
+	var gotEvents, wantEvents []string
+	for i := range 5 {
+		modifiedPod := createdPods[0].DeepCopy()
+		modifiedPod.Labels["modified"] = strconv.Itoa(i)
+		modifiedPod.ResourceVersion = ""
+		if err := s.Update(modifiedPod); err != nil {
+			t.Fatalf("Error updating object in store: %v", err)
+		}
+		wantEvents = append(wantEvents, fmt.Sprintf("MODIFIED bingo-0 %d", startVersion+int64(i)+1))
+		select {
+		case e := <-healthyEvents:
+			gotEvents = append(gotEvents, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for event %d of healthy watcher", i)
+		}
+		if i == 0 {
+			<-slowBlocked
+		}
+	}
+	waitForStats(t, s, func(stats mkapi.ResourceStoreStats) bool {
+		return stats.WatcherCount == 1 && stats.DroppedWatcherCount == 1
+	})
+	close(release)
+	<-slowDone
+	cancel()
+	wg.Wait()
+
+	if !apierrors.IsResourceExpired(slowErr) {
+		t.Errorf("Expected resource expired error for lagging watcher, got %v", slowErr)
+	}
+	if healthyErr != nil {
+		t.Errorf("Unexpected error for healthy watcher: %v", healthyErr)
+	}
+	if diff := cmp.Diff(wantEvents, gotEvents); diff != "" {
+		t.Errorf("Healthy watcher events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func waitForStats(t *testing.T, s *InMemResourceStore, condition func(mkapi.ResourceStoreStats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition(s.GetStats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Unexpected store stats: %+v", s.GetStats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDeleteObjects(t *testing.T) {
+	gracePeriodSeconds := int64(30)
+	tests := map[string]struct {
+		opts          mkapi.DeleteOptions
+		wantRemaining int
+		wantEventType watch.EventType
+	}{
+		"immediate deletion": {
+			wantRemaining: 1,
+			wantEventType: watch.Deleted,
+		},
+		"graceful deletion": {
+			opts:          mkapi.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds},
+			wantRemaining: 3,
+			wantEventType: watch.Modified,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := createStoreForTesting(typeinfo.PodsDescriptor)
+			t.Cleanup(func() { s.Close() })
+			if _, err := createPodsForTesting(t, s); err != nil {
+				t.Fatalf("failed to create pods: %v", err)
+			}
+			startVersion := s.CurrentResourceVersion()
+			c := mkapi.MatchCriteria{Namespace: testPod.Namespace, LabelSelector: labels.SelectorFromSet(labels.Set{"k1": "v1"})}
+			delObjs, err := s.DeleteObjects(c, tc.opts)
+			if err != nil {
+				t.Fatalf("failed to delete pods: %v", err)
+			}
+			if len(delObjs) != 2 {
+				t.Errorf("expected 2 deleted pods, got %d", len(delObjs))
+			}
+			assertNumberOfItems(t, s, tc.wantRemaining)
+			watchEvents, err := s.buildPendingWatchEvents(mkapi.WatchOptions{StartVersion: startVersion})
+			if err != nil {
+				t.Fatalf("failed to build watch events: %v", err)
+			}
+			if len(watchEvents) != len(delObjs) {
+				t.Errorf("expected a watch event per deleted pod, got %d", len(watchEvents))
+			}
+			for _, ev := range watchEvents {
+				if ev.Type != tc.wantEventType {
+					t.Errorf("expected watch event of type %s, got %s", tc.wantEventType, ev.Type)
+				}
+			}
+		})
+	}
+}
+
 func createStoreForTesting(d typeinfo.Descriptor) *InMemResourceStore {
 	queueSize := 100
 	watchTimeout := 2 * time.Second
 	log := klog.NewKlogr().V(4)
 
 	return NewInMemResourceStore(log, &mkapi.ResourceStoreArgs{
-		Name:          d.GVR.Resource,
-		ObjectGVK:     d.GVK,
-		ObjectListGVK: d.ListGVK,
-		Scheme:        typeinfo.SupportedScheme,
-		WatchConfig:   mkapi.WatchConfig{QueueSize: queueSize, Timeout: watchTimeout},
+		Name:             d.GVR.Resource,
+		ObjectGVK:        d.GVK,
+		ObjectListGVK:    d.ListGVK,
+		Scheme:           typeinfo.SupportedScheme,
+		WatchConfig:      mkapi.WatchConfig{QueueSize: queueSize, Timeout: watchTimeout},
+		SelectableFields: d.SelectableFields,
+		Indexes:          d.IndexesWithLabelKeys("k1"),
 	})
 }
 
@@ -604,3 +1331,211 @@ func assertNumberOfItems(t *testing.T, s *InMemResourceStore, want int) {
 		t.Logf("Expected number of items, got: %v", got)
 	}
 }
+
+func TestListWithIndexes(t *testing.T) {
+	s := NewInMemResourceStore(klog.NewKlogr().V(4), &mkapi.ResourceStoreArgs{
+		Name:             typeinfo.PodsDescriptor.GVR.Resource,
+		ObjectGVK:        typeinfo.PodsDescriptor.GVK,
+		ObjectListGVK:    typeinfo.PodsDescriptor.ListGVK,
+		Scheme:           typeinfo.SupportedScheme,
+		SelectableFields: typeinfo.PodsDescriptor.SelectableFields,
+		Indexes:          typeinfo.PodsDescriptor.IndexesWithLabelKeys("app"),
+	})
+	t.Cleanup(func() { _ = s.Close() })
+	for _, ns := range []string{"a", "b"} {
+		for _, node := range []string{"n1", "n2"} {
+			for _, app := range []string{"web", "db"} {
+				if err := s.Add(newIndexTestPod(ns, app+"-"+node, node, app)); err != nil {
+					t.Fatalf("Error adding pod to store: %v", err)
+				}
+			}
+		}
+	}
+	if err := s.Add(newIndexTestPod("c", "web-n3", "n3", "web")); err != nil {
+		t.Fatalf("Error adding pod to store: %v", err)
+	}
+
+	tests := map[string]struct {
+		criteria       mkapi.MatchCriteria
+		wantNames      []string
+		wantCandidates int
+	}{
+		"no criteria": {
+			wantNames:      []string{"a/db-n1", "a/db-n2", "a/web-n1", "a/web-n2", "b/db-n1", "b/db-n2", "b/web-n1", "b/web-n2", "c/web-n3"},
+			wantCandidates: 9,
+		},
+		"namespace": {
+			criteria:       mkapi.MatchCriteria{Namespace: "a"},
+			wantNames:      []string{"a/db-n1", "a/db-n2", "a/web-n1", "a/web-n2"},
+			wantCandidates: 4,
+		},
+		"spec.nodeName": {
+			criteria:       mkapi.MatchCriteria{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", "n3")},
+			wantNames:      []string{"c/web-n3"},
+			wantCandidates: 1,
+		},
+		"negated spec.nodeName": {
+			criteria:       mkapi.MatchCriteria{Namespace: "b", FieldSelector: fields.OneTermNotEqualSelector("spec.nodeName", "n1")},
+			wantNames:      []string{"b/db-n2", "b/web-n2"},
+			wantCandidates: 4,
+		},
+		"indexed label": {
+			criteria:       mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "db"})},
+			wantNames:      []string{"a/db-n1", "a/db-n2", "b/db-n1", "b/db-n2"},
+			wantCandidates: 4,
+		},
+		"indexed label with set of values": {
+			criteria:       mkapi.MatchCriteria{LabelSelector: mustParseSelector(t, "app in (db,cache)")},
+			wantNames:      []string{"a/db-n1", "a/db-n2", "b/db-n1", "b/db-n2"},
+			wantCandidates: 4,
+		},
+		"indexed label without objects": {
+			criteria:       mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "cache"})},
+			wantCandidates: 0,
+		},
+		"unindexed label": {
+			criteria:       mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{"tier": "frontend"})},
+			wantCandidates: 9,
+		},
+		"most selective index": {
+			criteria: mkapi.MatchCriteria{
+				Namespace:     "c",
+				LabelSelector: labels.SelectorFromSet(labels.Set{"app": "web"}),
+				FieldSelector: fields.OneTermEqualSelector("spec.nodeName", "n3"),
+			},
+			wantNames:      []string{"c/web-n3"},
+			wantCandidates: 1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			candidates, err := s.candidates(tc.criteria)
+			if err != nil {
+				t.Fatalf("Unexpected error getting candidates: %v", err)
+			}
+			if len(candidates) != tc.wantCandidates {
+				t.Errorf("Expected %d candidates, got %d", tc.wantCandidates, len(candidates))
+			}
+			metaObjs, _, err := s.ListMetaObjects(tc.criteria)
+			if err != nil {
+				t.Fatalf("Unexpected error listing objects: %v", err)
+			}
+			var gotNames []string
+			for _, mo := range metaObjs {
+				gotNames = append(gotNames, objutil.CacheName(mo).String())
+			}
+			slices.Sort(gotNames)
+			if diff := cmp.Diff(tc.wantNames, gotNames); diff != "" {
+				t.Errorf("Listed objects mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// BenchmarkListMetaObjects lists the objects of stores holding 100k pods and 10k nodes, which is the scale of large
+// clusters, with and without secondary indexes.
+func BenchmarkListMetaObjects(b *testing.B) {
+	const (
+		numNodes       = 10_000
+		numPods        = 100_000
+		numNamespaces  = 100
+		numApps        = 1_000
+		numZones       = 10
+		zoneLabelKey   = "topology.kubernetes.io/zone"
+		appLabelKey    = "app"
+		benchNamespace = "ns-42"
+	)
+	newStore := func(b *testing.B, d typeinfo.Descriptor, indexes mkapi.IndexConfig, objs []metav1.Object) *InMemResourceStore {
+		b.Helper()
+		s := NewInMemResourceStore(logr.Discard(), &mkapi.ResourceStoreArgs{
+			Name:             d.GVR.Resource,
+			ObjectGVK:        d.GVK,
+			ObjectListGVK:    d.ListGVK,
+			Scheme:           typeinfo.SupportedScheme,
+			SelectableFields: d.SelectableFields,
+			Indexes:          indexes,
+		})
+		for _, o := range objs {
+			if err := s.Add(o); err != nil {
+				b.Fatal(err)
+			}
+		}
+		return s
+	}
+	pods := make([]metav1.Object, 0, numPods)
+	for i := range numPods {
+		pods = append(pods, newIndexTestPod(fmt.Sprintf("ns-%d", i%numNamespaces), fmt.Sprintf("pod-%d", i), fmt.Sprintf("node-%d", i%numNodes), fmt.Sprintf("app-%d", i%numApps)))
+	}
+	nodes := make([]metav1.Object, 0, numNodes)
+	for i := range numNodes {
+		nodes = append(nodes, &corev1.Node{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i), Labels: map[string]string{zoneLabelKey: fmt.Sprintf("zone-%d", i%numZones)}},
+		})
+	}
+
+	queries := []struct {
+		name     string
+		d        typeinfo.Descriptor
+		criteria mkapi.MatchCriteria
+	}{
+		{
+			name:     "pods by namespace",
+			d:        typeinfo.PodsDescriptor,
+			criteria: mkapi.MatchCriteria{Namespace: benchNamespace},
+		},
+		{
+			name:     "pods by spec.nodeName",
+			d:        typeinfo.PodsDescriptor,
+			criteria: mkapi.MatchCriteria{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", "node-42")},
+		},
+		{
+			name:     "pods by label",
+			d:        typeinfo.PodsDescriptor,
+			criteria: mkapi.MatchCriteria{Namespace: benchNamespace, LabelSelector: labels.SelectorFromSet(labels.Set{appLabelKey: "app-42"})},
+		},
+		{
+			name:     "nodes by label",
+			d:        typeinfo.NodesDescriptor,
+			criteria: mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{zoneLabelKey: "zone-4"})},
+		},
+	}
+	for _, indexed := range []bool{false, true} {
+		podIndexes, nodeIndexes := mkapi.IndexConfig{}, mkapi.IndexConfig{}
+		if indexed {
+			podIndexes = typeinfo.PodsDescriptor.IndexesWithLabelKeys(appLabelKey)
+			nodeIndexes = typeinfo.NodesDescriptor.IndexesWithLabelKeys(zoneLabelKey)
+		}
+		stores := map[schema.GroupVersionKind]*InMemResourceStore{
+			typeinfo.PodsDescriptor.GVK:  newStore(b, typeinfo.PodsDescriptor, podIndexes, pods),
+			typeinfo.NodesDescriptor.GVK: newStore(b, typeinfo.NodesDescriptor, nodeIndexes, nodes),
+		}
+		for _, q := range queries {
+			b.Run(fmt.Sprintf("%s/indexed=%t", q.name, indexed), func(b *testing.B) {
+				s := stores[q.d.GVK]
+				for b.Loop() {
+					if _, _, err := s.ListMetaObjects(q.criteria); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func newIndexTestPod(namespace, name, nodeName, app string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func mustParseSelector(t *testing.T, selector string) labels.Selector {
+	t.Helper()
+	s, err := labels.Parse(selector)
+	if err != nil {
+		t.Fatalf("Error parsing label selector %q: %v", selector, err)
+	}
+	return s
+}
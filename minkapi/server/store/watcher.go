@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// storeWatcher receives the changes made to a store through a buffer bounded by the watch queue size of the store.
+// Changes are offered to all watchers without blocking, so that a watcher whose buffer is full cannot stall the store
+// or its other watchers. Such a lagging watcher is terminated with a 410 Expired error instead, upon which reflectors
+// relist. Since the changes are offered in the order of their resource versions, every watcher that is not terminated
+// receives all of them in order.
+type storeWatcher struct {
+	changes chan *historyEntry
+	// done is closed once the watcher is terminated, after which err holds the reason of its termination, if any.
+	done chan struct{}
+	err  error
+}
+
+func newStoreWatcher(queueSize int) *storeWatcher {
+	return &storeWatcher{
+		changes: make(chan *historyEntry, queueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// offer adds the given change to the buffer of the watcher and reports whether there was room for it.
+func (w *storeWatcher) offer(e *historyEntry) bool {
+	select {
+	case w.changes <- e:
+		return true
+	default:
+		return false
+	}
+}
+
+// terminate ends the watcher for the given reason, or without error if nil. Must be called at most once.
+func (w *storeWatcher) terminate(reason error) {
+	w.err = reason
+	close(w.done)
+}
+
+// addWatcher registers a watcher for the changes made to the store. Must be called with s.mu held, so that the
+// watcher receives exactly the changes made after its pending events have been computed.
+func (s *InMemResourceStore) addWatcher() *storeWatcher {
+	w := newStoreWatcher(s.watchQueueSize())
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	s.watchers[w] = struct{}{}
+	return w
+}
+
+// removeWatcher unregisters the given watcher once its watch has ended.
+func (s *InMemResourceStore) removeWatcher(w *storeWatcher) {
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	delete(s.watchers, w)
+}
+
+// broadcast offers the given change to all watchers of the store, terminating those that lag behind by more than
+// the watch queue size. Must be called with s.mu held.
+func (s *InMemResourceStore) broadcast(e *historyEntry) {
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	for w := range s.watchers {
+		if w.offer(e) {
+			continue
+		}
+		delete(s.watchers, w)
+		s.droppedWatchers.Add(1)
+		s.log.Info("terminating watcher lagging behind", "kind", s.args.ObjectGVK.Kind, "resourceVersion", e.resourceVersion, "watchQueueSize", cap(w.changes))
+		w.terminate(apierrors.NewResourceExpired(fmt.Sprintf("watch of %s terminated since it lagged behind by more than %d events", s.groupResource(), cap(w.changes))))
+	}
+}
+
+// terminateWatchers ends all watchers of the store without error.
+func (s *InMemResourceStore) terminateWatchers() {
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+	for w := range s.watchers {
+		delete(s.watchers, w)
+		w.terminate(nil)
+	}
+}
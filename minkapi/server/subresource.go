@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+)
+
+// handlePutStatus replaces the status of an object of a kind with a status subresource. Like in the kube-apiserver,
+// changes to other fields than the status are ignored. The update is conditional on the resource version of the request
+// body if it is set. Otherwise, it is unconditional and re-applied to the latest object on conflicts with concurrent
+// writers.
+func handlePutStatus(d typeinfo.Descriptor, v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := GetObjectName(r, d)
+		body, err := d.CreateObject()
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		if !readBodyIntoObj(w, r, body) {
+			return
+		}
+		var (
+			prevObj, obj   runtime.Object
+			preconditioned bool
+		)
+		err = retry.OnError(retry.DefaultRetry, func(err error) bool {
+			return apierrors.IsConflict(err) && !preconditioned
+		}, func() (err error) {
+			if prevObj, err = v.GetObject(d.GVK, name); err != nil {
+				return err
+			}
+			obj = prevObj.DeepCopyObject()
+			if err = copyStatus(body.(runtime.Object), obj); err != nil {
+				return apierrors.NewInternalError(err)
+			}
+			if err = recordUpdate(r, d, "status", prevObj, obj); err != nil {
+				return err
+			}
+			mo := obj.(metav1.Object)
+			if body.GetResourceVersion() != "" {
+				preconditioned = body.GetResourceVersion() != mo.GetResourceVersion()
+				mo.SetResourceVersion(body.GetResourceVersion())
+			}
+			return v.UpdateObject(d.GVK, mo)
+		})
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		auditMutation(r, v, mkapi.AuditVerbUpdate, d.GVK, prevObj, obj)
+		writeResponse(w, r, obj)
+	}
+}
+
+// copyStatus copies the status of the given src object to the given dst object of the same kind.
+func copyStatus(src, dst runtime.Object) error {
+	if u, ok := dst.(*unstructured.Unstructured); ok {
+		status, found := src.(*unstructured.Unstructured).Object["status"]
+		if !found {
+			delete(u.Object, "status")
+			return nil
+		}
+		u.Object["status"] = runtime.DeepCopyJSONValue(status)
+		return nil
+	}
+	srcStatus := reflect.ValueOf(src).Elem().FieldByName("Status")
+	dstStatus := reflect.ValueOf(dst).Elem().FieldByName("Status")
+	if !srcStatus.IsValid() || !dstStatus.IsValid() {
+		return fmt.Errorf("object of type %T has no Status field", dst)
+	}
+	dstStatus.Set(srcStatus)
+	return nil
+}
+
+// handleGetScale serves the autoscaling/v1 Scale of a Deployment, ReplicaSet or StatefulSet.
+func handleGetScale(d typeinfo.Descriptor, v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, err := v.GetObject(d.GVK, GetObjectName(r, d))
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		scale, err := scaleOf(obj)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		writeResponse(w, r, scale)
+	}
+}
+
+// handleUpdateScale sets the replicas of a Deployment, ReplicaSet or StatefulSet to those of the autoscaling/v1 Scale
+// in the request body.
+func handleUpdateScale(d typeinfo.Descriptor, v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scale := &autoscalingv1.Scale{}
+		if !readBodyIntoObj(w, r, scale) {
+			return
+		}
+		updateScale(w, r, d, v, GetObjectName(r, d), func(runtime.Object) (*autoscalingv1.Scale, error) {
+			return scale, nil
+		})
+	}
+}
+
+// handlePatchScale patches the autoscaling/v1 Scale of a Deployment, ReplicaSet or StatefulSet and sets its replicas to
+// those of the patched Scale.
+func handlePatchScale(d typeinfo.Descriptor, v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := GetObjectName(r, d)
+		contentType := r.Header.Get("Content-Type")
+		if contentType != string(types.StrategicMergePatchType) && contentType != string(types.MergePatchType) && contentType != string(types.JSONPatchType) {
+			handleBadRequest(w, r, fmt.Errorf("unsupported content type %q for scale of %q", contentType, name))
+			return
+		}
+		patchData, err := io.ReadAll(r.Body)
+		if err != nil {
+			handleInternalServerError(w, r, err)
+			return
+		}
+		updateScale(w, r, d, v, name, func(obj runtime.Object) (*autoscalingv1.Scale, error) {
+			scale, err := scaleOf(obj)
+			if err != nil {
+				return nil, err
+			}
+			if err = objutil.PatchObject(scale, name, types.PatchType(contentType), patchData); err != nil {
+				return nil, apierrors.NewBadRequest(err.Error())
+			}
+			return scale, nil
+		})
+	}
+}
+
+// updateScale sets the replicas of the stored object with the given name to those of the desired scale returned for it
+// by the given desiredScaleFn and writes the resulting Scale to the response. Like the kube-apiserver, the update is
+// conditional on the resource version of the desired scale if it is set to one other than that of the stored object.
+// Otherwise, it is unconditional and re-applied to the latest object on conflicts with concurrent writers.
+func updateScale(w http.ResponseWriter, r *http.Request, d typeinfo.Descriptor, v mkapi.View, name cache.ObjectName, desiredScaleFn func(obj runtime.Object) (*autoscalingv1.Scale, error)) {
+	var (
+		prevObj, obj   runtime.Object
+		preconditioned bool
+	)
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) && !preconditioned
+	}, func() (err error) {
+		if prevObj, err = v.GetObject(d.GVK, name); err != nil {
+			return err
+		}
+		scale, err := desiredScaleFn(prevObj)
+		if err != nil {
+			return err
+		}
+		mo := prevObj.(metav1.Object)
+		if scale.Name != mo.GetName() {
+			return apierrors.NewBadRequest(fmt.Sprintf("name %q of the scale does not match the name %q of the %s", scale.Name, mo.GetName(), d.Kind))
+		}
+		if scale.Spec.Replicas < 0 {
+			return apierrors.NewInvalid(autoscalingv1.SchemeGroupVersion.WithKind("Scale").GroupKind(), scale.Name, field.ErrorList{
+				field.Invalid(field.NewPath("spec", "replicas"), scale.Spec.Replicas, "must be greater than or equal to 0"),
+			})
+		}
+		obj = prevObj.DeepCopyObject()
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			o.Spec.Replicas = ptr.To(scale.Spec.Replicas)
+		case *appsv1.ReplicaSet:
+			o.Spec.Replicas = ptr.To(scale.Spec.Replicas)
+		case *appsv1.StatefulSet:
+			o.Spec.Replicas = ptr.To(scale.Spec.Replicas)
+		}
+		mo = obj.(metav1.Object)
+		if scale.ResourceVersion != "" {
+			preconditioned = scale.ResourceVersion != mo.GetResourceVersion()
+			mo.SetResourceVersion(scale.ResourceVersion)
+		}
+		return v.UpdateObject(d.GVK, mo)
+	})
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	auditMutation(r, v, mkapi.AuditVerbUpdate, d.GVK, prevObj, obj)
+	scale, err := scaleOf(obj)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	writeResponse(w, r, scale)
+}
+
+// scaleOf returns the autoscaling/v1 Scale of the given Deployment, ReplicaSet or StatefulSet.
+func scaleOf(obj runtime.Object) (*autoscalingv1.Scale, error) {
+	var (
+		replicas       *int32
+		statusReplicas int32
+		labelSelector  *metav1.LabelSelector
+	)
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		replicas, statusReplicas, labelSelector = o.Spec.Replicas, o.Status.Replicas, o.Spec.Selector
+	case *appsv1.ReplicaSet:
+		replicas, statusReplicas, labelSelector = o.Spec.Replicas, o.Status.Replicas, o.Spec.Selector
+	case *appsv1.StatefulSet:
+		replicas, statusReplicas, labelSelector = o.Spec.Replicas, o.Status.Replicas, o.Spec.Selector
+	default:
+		return nil, apierrors.NewInternalError(fmt.Errorf("object of type %T has no scale subresource", obj))
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("invalid label selector: %w", err))
+	}
+	mo := obj.(metav1.Object)
+	return &autoscalingv1.Scale{
+		TypeMeta: metav1.TypeMeta{Kind: "Scale", APIVersion: autoscalingv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              mo.GetName(),
+			Namespace:         mo.GetNamespace(),
+			UID:               mo.GetUID(),
+			ResourceVersion:   mo.GetResourceVersion(),
+			CreationTimestamp: mo.GetCreationTimestamp(),
+		},
+		Spec:   autoscalingv1.ScaleSpec{Replicas: ptr.Deref(replicas, 1)},
+		Status: autoscalingv1.ScaleStatus{Replicas: statusReplicas, Selector: selector.String()},
+	}, nil
+}
+
+// handleCreatePodEviction evicts a pod like the eviction subresource of the kube-apiserver, honouring the
+// PodDisruptionBudgets stored in the view. See view.EvictPod.
+func handleCreatePodEviction(v mkapi.View) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := logr.FromContextOrDiscard(r.Context())
+		d := typeinfo.PodsDescriptor
+		eviction := policyv1.Eviction{}
+		if !readBodyIntoObj(w, r, &eviction) {
+			return
+		}
+		podName := GetObjectName(r, d)
+		if eviction.Name != podName.Name {
+			handleStatusError(w, r, apierrors.NewBadRequest("name in URL does not match name in Eviction object"))
+			return
+		}
+		var opts mkapi.DeleteOptions
+		if eviction.DeleteOptions != nil {
+			opts = toDeleteOptions(*eviction.DeleteOptions)
+		}
+		result, err := view.EvictPod(v, podName, opts)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		if result.Budget != nil {
+			auditMutation(r, v, mkapi.AuditVerbUpdate, typeinfo.PodDisruptionBudgetDescriptor.GVK, result.PrevBudget, result.Budget)
+		}
+		var pendingPod runtime.Object
+		if result.PendingPod != nil {
+			pendingPod = result.PendingPod
+		}
+		auditDeletion(r, v, d.GVK, result.Pod, pendingPod, opts)
+		log.V(3).Info("evicted pod", "podName", podName)
+		writeResponseWithStatusCode(w, r, http.StatusCreated, &metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusSuccess,
+			Code:     http.StatusCreated,
+		})
+	}
+}
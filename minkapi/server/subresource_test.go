@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+func TestStatusSubresource(t *testing.T) {
+	tests := map[string]struct {
+		method       string
+		contentType  string
+		body         string
+		wantCode     int
+		wantAttached bool
+	}{
+		"get": {
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+		},
+		"patch": {
+			method:       http.MethodPatch,
+			contentType:  string(types.MergePatchType),
+			body:         `{"spec":{"attacher":"other"},"status":{"attached":true}}`,
+			wantCode:     http.StatusOK,
+			wantAttached: true,
+		},
+		"put": {
+			method:       http.MethodPut,
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"va"},"spec":{"attacher":"other"},"status":{"attached":true}}`,
+			wantCode:     http.StatusOK,
+			wantAttached: true,
+		},
+		"put with stale resource version": {
+			method:      http.MethodPut,
+			contentType: "application/json",
+			body:        `{"metadata":{"name":"va","resourceVersion":"12345"},"status":{"attached":true}}`,
+			wantCode:    http.StatusConflict,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, sandboxView := newSubresourceTestServer(t)
+			va := &storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "va"},
+				Spec:       storagev1.VolumeAttachmentSpec{Attacher: "csi", NodeName: "node"},
+			}
+			if err := sandboxView.CreateObject(typeinfo.VolumeAttachmentDescriptor.GVK, va); err != nil {
+				t.Fatalf("failed to create volume attachment: %v", err)
+			}
+			w := serveSubresourceRequest(k, tc.method, "/sb/apis/storage.k8s.io/v1/volumeattachments/va/status", tc.contentType, tc.body)
+			if w.Code != tc.wantCode {
+				t.Fatalf("expected status code %d, got %d: %s", tc.wantCode, w.Code, w.Body.String())
+			}
+			obj, err := sandboxView.GetObject(typeinfo.VolumeAttachmentDescriptor.GVK, cache.ObjectName{Name: va.Name})
+			if err != nil {
+				t.Fatalf("failed to get volume attachment: %v", err)
+			}
+			got := obj.(*storagev1.VolumeAttachment)
+			if got.Spec.Attacher != va.Spec.Attacher {
+				t.Errorf("expected attacher %q to be left unchanged, got %q", va.Spec.Attacher, got.Spec.Attacher)
+			}
+			if got.Status.Attached != tc.wantAttached {
+				t.Errorf("expected attached %t, got %t", tc.wantAttached, got.Status.Attached)
+			}
+		})
+	}
+}
+
+func TestScaleSubresource(t *testing.T) {
+	tests := map[string]struct {
+		method       string
+		contentType  string
+		body         string
+		wantCode     int
+		wantReplicas int32
+	}{
+		"get": {
+			method:       http.MethodGet,
+			wantCode:     http.StatusOK,
+			wantReplicas: 2,
+		},
+		"put": {
+			method:       http.MethodPut,
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"web","namespace":"default"},"spec":{"replicas":3}}`,
+			wantCode:     http.StatusOK,
+			wantReplicas: 3,
+		},
+		"merge patch": {
+			method:       http.MethodPatch,
+			contentType:  string(types.MergePatchType),
+			body:         `{"spec":{"replicas":0}}`,
+			wantCode:     http.StatusOK,
+			wantReplicas: 0,
+		},
+		"json patch": {
+			method:       http.MethodPatch,
+			contentType:  string(types.JSONPatchType),
+			body:         `[{"op":"replace","path":"/spec/replicas","value":5}]`,
+			wantCode:     http.StatusOK,
+			wantReplicas: 5,
+		},
+		"negative replicas": {
+			method:       http.MethodPut,
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"web","namespace":"default"},"spec":{"replicas":-1}}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantReplicas: 2,
+		},
+		"mismatching name": {
+			method:       http.MethodPut,
+			contentType:  "application/json",
+			body:         `{"metadata":{"name":"other","namespace":"default"},"spec":{"replicas":3}}`,
+			wantCode:     http.StatusBadRequest,
+			wantReplicas: 2,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			k, sandboxView := newSubresourceTestServer(t)
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](2),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			}
+			if err := sandboxView.CreateObject(typeinfo.DeploymentDescriptor.GVK, deploy); err != nil {
+				t.Fatalf("failed to create deployment: %v", err)
+			}
+			w := serveSubresourceRequest(k, tc.method, "/sb/apis/apps/v1/namespaces/default/deployments/web/scale", tc.contentType, tc.body)
+			if w.Code != tc.wantCode {
+				t.Fatalf("expected status code %d, got %d: %s", tc.wantCode, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK {
+				var scale autoscalingv1.Scale
+				if err := json.NewDecoder(w.Body).Decode(&scale); err != nil {
+					t.Fatalf("failed to decode scale: %v", err)
+				}
+				if scale.Kind != "Scale" || scale.Spec.Replicas != tc.wantReplicas || scale.Status.Selector != "app=web" {
+					t.Errorf("expected scale of %d replicas selecting app=web, got %+v", tc.wantReplicas, scale)
+				}
+			}
+			obj, err := sandboxView.GetObject(typeinfo.DeploymentDescriptor.GVK, cache.ObjectName{Namespace: deploy.Namespace, Name: deploy.Name})
+			if err != nil {
+				t.Fatalf("failed to get deployment: %v", err)
+			}
+			if got := *obj.(*appsv1.Deployment).Spec.Replicas; got != tc.wantReplicas {
+				t.Errorf("expected deployment with %d replicas, got %d", tc.wantReplicas, got)
+			}
+		})
+	}
+}
+
+func TestPodEviction(t *testing.T) {
+	k, sandboxView := newSubresourceTestServer(t)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "rs-uid"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: ptr.To[int32](2)},
+	}
+	if err := sandboxView.CreateObject(typeinfo.ReplicaSetDescriptor.GVK, rs); err != nil {
+		t.Fatalf("failed to create replicaset: %v", err)
+	}
+	for _, name := range []string{"web-a", "web-b"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: rs.Namespace,
+				Labels:    map[string]string{"app": "web"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "ReplicaSet", Name: rs.Name, UID: rs.UID, Controller: ptr.To(true),
+				}},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		if err := sandboxView.CreateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+	}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: rs.Namespace},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: ptr.To(intstr.FromInt32(1)),
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	if err := sandboxView.CreateObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, pdb); err != nil {
+		t.Fatalf("failed to create pod disruption budget: %v", err)
+	}
+
+	// the tests are run in order, since each eviction consumes the disruption allowed by the budget.
+	tests := []struct {
+		name     string
+		pod      string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "mismatching name",
+			pod:      "web-a",
+			body:     `{"apiVersion":"policy/v1","kind":"Eviction","metadata":{"name":"web-b","namespace":"default"}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "allowed by budget",
+			pod:      "web-a",
+			body:     `{"apiVersion":"policy/v1","kind":"Eviction","metadata":{"name":"web-a","namespace":"default"}}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "violating budget",
+			pod:      "web-b",
+			body:     `{"apiVersion":"policy/v1","kind":"Eviction","metadata":{"name":"web-b","namespace":"default"}}`,
+			wantCode: http.StatusTooManyRequests,
+		},
+		{
+			name:     "evicted pod",
+			pod:      "web-a",
+			body:     `{"apiVersion":"policy/v1","kind":"Eviction","metadata":{"name":"web-a","namespace":"default"}}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range tests {
+		target := fmt.Sprintf("/sb/api/v1/namespaces/default/pods/%s/eviction", tc.pod)
+		w := serveSubresourceRequest(k, http.MethodPost, target, "application/json", tc.body)
+		if w.Code != tc.wantCode {
+			t.Errorf("%s: expected status code %d, got %d: %s", tc.name, tc.wantCode, w.Code, w.Body.String())
+		}
+	}
+	obj, err := sandboxView.GetObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, cache.ObjectName{Namespace: pdb.Namespace, Name: pdb.Name})
+	if err != nil {
+		t.Fatalf("failed to get pod disruption budget: %v", err)
+	}
+	if status := obj.(*policyv1.PodDisruptionBudget).Status; status.DisruptionsAllowed != 0 || len(status.DisruptedPods) != 1 {
+		t.Errorf("expected budget to record the single allowed disruption, got %+v", status)
+	}
+}
+
+func TestSubresourceDiscovery(t *testing.T) {
+	k, _ := newSubresourceTestServer(t)
+	tests := map[string]struct {
+		target    string
+		wantNames []string
+	}{
+		"core": {
+			target:    "/sb/api/v1/",
+			wantNames: []string{"pods/status", "pods/binding", "pods/eviction", "nodes/status", "namespaces/status"},
+		},
+		"apps": {
+			target:    "/sb/apis/apps/v1",
+			wantNames: []string{"deployments/status", "deployments/scale", "replicasets/scale", "statefulsets/scale"},
+		},
+		"storage": {
+			target:    "/sb/apis/storage.k8s.io/v1",
+			wantNames: []string{"volumeattachments/status"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := serveSubresourceRequest(k, http.MethodGet, tc.target, "", "")
+			var list metav1.APIResourceList
+			if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+				t.Fatalf("failed to decode resource list: %v", err)
+			}
+			var names []string
+			for _, r := range list.APIResources {
+				names = append(names, r.Name)
+			}
+			for _, want := range tc.wantNames {
+				if !slices.Contains(names, want) {
+					t.Errorf("expected resource %q in %v", want, names)
+				}
+			}
+		})
+	}
+}
+
+func newSubresourceTestServer(t *testing.T) (*InMemoryKAPI, mkapi.View) {
+	t.Helper()
+	k := newMetricsTestServer(t)
+	sandboxView, err := k.GetSandboxView(context.Background(), "sb")
+	if err != nil {
+		t.Fatalf("failed to get sandbox view: %v", err)
+	}
+	return k, sandboxView
+}
+
+func serveSubresourceRequest(k *InMemoryKAPI, method, target, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	k.rootMux.ServeHTTP(w, r)
+	return w
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package typeinfo
+
+import (
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// NewCustomResourceDescriptor returns the Descriptor of the custom resource defined by the given CustomResourceDefinition.
+// Objects are not converted between versions, so only the storage version of the custom resource is served. An Invalid
+// StatusError is returned if the CustomResourceDefinition cannot be served.
+func NewCustomResourceDescriptor(crd *apiextensionsv1.CustomResourceDefinition) (d Descriptor, err error) {
+	var (
+		allErrs     field.ErrorList
+		specPath    = field.NewPath("spec")
+		names       = crd.Spec.Names
+		namespaced  = crd.Spec.Scope == apiextensionsv1.NamespaceScoped
+		storageVers *apiextensionsv1.CustomResourceDefinitionVersion
+	)
+	if crd.Spec.Group == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("group"), ""))
+	}
+	if names.Plural == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("names", "plural"), ""))
+	}
+	if names.Kind == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("names", "kind"), ""))
+	}
+	if wantName := names.Plural + "." + crd.Spec.Group; crd.Name != wantName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), crd.Name, fmt.Sprintf("must be spec.names.plural+\".\"+spec.group: %q", wantName)))
+	}
+	if !namespaced && crd.Spec.Scope != apiextensionsv1.ClusterScoped {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("scope"), crd.Spec.Scope, []apiextensionsv1.ResourceScope{apiextensionsv1.ClusterScoped, apiextensionsv1.NamespaceScoped}))
+	}
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			storageVers = &crd.Spec.Versions[i]
+			break
+		}
+	}
+	if storageVers == nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("versions"), crd.Spec.Versions, "must have exactly one version marked as storage version"))
+	} else if !storageVers.Served {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("versions"), storageVers.Name, "the storage version must be served"))
+	}
+	if len(allErrs) > 0 {
+		err = apierrors.NewInvalid(CustomResourceDefinitionsDescriptor.GVK.GroupKind(), crd.Name, allErrs)
+		return
+	}
+
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: storageVers.Name, Resource: names.Plural}
+	if SupportedScheme.Recognizes(gvr.GroupVersion().WithKind(names.Kind)) {
+		err = apierrors.NewInvalid(CustomResourceDefinitionsDescriptor.GVK.GroupKind(), crd.Name, field.ErrorList{
+			field.Invalid(specPath.Child("names", "kind"), names.Kind, fmt.Sprintf("conflicts with the built-in kind in %q", gvr.GroupVersion())),
+		})
+		return
+	}
+	listKind := names.ListKind
+	if listKind == "" {
+		listKind = names.Kind + "List"
+	}
+	d = NewDescriptor(KindName(names.Kind), KindName(listKind), namespaced, gvr, names.ShortNames...)
+	d.APIResource.SingularName = names.Singular
+	if d.APIResource.SingularName == "" {
+		d.APIResource.SingularName = strings.ToLower(names.Kind)
+	}
+	d.APIResource.Categories = names.Categories
+	d.StatusSubresource = storageVers.Subresources != nil && storageVers.Subresources.Status != nil
+	d.PrinterColumns = storageVers.AdditionalPrinterColumns
+	if storageVers.Schema != nil {
+		d.OpenAPIV3Schema = storageVers.Schema.OpenAPIV3Schema
+	}
+	return
+}
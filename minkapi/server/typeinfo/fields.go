@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package typeinfo
+
+import (
+	"strconv"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// The field sets below mirror the ones registered as field label conversion funcs by the real API server for the
+// corresponding kinds. Ex: https://github.com/kubernetes/kubernetes/blob/master/pkg/registry/core/pod/strategy.go
+
+func podFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return set
+	}
+	var podIP string
+	if len(pod.Status.PodIPs) > 0 {
+		podIP = pod.Status.PodIPs[0].IP
+	}
+	set["spec.nodeName"] = pod.Spec.NodeName
+	set["spec.restartPolicy"] = string(pod.Spec.RestartPolicy)
+	set["spec.schedulerName"] = pod.Spec.SchedulerName
+	set["spec.serviceAccountName"] = pod.Spec.ServiceAccountName
+	set["spec.hostNetwork"] = strconv.FormatBool(pod.Spec.HostNetwork)
+	set["status.phase"] = string(pod.Status.Phase)
+	set["status.podIP"] = podIP
+	set["status.nominatedNodeName"] = pod.Status.NominatedNodeName
+	return set
+}
+
+func nodeFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return set
+	}
+	set["spec.unschedulable"] = strconv.FormatBool(node.Spec.Unschedulable)
+	return set
+}
+
+func namespaceFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return set
+	}
+	set["status.phase"] = string(ns.Status.Phase)
+	return set
+}
+
+func serviceFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return set
+	}
+	set["spec.clusterIP"] = svc.Spec.ClusterIP
+	set["spec.type"] = string(svc.Spec.Type)
+	return set
+}
+
+func replicationControllerFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	rc, ok := obj.(*corev1.ReplicationController)
+	if !ok {
+		return set
+	}
+	set["status.replicas"] = strconv.Itoa(int(rc.Status.Replicas))
+	return set
+}
+
+func replicaSetFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	rs, ok := obj.(*appsv1.ReplicaSet)
+	if !ok {
+		return set
+	}
+	set["status.replicas"] = strconv.Itoa(int(rs.Status.Replicas))
+	return set
+}
+
+func eventFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	ev, ok := obj.(*eventsv1.Event)
+	if !ok {
+		return set
+	}
+	set["regarding.kind"] = ev.Regarding.Kind
+	set["regarding.namespace"] = ev.Regarding.Namespace
+	set["regarding.name"] = ev.Regarding.Name
+	set["regarding.uid"] = string(ev.Regarding.UID)
+	set["regarding.apiVersion"] = ev.Regarding.APIVersion
+	set["regarding.resourceVersion"] = ev.Regarding.ResourceVersion
+	set["regarding.fieldPath"] = ev.Regarding.FieldPath
+	set["reason"] = ev.Reason
+	set["reportingController"] = ev.ReportingController
+	set["type"] = ev.Type
+	return set
+}
+
+func coreEventFields(obj metav1.Object) fields.Set {
+	set := mkapi.DefaultObjectFields(obj)
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return set
+	}
+	set["involvedObject.kind"] = ev.InvolvedObject.Kind
+	set["involvedObject.namespace"] = ev.InvolvedObject.Namespace
+	set["involvedObject.name"] = ev.InvolvedObject.Name
+	set["involvedObject.uid"] = string(ev.InvolvedObject.UID)
+	set["involvedObject.apiVersion"] = ev.InvolvedObject.APIVersion
+	set["involvedObject.resourceVersion"] = ev.InvolvedObject.ResourceVersion
+	set["involvedObject.fieldPath"] = ev.InvolvedObject.FieldPath
+	set["reason"] = ev.Reason
+	set["reportingComponent"] = ev.ReportingController
+	set["source"] = ev.Source.Component
+	set["type"] = ev.Type
+	return set
+}
@@ -11,7 +11,9 @@ import (
 	"strings"
 
 	commonconstants "github.com/gardener/scaling-advisor/api/common/constants"
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
@@ -19,7 +21,11 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
@@ -97,6 +103,9 @@ const (
 
 	VolumeAttachmentKind     KindName = "VolumeAttachment"
 	VolumeAttachmentListKind KindName = "VolumeAttachmentList"
+
+	CustomResourceDefinitionKind     KindName = "CustomResourceDefinition"
+	CustomResourceDefinitionListKind KindName = "CustomResourceDefinitionList"
 )
 
 // Descriptor is an aggregate holder of various bits of type information on a given Kind
@@ -108,6 +117,21 @@ type Descriptor struct {
 	GVR          schema.GroupVersionResource
 	ListTypeMeta metav1.TypeMeta
 	APIResource  metav1.APIResource
+	// SelectableFields returns the field labels and values supported in field selectors for objects of this kind.
+	SelectableFields mkapi.ObjectFieldsFunc
+	// Indexes are the secondary indexes of the resource stores of this kind. Objects of namespaced kinds are indexed by
+	// namespace.
+	Indexes mkapi.IndexConfig
+	// StatusSubresource is true for built-in kinds with a status and for custom resources whose
+	// CustomResourceDefinition enables the status subresource.
+	StatusSubresource bool
+	// ScaleSubresource is true for built-in kinds whose replicas can be read and updated through the autoscaling/v1
+	// Scale of their scale subresource.
+	ScaleSubresource bool
+	// PrinterColumns are the additional printer columns of custom resources whose CustomResourceDefinition defines any.
+	PrinterColumns []apiextensionsv1.CustomResourceColumnDefinition
+	// OpenAPIV3Schema is the structural schema of custom resources whose CustomResourceDefinition defines one.
+	OpenAPIV3Schema *apiextensionsv1.JSONSchemaProps
 	//ObjTemplate     runtime.Object
 	//ObjListTemplate runtime.Object
 	//ObjType         reflect.Type
@@ -117,29 +141,32 @@ type Descriptor struct {
 
 var (
 	SupportedScheme      = RegisterSchemes()
-	NamespacesDescriptor = NewDescriptor(NamespaceKind, NamespaceListKind, false, corev1.SchemeGroupVersion.WithResource("namespaces"), "ns")
+	NamespacesDescriptor = NewDescriptor(NamespaceKind, NamespaceListKind, false, corev1.SchemeGroupVersion.WithResource("namespaces"), "ns").WithSelectableFields(namespaceFields)
 
 	ServiceAccountsDescriptor = NewDescriptor(ServiceAccountKind, ServiceAccountListKind, true, corev1.SchemeGroupVersion.WithResource("serviceaccounts"), "sa")
 
 	ConfigMapsDescriptor = NewDescriptor(ConfigMapKind, ConfigMapListKind, true, corev1.SchemeGroupVersion.WithResource("configmaps"), "cm")
-	NodesDescriptor      = NewDescriptor(NodeKind, NodeListKind, false, corev1.SchemeGroupVersion.WithResource("nodes"), "no")
-	PodsDescriptor       = NewDescriptor(PodKind, PodListKind, true, corev1.SchemeGroupVersion.WithResource("pods"), "po")
+	NodesDescriptor      = NewDescriptor(NodeKind, NodeListKind, false, corev1.SchemeGroupVersion.WithResource("nodes"), "no").WithSelectableFields(nodeFields)
+	PodsDescriptor       = NewDescriptor(PodKind, PodListKind, true, corev1.SchemeGroupVersion.WithResource("pods"), "po").WithSelectableFields(podFields).WithIndexedFields("spec.nodeName")
 
-	ServicesDescriptor          = NewDescriptor(ServiceKind, ServiceListKind, true, corev1.SchemeGroupVersion.WithResource("services"), "svc")
+	ServicesDescriptor          = NewDescriptor(ServiceKind, ServiceListKind, true, corev1.SchemeGroupVersion.WithResource("services"), "svc").WithSelectableFields(serviceFields)
 	PersistentVolumesDescriptor = NewDescriptor(PersistentVolumeKind, PersistentVolumeListKind, false, corev1.SchemeGroupVersion.WithResource("persistentvolumes"), "pv")
 
 	PersistentVolumeClaimsDescriptor = NewDescriptor(PersistentVolumeClaimKind, PersistentVolumeClaimListKind, true, corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), "pvc")
 
-	ReplicationControllersDescriptor = NewDescriptor(ReplicationControllerKind, ReplicationControllerListKind, true, corev1.SchemeGroupVersion.WithResource("replicationcontrollers"), "rc")
+	// CoreEventsDescriptor describes the core/v1 events, which are served alongside the events.k8s.io/v1 events of the EventsDescriptor.
+	CoreEventsDescriptor = NewDescriptor(EventKind, EventListKind, true, corev1.SchemeGroupVersion.WithResource("events"), "ev").WithSelectableFields(coreEventFields)
+
+	ReplicationControllersDescriptor = NewDescriptor(ReplicationControllerKind, ReplicationControllerListKind, true, corev1.SchemeGroupVersion.WithResource("replicationcontrollers"), "rc").WithSelectableFields(replicationControllerFields)
 	PriorityClassesDescriptor        = NewDescriptor(PriorityClassKind, PriorityClassListKind, false, schedulingv1.SchemeGroupVersion.WithResource("priorityclasses"), "pc")
 
 	LeaseDescriptor = NewDescriptor(LeaseKind, LeaseListKind, true, coordinationv1.SchemeGroupVersion.WithResource("leases"))
 
-	EventsDescriptor              = NewDescriptor(EventKind, EventListKind, true, eventsv1.SchemeGroupVersion.WithResource("events"), "ev")
+	EventsDescriptor              = NewDescriptor(EventKind, EventListKind, true, eventsv1.SchemeGroupVersion.WithResource("events"), "ev").WithSelectableFields(eventFields)
 	RolesDescriptor               = NewDescriptor(RoleKind, RoleListKind, true, rbacv1.SchemeGroupVersion.WithResource("roles"))
-	DeploymentDescriptor          = NewDescriptor(DeploymentKind, DeploymentListKind, true, appsv1.SchemeGroupVersion.WithResource("deployments"), "deploy")
-	ReplicaSetDescriptor          = NewDescriptor(ReplicaSetKind, ReplicaSetListKind, true, appsv1.SchemeGroupVersion.WithResource("replicasets"), "rs")
-	StatefulSetDescriptor         = NewDescriptor(StatefulSetKind, StatefulSetListKind, true, appsv1.SchemeGroupVersion.WithResource("statefulsets"), "sts")
+	DeploymentDescriptor          = NewDescriptor(DeploymentKind, DeploymentListKind, true, appsv1.SchemeGroupVersion.WithResource("deployments"), "deploy").WithScaleSubresource()
+	ReplicaSetDescriptor          = NewDescriptor(ReplicaSetKind, ReplicaSetListKind, true, appsv1.SchemeGroupVersion.WithResource("replicasets"), "rs").WithSelectableFields(replicaSetFields).WithScaleSubresource()
+	StatefulSetDescriptor         = NewDescriptor(StatefulSetKind, StatefulSetListKind, true, appsv1.SchemeGroupVersion.WithResource("statefulsets"), "sts").WithScaleSubresource()
 	PodDisruptionBudgetDescriptor = NewDescriptor(PodDisruptionBudgetKind, PodDisruptionBudgetListKind, true, policyv1.SchemeGroupVersion.WithResource("poddisruptionbudgets"), "pdb")
 
 	StorageClassDescriptor = NewDescriptor(StorageClassKind, StorageClassListKind, false, storagev1.SchemeGroupVersion.WithResource("storageclasses"), "sc")
@@ -151,8 +178,11 @@ var (
 
 	VolumeAttachmentDescriptor = NewDescriptor(VolumeAttachmentKind, VolumeAttachmentListKind, false, storagev1.SchemeGroupVersion.WithResource("volumeattachments"))
 
+	CustomResourceDefinitionsDescriptor = NewDescriptor(CustomResourceDefinitionKind, CustomResourceDefinitionListKind, false, apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"), "crd", "crds")
+
 	SupportedDescriptors = []Descriptor{
 		ServiceAccountsDescriptor, ConfigMapsDescriptor, NamespacesDescriptor, NodesDescriptor, PodsDescriptor, ServicesDescriptor, PersistentVolumesDescriptor, PersistentVolumeClaimsDescriptor, ReplicationControllersDescriptor,
+		CoreEventsDescriptor,
 		PriorityClassesDescriptor,
 		LeaseDescriptor,
 		EventsDescriptor,
@@ -160,9 +190,10 @@ var (
 		DeploymentDescriptor, ReplicaSetDescriptor, StatefulSetDescriptor,
 		PodDisruptionBudgetDescriptor,
 		StorageClassDescriptor, CSIDriverDescriptor, CSIStorageCapacityDescriptor, CSINodeDescriptor, VolumeAttachmentDescriptor,
+		CustomResourceDefinitionsDescriptor,
 	}
 
-	SupportedVerbs = []string{"create", "delete", "get", "list", "patch", "watch"}
+	SupportedVerbs = []string{"create", "delete", "deletecollection", "get", "list", "patch", "watch"}
 
 	SupportedAPIVersions = metav1.APIVersions{
 		TypeMeta: metav1.TypeMeta{
@@ -177,81 +208,89 @@ var (
 		},
 	}
 
-	SupportedAPIGroups = buildAPIGroupList()
+	SupportedAPIGroups = BuildAPIGroupList(SupportedDescriptors)
 
 	SupportedCoreAPIResourceList = metav1.APIResourceList{
 		TypeMeta: metav1.TypeMeta{
 			Kind: "APIResourceList",
 		},
 		GroupVersion: "v1",
-		APIResources: []metav1.APIResource{
-			ServiceAccountsDescriptor.APIResource,
-			ConfigMapsDescriptor.APIResource,
-			NamespacesDescriptor.APIResource,
-			NodesDescriptor.APIResource,
-			PodsDescriptor.APIResource,
-			ServicesDescriptor.APIResource,
-			PersistentVolumesDescriptor.APIResource,
-			PersistentVolumeClaimsDescriptor.APIResource,
-			ReplicationControllersDescriptor.APIResource,
-		},
+		APIResources: apiResourcesOf(
+			ServiceAccountsDescriptor,
+			ConfigMapsDescriptor,
+			NamespacesDescriptor,
+			NodesDescriptor,
+			PodsDescriptor,
+			ServicesDescriptor,
+			PersistentVolumesDescriptor,
+			PersistentVolumeClaimsDescriptor,
+			ReplicationControllersDescriptor,
+			CoreEventsDescriptor,
+		),
 	}
 
 	SupportedGroupAPIResourceLists = []metav1.APIResourceList{
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: appsv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				DeploymentDescriptor.APIResource,
-				ReplicaSetDescriptor.APIResource,
-				StatefulSetDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				DeploymentDescriptor,
+				ReplicaSetDescriptor,
+				StatefulSetDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: coordinationv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				LeaseDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				LeaseDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: eventsv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				EventsDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				EventsDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: rbacv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				RolesDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				RolesDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: schedulingv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				PriorityClassesDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				PriorityClassesDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: policyv1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				PodDisruptionBudgetDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				PodDisruptionBudgetDescriptor,
+			),
 		},
 		{
 			TypeMeta:     metaV1APIResourceList,
 			GroupVersion: storagev1.SchemeGroupVersion.String(),
-			APIResources: []metav1.APIResource{
-				StorageClassDescriptor.APIResource,
-				CSIDriverDescriptor.APIResource,
-				CSIStorageCapacityDescriptor.APIResource,
-				CSINodeDescriptor.APIResource,
-				VolumeAttachmentDescriptor.APIResource,
-			},
+			APIResources: apiResourcesOf(
+				StorageClassDescriptor,
+				CSIDriverDescriptor,
+				CSIStorageCapacityDescriptor,
+				CSINodeDescriptor,
+				VolumeAttachmentDescriptor,
+			),
+		},
+		{
+			TypeMeta:     metaV1APIResourceList,
+			GroupVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			APIResources: apiResourcesOf(
+				CustomResourceDefinitionsDescriptor,
+			),
 		},
 	}
 )
@@ -261,12 +300,14 @@ var (
 		metav1.AddMetaToScheme,
 		corev1.AddToScheme,
 		appsv1.AddToScheme,
+		autoscalingv1.AddToScheme,
 		coordinationv1.AddToScheme,
 		eventsv1.AddToScheme,
 		rbacv1.AddToScheme,
 		schedulingv1.AddToScheme,
 		policyv1.AddToScheme,
 		storagev1.AddToScheme,
+		apiextensionsv1.AddToScheme,
 	}
 
 	metaV1APIResourceList = metav1.TypeMeta{
@@ -283,28 +324,32 @@ func RegisterSchemes() (scheme *runtime.Scheme) {
 	return
 }
 
-func buildAPIGroupList() metav1.APIGroupList {
+// BuildAPIGroupList returns the discovery list of the non-core API groups of the given descriptors. The preferred version
+// of a group is the version of its first descriptor.
+func BuildAPIGroupList(descriptors []Descriptor) metav1.APIGroupList {
 	var groups = make(map[string]metav1.APIGroup)
-	for _, d := range SupportedDescriptors {
+	for _, d := range descriptors {
 		if d.GVK.Group == "" {
 			//  don't add default group otherwise kubectl will  give errors like the below
 			// error: /, Kind=Pod matches multiple kinds [/v1, Kind=Pod /v1, Kind=Pod]
 			// OH-MY-GAWD, it took me FOREVER to find this.
 			continue
 		}
-		groups[d.GVR.Group] = metav1.APIGroup{
-			Name: d.GVR.Group,
-			Versions: []metav1.GroupVersionForDiscovery{
-				{
-					GroupVersion: d.GVK.GroupVersion().String(),
-					Version:      d.GVK.Version,
-				},
-			},
-			PreferredVersion: metav1.GroupVersionForDiscovery{
-				GroupVersion: d.GVK.GroupVersion().String(),
-				Version:      d.GVK.Version,
-			},
+		version := metav1.GroupVersionForDiscovery{
+			GroupVersion: d.GVK.GroupVersion().String(),
+			Version:      d.GVK.Version,
+		}
+		group, exists := groups[d.GVR.Group]
+		if !exists {
+			group = metav1.APIGroup{
+				Name:             d.GVR.Group,
+				PreferredVersion: version,
+			}
+		}
+		if !slices.Contains(group.Versions, version) {
+			group.Versions = append(group.Versions, version)
 		}
+		groups[d.GVR.Group] = group
 	}
 	return metav1.APIGroupList{
 		TypeMeta: metav1.TypeMeta{
@@ -326,13 +371,10 @@ func NewDescriptor(kind KindName, listKind KindName, namespaced bool, gvr schema
 	} else {
 		singularName = strings.TrimSuffix(gvr.Resource, "s")
 	}
+	gvk := gvr.GroupVersion().WithKind(string(kind))
 	return Descriptor{
-		Kind: kind,
-		GVK: schema.GroupVersionKind{
-			Group:   gvr.Group,
-			Version: gvr.Version,
-			Kind:    string(kind),
-		},
+		Kind:     kind,
+		GVK:      gvk,
 		ListKind: listKind,
 		ListGVK: schema.GroupVersionKind{
 			Group:   gvr.Group,
@@ -356,10 +398,118 @@ func NewDescriptor(kind KindName, listKind KindName, namespaced bool, gvr schema
 			Categories:         []string{"all"}, // TODO: Uhhh, WTH is this exactly ? Who uses this ?
 			StorageVersionHash: GenerateName(singularName),
 		},
+		SelectableFields:  mkapi.DefaultObjectFields,
+		Indexes:           mkapi.IndexConfig{Namespace: namespaced},
+		StatusSubresource: hasStatus(gvk),
 	}
 }
 
+// hasStatus returns whether objects of the given built-in kind have a status, which is then served through the status
+// subresource like in the kube-apiserver.
+func hasStatus(gvk schema.GroupVersionKind) bool {
+	t, ok := SupportedScheme.AllKnownTypes()[gvk]
+	if !ok {
+		return false
+	}
+	_, ok = t.FieldByName("Status")
+	return ok
+}
+
+// WithScaleSubresource returns a copy of this Descriptor whose kind serves the scale subresource.
+func (d Descriptor) WithScaleSubresource() Descriptor {
+	d.ScaleSubresource = true
+	return d
+}
+
+// SubresourceAPIResources returns the discovery information of the subresources served for this kind.
+func (d Descriptor) SubresourceAPIResources() (apiResources []metav1.APIResource) {
+	subresource := func(name string, gvk schema.GroupVersionKind, verbs ...string) metav1.APIResource {
+		return metav1.APIResource{
+			Name:       d.GVR.Resource + "/" + name,
+			Namespaced: d.APIResource.Namespaced,
+			Group:      gvk.Group,
+			Version:    gvk.Version,
+			Kind:       gvk.Kind,
+			Verbs:      verbs,
+		}
+	}
+	if d.StatusSubresource {
+		apiResources = append(apiResources, subresource("status", d.GVK, "get", "patch", "update"))
+	}
+	if d.ScaleSubresource {
+		apiResources = append(apiResources, subresource("scale", autoscalingv1.SchemeGroupVersion.WithKind("Scale"), "get", "patch", "update"))
+	}
+	if d.GVK == corev1.SchemeGroupVersion.WithKind(string(PodKind)) {
+		apiResources = append(apiResources,
+			subresource("binding", corev1.SchemeGroupVersion.WithKind("Binding"), "create"),
+			subresource("eviction", policyv1.SchemeGroupVersion.WithKind("Eviction"), "create"))
+	}
+	return
+}
+
+// apiResourcesOf returns the discovery information of the kinds described by the given descriptors, each followed by
+// that of its subresources.
+func apiResourcesOf(descriptors ...Descriptor) (apiResources []metav1.APIResource) {
+	for _, d := range descriptors {
+		apiResources = append(apiResources, d.APIResource)
+		apiResources = append(apiResources, d.SubresourceAPIResources()...)
+	}
+	return
+}
+
+// WithSelectableFields returns a copy of this Descriptor whose objects support the field labels returned by the given
+// fieldsFn in field selectors. The fieldsFn is expected to include the DefaultObjectFields.
+func (d Descriptor) WithSelectableFields(fieldsFn mkapi.ObjectFieldsFunc) Descriptor {
+	d.SelectableFields = fieldsFn
+	return d
+}
+
+// WithIndexedFields returns a copy of this Descriptor whose objects are additionally indexed by the value of the given
+// selectable field labels.
+func (d Descriptor) WithIndexedFields(fieldLabels ...string) Descriptor {
+	d.Indexes.FieldLabels = append(slices.Clone(d.Indexes.FieldLabels), fieldLabels...)
+	return d
+}
+
+// IndexesWithLabelKeys returns the Indexes of this Descriptor with objects additionally indexed by the value of the
+// given label keys.
+func (d Descriptor) IndexesWithLabelKeys(labelKeys ...string) mkapi.IndexConfig {
+	indexes := d.Indexes
+	for _, k := range labelKeys {
+		if !slices.Contains(indexes.LabelKeys, k) {
+			indexes.LabelKeys = append(slices.Clone(indexes.LabelKeys), k)
+		}
+	}
+	return indexes
+}
+
+// ConvertFieldSelector validates that all field labels used in the given selector are supported for this kind and returns
+// the selector to be used for matching. A BadRequest StatusError is returned for unsupported field labels.
+func (d Descriptor) ConvertFieldSelector(selector fields.Selector) (fields.Selector, error) {
+	if selector == nil || selector.Empty() {
+		return selector, nil
+	}
+	obj, err := d.CreateObject()
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	supported := d.SelectableFields(obj)
+	return selector.Transform(func(field, value string) (string, string, error) {
+		if !supported.Has(field) {
+			return "", "", apierrors.NewBadRequest(fmt.Sprintf("field label not supported: %s", field))
+		}
+		return field, value, nil
+	})
+}
+
+// CreateObject returns a new empty object of this kind. Objects of kinds not registered in the SupportedScheme, such as
+// custom resources, are unstructured.
 func (d Descriptor) CreateObject() (obj metav1.Object, err error) {
+	if !SupportedScheme.Recognizes(d.GVK) {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(d.GVK)
+		return u, nil
+	}
 	runtimeObj, err := SupportedScheme.New(d.GVK)
 	if err != nil {
 		return
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package typeinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// apiModulePath is the path of the module of the API types served by minkapi.
+const apiModulePath = "k8s.io/api"
+
+// ServerVersion is the version served at /version. Since minkapi serves the API types of the k8s.io/api module it is
+// built with, it reports the Kubernetes version of that module, such as v1.33.3 for k8s.io/api v0.33.3, so that clients
+// negotiate the API like they would with a kube-apiserver of that version.
+var ServerVersion = buildServerVersion()
+
+func buildServerVersion() version.Info {
+	info := version.Info{
+		GitVersion:   "v0.0.0-unknown",
+		GitTreeState: "unknown",
+		GoVersion:    runtime.Version(),
+		Compiler:     runtime.Compiler,
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, dep := range buildInfo.Deps {
+		if dep.Path != apiModulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if minor, found := strings.CutPrefix(dep.Version, "v0."); found {
+			info.GitVersion = "v1." + minor
+		}
+		break
+	}
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.GitCommit = setting.Value
+		case "vcs.time":
+			info.BuildDate = setting.Value
+		case "vcs.modified":
+			if modified, err := strconv.ParseBool(setting.Value); err == nil {
+				info.GitTreeState = map[bool]string{true: "dirty", false: "clean"}[modified]
+			}
+		}
+	}
+	if v, err := utilversion.ParseSemantic(info.GitVersion); err == nil {
+		info.Major = strconv.FormatUint(uint64(v.Major()), 10)
+		info.Minor = strconv.FormatUint(uint64(v.Minor()), 10)
+	}
+	return info
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured/unstructuredscheme"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/applyconfigurations"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	// fieldManagers caches the managedfields.FieldManager per fieldManagerKey.
+	fieldManagers sync.Map
+	// typedConverter converts objects of kinds registered in typeinfo.SupportedScheme using the built-in schemas of the
+	// kubernetes API types.
+	typedConverter = sync.OnceValue(func() managedfields.TypeConverter {
+		return applyconfigurations.NewTypeConverter(typeinfo.SupportedScheme)
+	})
+)
+
+// applyObject performs a server-side apply of the given applyData on the object with the given objName in the given
+// view, creating the object if it does not exist. Since the update of an existing object is conditional on the resource
+// version of the object the configuration was applied to, the returned prevObj is the object as it was stored right
+// before the update, or nil if the object was created.
+func applyObject(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, applyData []byte, opts minkapi.ApplyOptions) (prevObj, appliedObj runtime.Object, err error) {
+	if opts.FieldManager == "" {
+		err = apierrors.NewBadRequest(fmt.Sprintf("fieldManager is required for apply of %s %q", gvk.Kind, objName))
+		return
+	}
+	patchObj, err := decodeApplyConfiguration(gvk, objName, applyData)
+	if err != nil {
+		return
+	}
+	mgr, err := getFieldManager(gvk, "")
+	if err != nil {
+		return
+	}
+	exists := true
+	liveObj, err := v.GetObject(gvk, objName)
+	if apierrors.IsNotFound(err) {
+		exists = false
+		liveObj, err = newObject(gvk)
+	}
+	if err != nil {
+		return
+	}
+	appliedObj, err = mgr.Apply(liveObj.DeepCopyObject(), patchObj, opts.FieldManager, opts.Force)
+	if err != nil {
+		err = fmt.Errorf("failed to apply object %q: %w", objName, err)
+		return
+	}
+	appliedObj, err = toTypedObject(gvk, appliedObj)
+	if err != nil {
+		return
+	}
+	mo, err := meta.Accessor(appliedObj)
+	if err != nil {
+		err = fmt.Errorf("applied object %q is not metav1.Object: %w", objName, err)
+		return
+	}
+	if exists {
+		if err = v.UpdateObject(gvk, mo); err == nil {
+			prevObj = liveObj
+		}
+	} else {
+		err = v.CreateObject(gvk, mo)
+	}
+	return
+}
+
+// decodeApplyConfiguration decodes the given YAML or JSON applyData into an unstructured object and checks that it
+// identifies the object with the given objName of the given gvk.
+func decodeApplyConfiguration(gvk schema.GroupVersionKind, objName cache.ObjectName, applyData []byte) (*unstructured.Unstructured, error) {
+	data, err := utilyaml.ToJSON(applyData)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("cannot decode apply configuration for %s %q: %v", gvk.Kind, objName, err))
+	}
+	patchObj := &unstructured.Unstructured{}
+	if err = patchObj.UnmarshalJSON(data); err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("cannot decode apply configuration for %s %q: %v", gvk.Kind, objName, err))
+	}
+	if patchGVK := patchObj.GroupVersionKind(); patchGVK != gvk {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("apply configuration for %q has apiVersion %q and kind %q, expected %q and %q", objName, patchGVK.GroupVersion(), patchGVK.Kind, gvk.GroupVersion(), gvk.Kind))
+	}
+	if name := patchObj.GetName(); name != "" && name != objName.Name {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("the name of the object (%s) does not match the name on the URL (%s)", name, objName.Name))
+	}
+	if namespace := patchObj.GetNamespace(); namespace != "" && namespace != objName.Namespace {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("the namespace of the object (%s) does not match the namespace on the URL (%s)", namespace, objName.Namespace))
+	}
+	patchObj.SetName(objName.Name)
+	patchObj.SetNamespace(objName.Namespace)
+	return patchObj, nil
+}
+
+// RecordUpdate records an Update entry of the given manager in the managedFields of the given newObj of the given gvk,
+// which replaces the given liveObj through the given subresource, or the object itself if the subresource is empty.
+// Like in the kube-apiserver, the entry lists the fields changed by the manager, which are no longer owned by other
+// managers, and the managedFields are reset instead of failing the write if they cannot be updated. A new object is
+// created from an empty liveObj.
+func RecordUpdate(gvk schema.GroupVersionKind, subresource string, liveObj, newObj runtime.Object, manager string) error {
+	mgr, err := getFieldManager(gvk, subresource)
+	if err != nil {
+		return err
+	}
+	updatedObj := mgr.UpdateNoErrors(liveObj, newObj.DeepCopyObject(), manager)
+	updatedMo, err := meta.Accessor(updatedObj)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	mo, err := meta.Accessor(newObj)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	mo.SetManagedFields(updatedMo.GetManagedFields())
+	return nil
+}
+
+// fieldManagerKey identifies the managedfields.FieldManager of a subresource of a GVK.
+type fieldManagerKey struct {
+	gvk         schema.GroupVersionKind
+	subresource string
+}
+
+func getFieldManager(gvk schema.GroupVersionKind, subresource string) (*managedfields.FieldManager, error) {
+	key := fieldManagerKey{gvk: gvk, subresource: subresource}
+	if mgr, ok := fieldManagers.Load(key); ok {
+		return mgr.(*managedfields.FieldManager), nil
+	}
+	mgr, err := newFieldManager(gvk, subresource)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("cannot create field manager for %q: %w", gvk, err))
+	}
+	actual, _ := fieldManagers.LoadOrStore(key, mgr)
+	return actual.(*managedfields.FieldManager), nil
+}
+
+// newFieldManager creates a managedfields.FieldManager for the given subresource of the given gvk. Kinds registered in
+// typeinfo.SupportedScheme are merged using their built-in schemas. Other kinds are treated as unstructured and merged
+// using a schema deduced from the objects themselves, where all fields including lists are atomic.
+func newFieldManager(gvk schema.GroupVersionKind, subresource string) (*managedfields.FieldManager, error) {
+	scheme := typeinfo.SupportedScheme
+	if scheme.Recognizes(gvk) {
+		return managedfields.NewDefaultFieldManager(typedConverter(), scheme, scheme, scheme, gvk, gvk.GroupVersion(), subresource, nil)
+	}
+	return managedfields.NewDefaultCRDFieldManager(managedfields.NewDeducedTypeConverter(), unstructuredConvertor{}, unstructuredDefaulter{}, unstructuredscheme.NewUnstructuredCreator(), gvk, gvk.GroupVersion(), subresource, nil)
+}
+
+// newObject returns an empty object of the given gvk to which an apply configuration can be applied.
+func newObject(gvk schema.GroupVersionKind) (obj runtime.Object, err error) {
+	if typeinfo.SupportedScheme.Recognizes(gvk) {
+		obj, err = typeinfo.SupportedScheme.New(gvk)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+	} else {
+		obj = &unstructured.Unstructured{}
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return
+}
+
+// toTypedObject converts the given obj to the Go type registered for the given gvk in typeinfo.SupportedScheme. The
+// obj is returned as is if it is already typed or if the gvk is not registered.
+func toTypedObject(gvk schema.GroupVersionKind, obj runtime.Object) (runtime.Object, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || !typeinfo.SupportedScheme.Recognizes(gvk) {
+		return obj, nil
+	}
+	typed, err := newObject(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), typed); err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("cannot convert applied %s to typed object: %w", gvk.Kind, err))
+	}
+	return typed, nil
+}
+
+// unstructuredConvertor is a runtime.ObjectConvertor for unstructured objects of kinds not registered in a scheme. Such
+// kinds are served in a single version, so only conversions to the version of the object itself are supported.
+type unstructuredConvertor struct{}
+
+var _ runtime.ObjectConvertor = unstructuredConvertor{}
+
+func (unstructuredConvertor) Convert(in, out, _ any) error {
+	uIn, okIn := in.(*unstructured.Unstructured)
+	uOut, okOut := out.(*unstructured.Unstructured)
+	if !okIn || !okOut {
+		return fmt.Errorf("cannot convert %T to %T: only unstructured objects are supported", in, out)
+	}
+	uOut.Object = runtime.DeepCopyJSON(uIn.Object)
+	return nil
+}
+
+func (unstructuredConvertor) ConvertToVersion(in runtime.Object, gv runtime.GroupVersioner) (runtime.Object, error) {
+	gvk := in.GetObjectKind().GroupVersionKind()
+	if target, ok := gv.KindForGroupVersionKinds([]schema.GroupVersionKind{gvk}); !ok || target != gvk {
+		return nil, fmt.Errorf("cannot convert %q to another version", gvk)
+	}
+	return in, nil
+}
+
+func (unstructuredConvertor) ConvertFieldLabel(_ schema.GroupVersionKind, label, value string) (string, string, error) {
+	return label, value, nil
+}
+
+// unstructuredDefaulter is a runtime.ObjectDefaulter that applies no defaults.
+type unstructuredDefaulter struct{}
+
+func (unstructuredDefaulter) Default(runtime.Object) {}
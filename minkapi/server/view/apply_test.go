@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	gocmp "github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestApplyObject(t *testing.T) {
+	gvk := typeinfo.ConfigMapsDescriptor.GVK
+	objName := cache.NewObjectName("default", "cm-a")
+	type applyStep struct {
+		opts     mkapi.ApplyOptions
+		data     string
+		wantErr  func(error) bool
+		wantData map[string]string
+		// wantManagers are the field managers recorded in the managedFields after the step.
+		wantManagers []string
+	}
+	tests := map[string]struct {
+		steps []applyStep
+	}{
+		"create and update by same manager": {
+			steps: []applyStep{
+				{
+					opts:         mkapi.ApplyOptions{FieldManager: "m1"},
+					data:         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\ndata:\n  a: '1'\n  b: '2'\n",
+					wantData:     map[string]string{"a": "1", "b": "2"},
+					wantManagers: []string{"m1"},
+				},
+				{
+					// omitting a field owned by the manager removes it.
+					opts:         mkapi.ApplyOptions{FieldManager: "m1"},
+					data:         `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-a"},"data":{"a":"3"}}`,
+					wantData:     map[string]string{"a": "3"},
+					wantManagers: []string{"m1"},
+				},
+			},
+		},
+		"conflict between managers": {
+			steps: []applyStep{
+				{
+					opts:         mkapi.ApplyOptions{FieldManager: "m1"},
+					data:         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\ndata:\n  a: '1'\n",
+					wantData:     map[string]string{"a": "1"},
+					wantManagers: []string{"m1"},
+				},
+				{
+					opts:    mkapi.ApplyOptions{FieldManager: "m2"},
+					data:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\ndata:\n  a: '2'\n",
+					wantErr: apierrors.IsConflict,
+				},
+				{
+					// a field not owned by another manager is shared without conflict.
+					opts:         mkapi.ApplyOptions{FieldManager: "m2"},
+					data:         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\ndata:\n  b: '2'\n",
+					wantData:     map[string]string{"a": "1", "b": "2"},
+					wantManagers: []string{"m1", "m2"},
+				},
+				{
+					opts:         mkapi.ApplyOptions{FieldManager: "m2", Force: true},
+					data:         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\ndata:\n  a: '2'\n  b: '2'\n",
+					wantData:     map[string]string{"a": "2", "b": "2"},
+					wantManagers: []string{"m2"},
+				},
+			},
+		},
+		"missing field manager": {
+			steps: []applyStep{
+				{
+					data:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-a\n",
+					wantErr: apierrors.IsBadRequest,
+				},
+			},
+		},
+		"mismatched kind": {
+			steps: []applyStep{
+				{
+					opts:    mkapi.ApplyOptions{FieldManager: "m1"},
+					data:    "apiVersion: v1\nkind: Secret\nmetadata:\n  name: cm-a\n",
+					wantErr: apierrors.IsBadRequest,
+				},
+			},
+		},
+		"mismatched name": {
+			steps: []applyStep{
+				{
+					opts:    mkapi.ApplyOptions{FieldManager: "m1"},
+					data:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-b\n",
+					wantErr: apierrors.IsBadRequest,
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, s, err := setup(t)
+			if err != nil {
+				return
+			}
+			for _, v := range []mkapi.View{b, s} {
+				b.Reset()
+				s.Reset()
+				for i, step := range tc.steps {
+					_, appliedObj, err := v.ApplyObject(gvk, objName, []byte(step.data), step.opts)
+					if step.wantErr != nil {
+						if !step.wantErr(err) {
+							t.Fatalf("in view %q, step %d: unexpected error: %v", v.GetName(), i, err)
+						}
+						continue
+					}
+					if err != nil {
+						t.Fatalf("in view %q, step %d: failed to apply: %v", v.GetName(), i, err)
+					}
+					cm, ok := appliedObj.(*corev1.ConfigMap)
+					if !ok {
+						t.Fatalf("in view %q, step %d: expected *corev1.ConfigMap, got %T", v.GetName(), i, appliedObj)
+					}
+					if diff := gocmp.Diff(step.wantData, cm.Data); diff != "" {
+						t.Errorf("in view %q, step %d: data mismatch (-want +got):\n%s", v.GetName(), i, diff)
+					}
+					if diff := gocmp.Diff(step.wantManagers, managersOf(cm.ManagedFields)); diff != "" {
+						t.Errorf("in view %q, step %d: field managers mismatch (-want +got):\n%s", v.GetName(), i, diff)
+					}
+					got, err := v.GetObject(gvk, objName)
+					if err != nil {
+						t.Fatalf("in view %q, step %d: failed to get applied object: %v", v.GetName(), i, err)
+					}
+					if diff := gocmp.Diff(step.wantData, got.(*corev1.ConfigMap).Data); diff != "" {
+						t.Errorf("in view %q, step %d: stored data mismatch (-want +got):\n%s", v.GetName(), i, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestApplyUnstructuredObject(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mgr, err := getFieldManager(gvk, "")
+	if err != nil {
+		t.Fatalf("failed to create field manager: %v", err)
+	}
+	liveObj, err := newObject(gvk)
+	if err != nil {
+		t.Fatalf("failed to create object: %v", err)
+	}
+	patchObj, err := decodeApplyConfiguration(gvk, cache.NewObjectName("default", "w"), []byte("apiVersion: example.com/v1\nkind: Widget\nspec:\n  size: 2\n"))
+	if err != nil {
+		t.Fatalf("failed to decode apply configuration: %v", err)
+	}
+	appliedObj, err := mgr.Apply(liveObj, patchObj, "m1", false)
+	if err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	u, ok := appliedObj.(*unstructured.Unstructured)
+	if !ok {
+		t.Fatalf("expected *unstructured.Unstructured, got %T", appliedObj)
+	}
+	size, _, _ := unstructured.NestedInt64(u.Object, "spec", "size")
+	if size != 2 {
+		t.Errorf("expected spec.size 2, got %d", size)
+	}
+	if diff := gocmp.Diff([]string{"m1"}, managersOf(u.GetManagedFields())); diff != "" {
+		t.Errorf("field managers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func managersOf(entries []metav1.ManagedFieldsEntry) (managers []string) {
+	for _, e := range entries {
+		managers = append(managers, e.Manager)
+	}
+	return
+}
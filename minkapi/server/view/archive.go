@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/store"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kjson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// saveView writes a minkapi.ViewArchive of all objects visible through the given view, including its events, and the
+// current resource versions of its stores to the given writer.
+func saveView(v minkapi.View, w io.Writer) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%w: %q: %w", minkapi.ErrSaveView, v.GetName(), err)
+		}
+	}()
+	archive := minkapi.ViewArchive{
+		Version:           minkapi.ViewArchiveVersion,
+		ViewName:          v.GetName(),
+		ViewType:          v.GetType(),
+		CreationTimestamp: metav1.Now(),
+	}
+	customDescriptors, err := CustomResourceDescriptors(v)
+	if err != nil {
+		return
+	}
+	// the stores of custom resources follow that of the CustomResourceDefinitions defining them.
+	for _, d := range slices.Concat(typeinfo.SupportedDescriptors, customDescriptors) {
+		var storeArchive minkapi.StoreArchive
+		storeArchive, err = archiveStore(v, d.GVK)
+		if err != nil {
+			return
+		}
+		archive.Stores = append(archive.Stores, storeArchive)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(archive)
+}
+
+func archiveStore(v minkapi.View, gvk schema.GroupVersionKind) (storeArchive minkapi.StoreArchive, err error) {
+	s, err := v.GetResourceStore(gvk)
+	if err != nil {
+		return
+	}
+	// Read the version before listing so that a concurrent change results in a lower rather than a higher store version.
+	version := s.GetVersionCounter().Load()
+	objs, _, err := v.ListMetaObjects(gvk, minkapi.MatchCriteria{})
+	if err != nil {
+		return
+	}
+	slices.SortFunc(objs, func(a, b metav1.Object) int {
+		return strings.Compare(objutil.CacheName(a).String(), objutil.CacheName(b).String())
+	})
+	storeArchive = minkapi.StoreArchive{
+		APIVersion:      gvk.GroupVersion().String(),
+		Kind:            gvk.Kind,
+		ResourceVersion: version,
+		Objects:         make([]json.RawMessage, 0, len(objs)),
+	}
+	for _, o := range objs {
+		var data []byte
+		data, err = kjson.Marshal(o)
+		if err != nil {
+			err = fmt.Errorf("cannot marshal %s %q: %w", gvk.Kind, objutil.CacheName(o), err)
+			return
+		}
+		storeArchive.Objects = append(storeArchive.Objects, data)
+	}
+	return
+}
+
+// readArchive reads and validates a minkapi.ViewArchive from the given reader.
+func readArchive(r io.Reader) (archive minkapi.ViewArchive, err error) {
+	if err = json.NewDecoder(r).Decode(&archive); err != nil {
+		err = fmt.Errorf("cannot decode view archive: %w", err)
+		return
+	}
+	if archive.Version != minkapi.ViewArchiveVersion {
+		err = fmt.Errorf("unsupported view archive version %q, expected %q", archive.Version, minkapi.ViewArchiveVersion)
+	}
+	return
+}
+
+// restoreStores returns new stores of a view with the given args holding the objects of the given archive. The stores
+// of custom resources are created for the CustomResourceDefinitions held by the archive.
+func restoreStores(log logr.Logger, args *minkapi.ViewArgs, archive minkapi.ViewArchive) (map[schema.GroupVersionKind]*store.InMemResourceStore, error) {
+	stores := make(map[schema.GroupVersionKind]*store.InMemResourceStore, len(typeinfo.SupportedDescriptors))
+	for _, d := range typeinfo.SupportedDescriptors {
+		stores[d.GVK] = createInMemStore(log, d, &atomic.Int64{}, args)
+	}
+	restore := func(storeArchive minkapi.StoreArchive) error {
+		gvk := schema.FromAPIVersionAndKind(storeArchive.APIVersion, storeArchive.Kind)
+		s, exists := stores[gvk]
+		if !exists {
+			return fmt.Errorf("%w: store not found for GVK %q", minkapi.ErrStoreNotFound, gvk)
+		}
+		_, objs, err := decodeStoreObjects(storeArchive)
+		if err != nil {
+			return err
+		}
+		return s.Restore(storeArchive.ResourceVersion, objs)
+	}
+	// custom resources can only be restored once the stores for the restored CustomResourceDefinitions exist.
+	var customStoreArchives []minkapi.StoreArchive
+	for _, storeArchive := range archive.Stores {
+		if !typeinfo.SupportedScheme.Recognizes(schema.FromAPIVersionAndKind(storeArchive.APIVersion, storeArchive.Kind)) {
+			customStoreArchives = append(customStoreArchives, storeArchive)
+			continue
+		}
+		if err := restore(storeArchive); err != nil {
+			return nil, err
+		}
+	}
+	crds, _, err := stores[typeinfo.CustomResourceDefinitionsDescriptor.GVK].ListMetaObjects(minkapi.MatchCriteria{})
+	if err != nil {
+		return nil, err
+	}
+	if err = syncCustomResourceStores(log, args, stores, customResourceDescriptorsOf(crds), newVersionCounter); err != nil {
+		return nil, err
+	}
+	for _, storeArchive := range customStoreArchives {
+		if err = restore(storeArchive); err != nil {
+			return nil, err
+		}
+	}
+	return stores, nil
+}
+
+// decodeStoreObjects decodes the objects held in the given store archive into typed objects of the store GVK, or into
+// unstructured objects for custom resources.
+func decodeStoreObjects(storeArchive minkapi.StoreArchive) (gvk schema.GroupVersionKind, objs []metav1.Object, err error) {
+	gvk = schema.FromAPIVersionAndKind(storeArchive.APIVersion, storeArchive.Kind)
+	for _, data := range storeArchive.Objects {
+		obj, err := newObject(gvk)
+		if err != nil {
+			return gvk, nil, err
+		}
+		if err = kjson.Unmarshal(data, obj); err != nil {
+			return gvk, nil, fmt.Errorf("cannot unmarshal %s in view archive: %w", gvk.Kind, err)
+		}
+		mo, ok := obj.(metav1.Object)
+		if !ok {
+			return gvk, nil, fmt.Errorf("%s in view archive is not a metav1.Object", gvk.Kind)
+		}
+		objutil.SetMetaObjectGVK(mo, gvk)
+		objs = append(objs, mo)
+	}
+	return
+}
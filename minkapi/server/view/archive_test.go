@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/common/testutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	gocmp "github.com/google/go-cmp/cmp"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSaveAndLoadBaseView(t *testing.T) {
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	nA := testNodes[0]
+	if err = storeNode(t, b, &nA); err != nil {
+		return
+	}
+	pA := testPods[0]
+	if err = storePod(t, b, &pA); err != nil {
+		return
+	}
+	ev := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "ev-a", Namespace: pA.Namespace},
+		Reason:     "Scheduled",
+		Note:       "assigned pod",
+	}
+	if _, err = b.GetEventSink().Create(context.Background(), ev); err != nil {
+		t.Fatalf("failed to record event: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = b.Save(&buf); err != nil {
+		t.Fatalf("failed to save view %q: %v", b.GetName(), err)
+	}
+
+	loaded, err := New(log, &baseViewArgs)
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = loaded.Close()
+	})
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatalf("failed to load view %q: %v", loaded.GetName(), err)
+	}
+
+	t.Run("ObjectsRetained", func(t *testing.T) {
+		// timestamps are archived with a precision of seconds.
+		wantNode := nA.DeepCopy()
+		wantNode.CreationTimestamp = wantNode.CreationTimestamp.Rfc3339Copy()
+		checkNodeInViewIsSame(t, loaded, wantNode)
+		gotPod, err := getPod(t, loaded, pA.Namespace, pA.Name)
+		if err != nil {
+			return
+		}
+		if gotPod.ResourceVersion != pA.ResourceVersion {
+			t.Errorf("pod resource version mismatch, want %q, got %q", pA.ResourceVersion, gotPod.ResourceVersion)
+		}
+	})
+
+	t.Run("VersionCountersRetained", func(t *testing.T) {
+		for _, d := range typeinfo.SupportedDescriptors {
+			want, err := b.GetResourceStore(d.GVK)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := loaded.GetResourceStore(d.GVK)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want.GetVersionCounter().Load() != got.GetVersionCounter().Load() {
+				t.Errorf("version counter mismatch for %s, want %d, got %d", d.GVK.Kind, want.GetVersionCounter().Load(), got.GetVersionCounter().Load())
+			}
+		}
+	})
+
+	t.Run("EventsRetained", func(t *testing.T) {
+		gotEvents := loaded.GetEventSink().List()
+		if len(gotEvents) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(gotEvents))
+		}
+		if diff := gocmp.Diff(ev.Note, gotEvents[0].Note); diff != "" {
+			t.Errorf("event mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestSaveSandboxView(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	nA := testNodes[0]
+	if err = storeNode(t, b, &nA); err != nil {
+		return
+	}
+	nB := *nA.DeepCopy()
+	nB.Name = nA.Name + "-b"
+	nB.ResourceVersion = ""
+	if err = storeNode(t, s, &nB); err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = s.Save(&buf); err != nil {
+		t.Fatalf("failed to save view %q: %v", s.GetName(), err)
+	}
+	var archive mkapi.ViewArchive
+	if err = json.Unmarshal(buf.Bytes(), &archive); err != nil {
+		t.Fatalf("failed to decode archive: %v", err)
+	}
+	if archive.ViewType != mkapi.SandboxViewType {
+		t.Errorf("expected view type %q, got %q", mkapi.SandboxViewType, archive.ViewType)
+	}
+	var gotNames []string
+	for _, storeArchive := range archive.Stores {
+		if storeArchive.Kind != typeinfo.NodesDescriptor.GVK.Kind {
+			continue
+		}
+		_, objs, err := decodeStoreObjects(storeArchive)
+		if err != nil {
+			t.Fatalf("failed to decode nodes: %v", err)
+		}
+		for _, o := range objs {
+			gotNames = append(gotNames, objutil.CacheName(o).Name)
+		}
+	}
+	// nodes of the base view must be merged with those of the sandbox.
+	if diff := gocmp.Diff([]string{nA.Name, nB.Name}, gotNames); diff != "" {
+		t.Errorf("archived nodes mismatch (-want +got):\n%s", diff)
+	}
+
+	err = s.Load(bytes.NewReader(buf.Bytes()))
+	testutil.AssertError(t, err, mkapi.ErrLoadView)
+}
+
+func TestLoadInvalidArchive(t *testing.T) {
+	tests := map[string]struct {
+		archive string
+		wantErr error
+	}{
+		"malformed": {
+			archive: "{",
+			wantErr: mkapi.ErrLoadView,
+		},
+		"unsupported version": {
+			archive: `{"version":"v0"}`,
+			wantErr: mkapi.ErrLoadView,
+		},
+		"unknown kind": {
+			archive: `{"version":"v1","stores":[{"apiVersion":"v1","kind":"Unknown","objects":[{}]}]}`,
+			wantErr: mkapi.ErrStoreNotFound,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := New(log, &baseViewArgs)
+			if err != nil {
+				t.Fatalf("failed to create base view: %v", err)
+			}
+			defer func() {
+				_ = b.Close()
+			}()
+			err = b.Load(strings.NewReader(tc.archive))
+			testutil.AssertError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLoadInvalidArchiveLeavesViewUntouched(t *testing.T) {
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	pA := testPods[0]
+	if err = storePod(t, b, &pA); err != nil {
+		return
+	}
+	// the nodes are valid and precede the invalid pod in the archive.
+	archive := `{"version":"v1","stores":[
+		{"apiVersion":"v1","kind":"Node","resourceVersion":1,"objects":[{"metadata":{"name":"n1","resourceVersion":"1"}}]},
+		{"apiVersion":"v1","kind":"Pod","resourceVersion":1,"objects":[{"metadata":{"name":"p1","namespace":"default","resourceVersion":"invalid"}}]}]}`
+	err = b.Load(strings.NewReader(archive))
+	testutil.AssertError(t, err, mkapi.ErrLoadView)
+
+	if _, err = getPod(t, b, pA.Namespace, pA.Name); err != nil {
+		t.Errorf("expected pod %q to be retained, got error: %v", pA.Name, err)
+	}
+	nodes, err := b.ListNodes()
+	if err != nil {
+		t.Fatalf("failed to list nodes: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("expected no nodes to be loaded, got %d", len(nodes))
+	}
+}
+
+func TestLoadRetainsHigherVersionCounter(t *testing.T) {
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, name := range []string{"p1", "p2", "p3"} {
+		p := testPods[0].DeepCopy()
+		p.Name = name
+		if err = storePod(t, b, p); err != nil {
+			return
+		}
+	}
+	s, err := b.GetResourceStore(typeinfo.PodsDescriptor.GVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVersion := s.GetVersionCounter().Load()
+	archive := `{"version":"v1","stores":[
+		{"apiVersion":"v1","kind":"Pod","resourceVersion":1,"objects":[{"metadata":{"name":"p1","namespace":"default","resourceVersion":"1"}}]}]}`
+	if err = b.Load(strings.NewReader(archive)); err != nil {
+		t.Fatalf("failed to load view %q: %v", b.GetName(), err)
+	}
+	// resource versions already observed by clients must not be reused for changes made after loading.
+	if got := s.GetVersionCounter().Load(); got < wantVersion {
+		t.Errorf("expected version counter of pods to be at least %d, got %d", wantVersion, got)
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kjson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ minkapi.AuditSink = (*AuditLog)(nil)
+
+// AuditLog is a minkapi.AuditSink that writes audit entries as JSON lines to a writer. It is safe for concurrent use,
+// so that a single audit log can be shared by several views.
+type AuditLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewAuditLog returns an AuditLog writing to the given writer.
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w, enc: json.NewEncoder(w)}
+}
+
+// Close closes the writer of the audit log if it is an io.Closer.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c, ok := a.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Record writes the given entry as a single JSON line.
+func (a *AuditLog) Record(entry minkapi.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.enc.Encode(entry)
+}
+
+// NewAuditEntry returns the audit entry for a mutation of an object of the given gvk made by the given actor through the
+// given view. The prevObj is the state of the object before the mutation and is nil for a creation. The obj is the state
+// of the object after the mutation and is nil for a deletion that removed the object.
+func NewAuditEntry(v minkapi.View, actor string, verb minkapi.AuditVerb, gvk schema.GroupVersionKind, prevObj, obj runtime.Object) (entry minkapi.AuditEntry, err error) {
+	entry = minkapi.AuditEntry{
+		Timestamp:  metav1.NowMicro(),
+		ViewName:   v.GetName(),
+		Actor:      actor,
+		Verb:       verb,
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+	}
+	var mo metav1.Object
+	if obj != nil {
+		if mo, err = meta.Accessor(obj); err != nil {
+			return
+		}
+		entry.ResourceVersion = mo.GetResourceVersion()
+	} else if mo, err = meta.Accessor(prevObj); err != nil {
+		return
+	}
+	entry.Namespace, entry.Name = mo.GetNamespace(), mo.GetName()
+	switch verb {
+	case minkapi.AuditVerbCreate:
+		entry.Object, err = marshalWithoutResourceVersion(obj)
+	case minkapi.AuditVerbUpdate, minkapi.AuditVerbPatch, minkapi.AuditVerbBind:
+		entry.Diff, err = createMergePatch(prevObj, obj)
+	}
+	return
+}
+
+// createMergePatch returns the JSON merge patch from prevObj to obj, ignoring their resource versions so that the patch
+// can be applied to an object of a different resource version.
+func createMergePatch(prevObj, obj runtime.Object) (json.RawMessage, error) {
+	prevData, err := marshalWithoutResourceVersion(prevObj)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalWithoutResourceVersion(obj)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(prevData, data)
+}
+
+func marshalWithoutResourceVersion(obj runtime.Object) ([]byte, error) {
+	obj = obj.DeepCopyObject()
+	mo, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	mo.SetResourceVersion("")
+	return kjson.Marshal(obj)
+}
+
+// ReplayAuditLog applies the mutations recorded in the audit log read from the given reader to the given view, in the
+// order in which they were recorded, and returns the number of replayed entries. Only the entries of the views with the
+// given viewNames are replayed, or all entries if no viewNames are given.
+//
+// Creations keep the UIDs of the recorded objects so that owner references remain valid. Updates, patches and bindings
+// are replayed as JSON merge patches. Since the garbage collector of the given view removes dependents on its own, the
+// deletion of an object that is no longer found is skipped.
+func ReplayAuditLog(v minkapi.View, r io.Reader, viewNames ...string) (count int, err error) {
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var entry minkapi.AuditEntry
+		if err = dec.Decode(&entry); errors.Is(err, io.EOF) {
+			return count, nil
+		} else if err != nil {
+			return count, fmt.Errorf("%w: cannot decode entry %d: %w", minkapi.ErrReplayAuditLog, n, err)
+		}
+		if len(viewNames) > 0 && !slices.Contains(viewNames, entry.ViewName) {
+			continue
+		}
+		if err = replayAuditEntry(v, entry); err != nil {
+			return count, fmt.Errorf("%w: entry %d to %s %s %q: %w", minkapi.ErrReplayAuditLog, n, entry.Verb, entry.Kind,
+				cache.NewObjectName(entry.Namespace, entry.Name), err)
+		}
+		count++
+	}
+}
+
+func replayAuditEntry(v minkapi.View, entry minkapi.AuditEntry) error {
+	gvk := schema.FromAPIVersionAndKind(entry.APIVersion, entry.Kind)
+	objName := cache.NewObjectName(entry.Namespace, entry.Name)
+	switch entry.Verb {
+	case minkapi.AuditVerbCreate:
+		obj, err := newObject(gvk)
+		if err != nil {
+			return err
+		}
+		if err = kjson.Unmarshal(entry.Object, obj); err != nil {
+			return err
+		}
+		mo, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		objutil.SetMetaObjectGVK(mo, gvk)
+		return v.CreateObject(gvk, mo)
+	case minkapi.AuditVerbUpdate, minkapi.AuditVerbPatch, minkapi.AuditVerbBind:
+		_, _, err := v.PatchObject(gvk, objName, types.MergePatchType, entry.Diff, minkapi.PatchOptions{})
+		return err
+	case minkapi.AuditVerbDelete:
+		err := v.DeleteObject(gvk, objName, minkapi.DeleteOptions{PropagationPolicy: entry.PropagationPolicy, GracePeriodSeconds: entry.GracePeriodSeconds})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("unsupported verb %q", entry.Verb)
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/common/testutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+func TestReplayAuditLog(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	auditLog := NewAuditLog(&buf)
+
+	baseNode := testNodes[0].DeepCopy()
+	if err = storeNode(t, b, baseNode); err != nil {
+		return
+	}
+	recordEntry(t, auditLog, b, mkapi.AuditVerbCreate, typeinfo.NodesDescriptor.GVK, nil, baseNode)
+
+	n := testNodes[0].DeepCopy()
+	n.Name += "-sandbox"
+	n.ResourceVersion = ""
+	if err = storeNode(t, s, n); err != nil {
+		return
+	}
+	recordEntry(t, auditLog, s, mkapi.AuditVerbCreate, typeinfo.NodesDescriptor.GVK, nil, n)
+	pods := make([]*corev1.Pod, 2)
+	for i := range pods {
+		pods[i] = testPods[0].DeepCopy()
+		pods[i].Name = fmt.Sprintf("%s-%d", pods[i].Name, i)
+		pods[i].UID = uuid.NewUUID()
+		pods[i].ResourceVersion = ""
+		if err = storePod(t, s, pods[i]); err != nil {
+			return
+		}
+		recordEntry(t, auditLog, s, mkapi.AuditVerbCreate, typeinfo.PodsDescriptor.GVK, nil, pods[i])
+	}
+
+	_, patchedPod, err := s.PatchObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(pods[0]), types.MergePatchType, []byte(`{"metadata":{"labels":{"audited":"true"}}}`), mkapi.PatchOptions{})
+	if err != nil {
+		t.Fatalf("failed to patch pod: %v", err)
+	}
+	recordEntry(t, auditLog, s, mkapi.AuditVerbPatch, typeinfo.PodsDescriptor.GVK, pods[0], patchedPod)
+	boundPod, err := updateBinding(t, s, patchedPod.(*corev1.Pod), n)
+	if err != nil {
+		return
+	}
+	recordEntry(t, auditLog, s, mkapi.AuditVerbBind, typeinfo.PodsDescriptor.GVK, patchedPod, boundPod)
+	if err = s.DeleteObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(pods[1]), mkapi.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	recordEntry(t, auditLog, s, mkapi.AuditVerbDelete, typeinfo.PodsDescriptor.GVK, pods[1], nil)
+
+	replayed, err := New(log, &baseViewArgs)
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = replayed.Close()
+	})
+	count, err := ReplayAuditLog(replayed, &buf, s.GetName())
+	if err != nil {
+		t.Fatalf("failed to replay audit log: %v", err)
+	}
+	if count != 6 {
+		t.Errorf("expected 6 replayed entries, got %d", count)
+	}
+
+	if _, err = getNode(t, replayed, baseNode.Name); !apierrors.IsNotFound(err) {
+		t.Errorf("expected node %q of the base view not to be replayed, got %v", baseNode.Name, err)
+	}
+	if _, err = getNode(t, replayed, n.Name); err != nil {
+		t.Errorf("expected node %q to be replayed: %v", n.Name, err)
+	}
+	got, err := getPod(t, replayed, pods[0].Namespace, pods[0].Name)
+	if err != nil {
+		t.Fatalf("expected pod %q to be replayed: %v", pods[0].Name, err)
+	}
+	if got.UID != pods[0].UID || got.Labels["audited"] != "true" || got.Spec.NodeName != n.Name {
+		t.Errorf("expected replayed pod with UID %q, label audited=true and node %q, got UID %q, labels %v and node %q",
+			pods[0].UID, n.Name, got.UID, got.Labels, got.Spec.NodeName)
+	}
+	if _, err = getPod(t, replayed, pods[1].Namespace, pods[1].Name); !apierrors.IsNotFound(err) {
+		t.Errorf("expected deleted pod %q not to be found, got %v", pods[1].Name, err)
+	}
+}
+
+func TestReplayDeletionWithGracePeriod(t *testing.T) {
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	p := testPods[0].DeepCopy()
+	p.ResourceVersion = ""
+	if err = storePod(t, b, p); err != nil {
+		return
+	}
+	auditLog := fmt.Sprintf(`{"viewName":"base","verb":"delete","apiVersion":"v1","kind":"Pod","namespace":%q,"name":%q,"gracePeriodSeconds":60}`, p.Namespace, p.Name)
+	if _, err = ReplayAuditLog(b, strings.NewReader(auditLog)); err != nil {
+		t.Fatalf("failed to replay audit log: %v", err)
+	}
+	got, err := getPod(t, b, p.Namespace, p.Name)
+	if err != nil {
+		t.Fatalf("expected pod %q to be pending deletion: %v", p.Name, err)
+	}
+	if got.DeletionTimestamp == nil || got.DeletionGracePeriodSeconds == nil || *got.DeletionGracePeriodSeconds != 60 {
+		t.Errorf("expected pod to be marked for deletion with a grace period of 60s, got deletionTimestamp %v and grace period %v",
+			got.DeletionTimestamp, got.DeletionGracePeriodSeconds)
+	}
+}
+
+func TestReplayInvalidAuditLog(t *testing.T) {
+	tests := map[string]struct {
+		auditLog string
+	}{
+		"malformed": {
+			auditLog: "{",
+		},
+		"unsupported verb": {
+			auditLog: `{"viewName":"base","verb":"get","apiVersion":"v1","kind":"Pod","namespace":"default","name":"a"}`,
+		},
+		"patch of missing object": {
+			auditLog: `{"viewName":"base","verb":"patch","apiVersion":"v1","kind":"Pod","namespace":"default","name":"a","diff":{}}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := New(log, &baseViewArgs)
+			if err != nil {
+				t.Fatalf("failed to create base view: %v", err)
+			}
+			defer func() {
+				_ = b.Close()
+			}()
+			_, err = ReplayAuditLog(b, strings.NewReader(tc.auditLog))
+			testutil.AssertError(t, err, mkapi.ErrReplayAuditLog)
+		})
+	}
+}
+
+func recordEntry(t *testing.T, sink mkapi.AuditSink, v mkapi.View, verb mkapi.AuditVerb, gvk schema.GroupVersionKind, prevObj, obj runtime.Object) {
+	t.Helper()
+	entry, err := NewAuditEntry(v, "test", verb, gvk, prevObj, obj)
+	if err != nil {
+		t.Fatalf("failed to create audit entry: %v", err)
+	}
+	if err = sink.Record(entry); err != nil {
+		t.Fatalf("failed to record audit entry: %v", err)
+	}
+}
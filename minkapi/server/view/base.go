@@ -11,19 +11,20 @@ import (
 	commontypes "github.com/gardener/scaling-advisor/api/common/types"
 	"github.com/gardener/scaling-advisor/api/minkapi"
 	"github.com/gardener/scaling-advisor/common/clientutil"
+	"io"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/gardener/scaling-advisor/common/objutil"
 	"github.com/gardener/scaling-advisor/common/podutil"
 
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/gardener/scaling-advisor/minkapi/server/eventsink"
 	"github.com/gardener/scaling-advisor/minkapi/server/store"
@@ -38,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/clock"
 )
 
 var (
@@ -52,6 +54,7 @@ type baseView struct {
 	stores      map[schema.GroupVersionKind]*store.InMemResourceStore
 	eventSink   minkapi.EventSink
 	changeCount atomic.Int64
+	gc          *garbageCollector
 }
 
 func New(log logr.Logger, args *minkapi.ViewArgs) (minkapi.View, error) {
@@ -60,29 +63,67 @@ func New(log logr.Logger, args *minkapi.ViewArgs) (minkapi.View, error) {
 		versionCounter := &atomic.Int64{}
 		stores[d.GVK] = createInMemStore(log, d, versionCounter, args)
 	}
-	eventSink := eventsink.New(log)
-	return &baseView{
+	v := &baseView{
 		log:       log,
 		args:      args,
 		stores:    stores,
-		eventSink: eventSink,
+		eventSink: newEventSink(log, args, stores),
 		mu:        &sync.RWMutex{},
-	}, nil
+	}
+	v.gc = newGarbageCollector(log, v)
+	v.gc.watchStores(stores)
+	return v, nil
 }
 
 func (v *baseView) Reset() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	resetStores(v.stores)
+	if err := syncCustomResourceStores(v.log, v.args, v.stores, nil, newVersionCounter); err != nil {
+		v.log.Error(err, "failed to remove custom resource stores", "view", v.args.Name)
+	}
+	v.gc.restartWatches(v.stores)
 	v.eventSink.Reset()
 }
 
 func (v *baseView) Close() error {
+	v.gc.stop()
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 	return closeStores(v.stores)
 }
 
+func (v *baseView) Save(w io.Writer) error {
+	return saveView(v, w)
+}
+
+func (v *baseView) Load(r io.Reader) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%w: %q: %w", minkapi.ErrLoadView, v.args.Name, err)
+		}
+	}()
+	archive, err := readArchive(r)
+	if err != nil {
+		return
+	}
+	// the archive is restored into new stores first so that the view is left untouched if any of its objects is invalid.
+	stores, err := restoreStores(v.log, v.args, archive)
+	if err != nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	err = replaceStores(v.stores, stores)
+	v.gc.restartWatches(v.stores)
+	v.eventSink.Reset()
+	if err != nil {
+		return
+	}
+	v.log.Info("loaded view from archive", "name", v.args.Name, "archivedView", archive.ViewName, "archivedAt", archive.CreationTimestamp)
+	return
+}
+
 func (v *baseView) GetName() string {
 	return v.args.Name
 }
@@ -91,6 +132,10 @@ func (v *baseView) GetType() minkapi.ViewType {
 	return minkapi.BaseViewType
 }
 
+func (v *baseView) GetClock() clock.WithTickerAndDelayedExecution {
+	return viewClock(v.args)
+}
+
 func (v *baseView) GetObjectChangeCount() int64 {
 	return v.changeCount.Load()
 }
@@ -109,6 +154,10 @@ func (v *baseView) GetEventSink() minkapi.EventSink {
 	return v.eventSink
 }
 
+func (v *baseView) GetAuditSink() minkapi.AuditSink {
+	return v.args.AuditSink
+}
+
 func (v *baseView) GetResourceStore(gvk schema.GroupVersionKind) (minkapi.ResourceStore, error) {
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -120,7 +169,15 @@ func (v *baseView) GetResourceStore(gvk schema.GroupVersionKind) (minkapi.Resour
 }
 
 func (v *baseView) CreateObject(gvk schema.GroupVersionKind, obj metav1.Object) error {
-	return storeObject(v, gvk, obj, &v.changeCount)
+	if gvk == typeinfo.CustomResourceDefinitionsDescriptor.GVK {
+		if err := prepareCustomResourceDefinition(obj); err != nil {
+			return err
+		}
+	}
+	if err := storeObject(v, gvk, obj, &v.changeCount); err != nil {
+		return err
+	}
+	return v.syncCustomResourceStores(gvk)
 }
 
 func (v *baseView) GetObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (obj runtime.Object, err error) {
@@ -134,27 +191,63 @@ func (v *baseView) GetObject(gvk schema.GroupVersionKind, objName cache.ObjectNa
 }
 
 func (v *baseView) UpdateObject(gvk schema.GroupVersionKind, obj metav1.Object) error {
-	return updateObject(v, gvk, obj, &v.changeCount)
+	if gvk == typeinfo.CustomResourceDefinitionsDescriptor.GVK {
+		if err := prepareCustomResourceDefinition(obj); err != nil {
+			return err
+		}
+	}
+	if err := updateObject(v, gvk, obj, &v.changeCount); err != nil {
+		return err
+	}
+	return v.syncCustomResourceStores(gvk)
+}
+
+// syncCustomResourceStores creates and removes the stores of custom resources following a change of objects of the
+// given gvk, if these are CustomResourceDefinitions.
+func (v *baseView) syncCustomResourceStores(gvk schema.GroupVersionKind) error {
+	if gvk != typeinfo.CustomResourceDefinitionsDescriptor.GVK {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.syncCustomResourceStoresLocked()
 }
 
-func (v *baseView) UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (*corev1.Pod, error) {
+// syncCustomResourceStoresLocked is syncCustomResourceStores for callers already holding the write lock of the view.
+func (v *baseView) syncCustomResourceStoresLocked() error {
+	crds, _, err := v.stores[typeinfo.CustomResourceDefinitionsDescriptor.GVK].ListMetaObjects(minkapi.MatchCriteria{})
+	if err != nil {
+		return err
+	}
+	err = syncCustomResourceStores(v.log, v.args, v.stores, customResourceDescriptorsOf(crds), newVersionCounter)
+	v.gc.watchStores(v.stores)
+	return err
+}
+
+func (v *baseView) UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (prevPod, pod *corev1.Pod, err error) {
 	obj, err := v.GetObject(typeinfo.PodsDescriptor.GVK, podName)
 	if err != nil {
-		return nil, err
+		return
 	}
-	pod, ok := obj.(*corev1.Pod)
+	prevPod, ok := obj.(*corev1.Pod)
 	if !ok {
-		return nil, fmt.Errorf("%w: cannot update pod node binding since obj %T for name %q not a corev1.Pod", minkapi.ErrUpdateObject, obj, podName)
+		err = fmt.Errorf("%w: cannot update pod node binding since obj %T for name %q not a corev1.Pod", minkapi.ErrUpdateObject, obj, podName)
+		return
 	}
-	return updatePodNodeBinding(v, pod, binding)
+	pod, err = updatePodNodeBinding(v, prevPod.DeepCopy(), binding)
+	return
 }
 
-func (v *baseView) PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte) (patchedObj runtime.Object, err error) {
-	return patchObject(v, gvk, objName, patchType, patchData)
+func (v *baseView) PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return patchObject(v, gvk, objName, patchType, patchData, opts)
 }
 
-func (v *baseView) PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchData []byte) (patchedObj runtime.Object, err error) {
-	return patchObjectStatus(v, gvk, objName, patchData)
+func (v *baseView) ApplyObject(gvk schema.GroupVersionKind, objName cache.ObjectName, applyData []byte, opts minkapi.ApplyOptions) (prevObj, appliedObj runtime.Object, err error) {
+	return applyObject(v, gvk, objName, applyData, opts)
+}
+
+func (v *baseView) PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return patchObjectStatus(v, gvk, objName, patchType, patchData, opts)
 }
 
 func (v *baseView) ListMetaObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria) ([]metav1.Object, int64, error) {
@@ -173,29 +266,33 @@ func (v *baseView) ListObjects(gvk schema.GroupVersionKind, criteria minkapi.Mat
 	return listObj, nil
 }
 
-func (v *baseView) WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, startVersion int64, namespace string, labelSelector labels.Selector, eventCallback minkapi.WatchEventCallback) error {
+func (v *baseView) WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, opts minkapi.WatchOptions, eventCallback minkapi.WatchEventCallback) error {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
 		return err
 	}
-	return s.Watch(ctx, startVersion, namespace, labelSelector, eventCallback)
+	return s.Watch(ctx, opts, eventCallback)
 }
 
-func (v *baseView) DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName) error {
+func (v *baseView) DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName, opts minkapi.DeleteOptions) error {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
 		return err
 	}
-	err = s.Delete(objName)
+	err = s.Delete(objName, opts)
 	if err != nil {
 		return err
 	}
 	v.changeCount.Add(1)
-	return nil
+	return v.syncCustomResourceStores(gvk)
 }
 
-func (v *baseView) DeleteObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria) error {
-	return deleteObjects(v, gvk, criteria, &v.changeCount)
+func (v *baseView) DeleteObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, opts minkapi.DeleteOptions) (delObjs []metav1.Object, err error) {
+	if delObjs, err = deleteObjects(v, gvk, criteria, opts, &v.changeCount); err != nil {
+		return
+	}
+	err = v.syncCustomResourceStores(gvk)
+	return
 }
 
 func (v *baseView) ListNodes(matchingNodeNames ...string) (nodes []corev1.Node, err error) {
@@ -228,27 +325,8 @@ func (v *baseView) ListPods(namespace string, matchingPodNames ...string) ([]cor
 	return pods, nil
 }
 
-func (v *baseView) ListEvents(namespace string) ([]eventsv1.Event, error) {
-	if len(strings.TrimSpace(namespace)) == 0 {
-		return nil, apierrors.NewBadRequest("cannot list events without namespace")
-	}
-	c := minkapi.MatchCriteria{
-		Namespace: namespace,
-	}
-	gvk := typeinfo.EventsDescriptor.GVK
-	s, err := v.GetResourceStore(gvk)
-	if err != nil {
-		return nil, err
-	}
-	objs, _, err := s.ListMetaObjects(c)
-	if err != nil {
-		return nil, err
-	}
-	events := make([]eventsv1.Event, 0, len(objs))
-	for _, obj := range objs {
-		events = append(events, *obj.(*eventsv1.Event))
-	}
-	return events, nil
+func (v *baseView) ListEvents(namespace string, regarding ...corev1.ObjectReference) ([]eventsv1.Event, error) {
+	return listEvents(v, namespace, regarding)
 }
 
 func (v *baseView) GetKubeConfigPath() string {
@@ -273,7 +351,7 @@ func storeObject(v minkapi.View, gvk schema.GroupVersionKind, obj metav1.Object,
 
 	createTimestamp := obj.GetCreationTimestamp()
 	if (&createTimestamp).IsZero() { // only set creationTimestamp if not already set.
-		obj.SetCreationTimestamp(metav1.Time{Time: time.Now()})
+		obj.SetCreationTimestamp(metav1.Time{Time: v.GetClock().Now()})
 	}
 
 	if obj.GetUID() == "" {
@@ -302,7 +380,19 @@ func updateObject(v minkapi.View, gvk schema.GroupVersionKind, obj metav1.Object
 	changeCount.Add(1)
 	return nil
 }
+
+// updatePodNodeBinding binds the given pod to the target node of the given binding. Like the kube-apiserver, binding a
+// pod that is already bound or whose UID differs from that of the binding fails with a 409 Conflict error. Since the
+// update is conditional on the resource version of the given pod, it fails with a 409 Conflict error as well if the
+// stored pod has changed in the meantime.
 func updatePodNodeBinding(v minkapi.View, pod *corev1.Pod, binding corev1.Binding) (*corev1.Pod, error) {
+	podGR := corev1.Resource(typeinfo.PodsDescriptor.GVR.Resource)
+	if binding.UID != "" && binding.UID != pod.UID {
+		return nil, apierrors.NewConflict(podGR, pod.Name, fmt.Errorf("precondition failed: UID in precondition: %v, UID in object meta: %v", binding.UID, pod.UID))
+	}
+	if pod.Spec.NodeName != "" {
+		return nil, apierrors.NewConflict(podGR, pod.Name, fmt.Errorf("pod %v is already assigned to node %q", pod.Name, pod.Spec.NodeName))
+	}
 	pod.Spec.NodeName = binding.Target.Name
 	podutil.UpdatePodCondition(&pod.Status, &corev1.PodCondition{
 		Type:   corev1.PodScheduled,
@@ -315,69 +405,83 @@ func updatePodNodeBinding(v minkapi.View, pod *corev1.Pod, binding corev1.Bindin
 	return pod, nil
 }
 
-func patchObject(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte) (patchedObj runtime.Object, err error) {
-	obj, err := v.GetObject(gvk, objName)
-	if err != nil {
-		return
-	}
-	err = objutil.PatchObject(obj, objName, patchType, patchData)
-	if err != nil {
-		err = fmt.Errorf("failed to patch object %q: %w", objName, err)
-		return
-	}
-	mo, err := meta.Accessor(obj)
-	if err != nil {
-		err = fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err)
-		return
-	}
-	if mo.GetName() != objName.Name {
-		fieldErr := field.Error{
-			Type:     field.ErrorTypeInvalid,
-			BadValue: mo.GetName(),
-			Field:    "metadata.name",
-			Detail:   fmt.Sprintf("Invalid value: %q: field is immutable", mo.GetName()),
+func patchObject(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return updatePatchedObject(v, gvk, objName, "", opts, func(obj runtime.Object) error {
+		if err := objutil.PatchObject(obj, objName, patchType, patchData); err != nil {
+			return fmt.Errorf("failed to patch object %q: %w", objName, err)
 		}
-		err = apierrors.NewInvalid(gvk.GroupKind(), objName.Name, field.ErrorList{&fieldErr})
-		return
-	}
-	if mo.GetNamespace() != objName.Namespace {
-		fieldErr := field.Error{
-			Type:     field.ErrorTypeInvalid,
-			Field:    "metadata.namespace",
-			BadValue: mo.GetNamespace(),
-			Detail:   fmt.Sprintf("Invalid value: %q: field is immutable", mo.GetNamespace()),
+		mo, err := meta.Accessor(obj)
+		if err != nil {
+			return fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err)
 		}
-		err = apierrors.NewInvalid(gvk.GroupKind(), objName.Name, field.ErrorList{&fieldErr})
-		return
-	}
+		if mo.GetName() != objName.Name {
+			fieldErr := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				BadValue: mo.GetName(),
+				Field:    "metadata.name",
+				Detail:   fmt.Sprintf("Invalid value: %q: field is immutable", mo.GetName()),
+			}
+			return apierrors.NewInvalid(gvk.GroupKind(), objName.Name, field.ErrorList{&fieldErr})
+		}
+		if mo.GetNamespace() != objName.Namespace {
+			fieldErr := field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "metadata.namespace",
+				BadValue: mo.GetNamespace(),
+				Detail:   fmt.Sprintf("Invalid value: %q: field is immutable", mo.GetNamespace()),
+			}
+			return apierrors.NewInvalid(gvk.GroupKind(), objName.Name, field.ErrorList{&fieldErr})
+		}
+		return nil
+	})
+}
 
-	err = v.UpdateObject(gvk, mo)
-	if err != nil {
-		return
-	}
-	patchedObj = obj
-	return
+func patchObjectStatus(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return updatePatchedObject(v, gvk, objName, "status", opts, func(obj runtime.Object) error {
+		if err := objutil.PatchObjectStatus(obj, objName, patchType, patchData); err != nil {
+			return fmt.Errorf("failed to patch object status of %q: %w", objName, err)
+		}
+		return nil
+	})
 }
-func patchObjectStatus(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, patchData []byte) (patchedObj runtime.Object, err error) {
-	obj, err := v.GetObject(gvk, objName)
-	if err != nil {
-		return
-	}
-	err = objutil.PatchObjectStatus(obj, objName, patchData)
-	if err != nil {
-		err = fmt.Errorf("failed to patch object status of %q: %w", objName, err)
-		return
-	}
-	mo, err := meta.Accessor(obj)
-	if err != nil {
-		err = fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err)
-		return
-	}
-	err = v.UpdateObject(gvk, mo)
-	if err != nil {
-		return
-	}
-	patchedObj = obj
+
+// updatePatchedObject updates the object with the given name after applying the given patch function to it. Like the
+// kube-apiserver, a patch that does not set the resourceVersion is applied unconditionally, being re-applied to the
+// latest object if a concurrent write conflicts with it. A patch that sets the resourceVersion fails with a 409
+// Conflict error unless it matches that of the stored object. Since the update is conditional on the resource version of
+// the object the patch was applied to, the returned prevObj is the object as it was stored right before the update.
+// The patch is recorded for the field manager of the given opts in the managedFields of the given subresource.
+func updatePatchedObject(v minkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName, subresource string, opts minkapi.PatchOptions, patchFn func(obj runtime.Object) error) (prevObj, patchedObj runtime.Object, err error) {
+	var preconditioned bool
+	err = retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) && !preconditioned
+	}, func() error {
+		obj, err := v.GetObject(gvk, objName)
+		if err != nil {
+			return err
+		}
+		mo, err := meta.Accessor(obj)
+		if err != nil {
+			return fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err)
+		}
+		storedVersion := mo.GetResourceVersion()
+		storedObj := obj.DeepCopyObject()
+		if err = patchFn(obj); err != nil {
+			return err
+		}
+		// a patch setting the resourceVersion to that of the stored object is not detected, but conflicts on retry.
+		preconditioned = mo.GetResourceVersion() != storedVersion
+		if opts.FieldManager != "" {
+			if err = RecordUpdate(gvk, subresource, storedObj, obj, opts.FieldManager); err != nil {
+				return err
+			}
+		}
+		if err = v.UpdateObject(gvk, mo); err != nil {
+			return err
+		}
+		prevObj, patchedObj = storedObj, obj
+		return nil
+	})
 	return
 }
 
@@ -389,17 +493,14 @@ func listMetaObjects(v minkapi.View, gvk schema.GroupVersionKind, criteria minka
 	return s.ListMetaObjects(criteria)
 }
 
-func deleteObjects(v minkapi.View, gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, changeCount *atomic.Int64) error {
+func deleteObjects(v minkapi.View, gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, opts minkapi.DeleteOptions, changeCount *atomic.Int64) (delObjs []metav1.Object, err error) {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
-		return err
-	}
-	delCount, err := s.DeleteObjects(criteria)
-	if err != nil {
-		return err
+		return
 	}
-	changeCount.Add(int64(delCount))
-	return nil
+	delObjs, err = s.DeleteObjects(criteria, opts)
+	changeCount.Add(int64(len(delObjs)))
+	return
 }
 
 func listNodes(v minkapi.View, matchingNodeNames []string) (nodes []corev1.Node, maxVersion int64, err error) {
@@ -460,6 +561,55 @@ func asPods(metaObjects []metav1.Object) (pods []corev1.Pod, maxVersion int64, e
 	return
 }
 
+// listEvents returns the events of the given namespace in the events.k8s.io/v1 and core/v1 event stores of the given
+// view, which hold the unexpired events of its event sink, restricted to the events regarding any of the given objects
+// if any. Like ListPods of the base view, it returns a 400 BadRequest error if no namespace is given.
+func listEvents(v minkapi.View, namespace string, regarding []corev1.ObjectReference) ([]eventsv1.Event, error) {
+	if len(strings.TrimSpace(namespace)) == 0 {
+		return nil, apierrors.NewBadRequest("cannot list events without namespace")
+	}
+	v.GetEventSink().RemoveExpired()
+	c := minkapi.MatchCriteria{Namespace: namespace}
+	metaObjs, _, err := v.ListMetaObjects(typeinfo.EventsDescriptor.GVK, c)
+	if err != nil {
+		return nil, err
+	}
+	events, _, err := asEvents(metaObjs)
+	if err != nil {
+		return nil, err
+	}
+	coreMetaObjs, _, err := v.ListMetaObjects(typeinfo.CoreEventsDescriptor.GVK, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range coreMetaObjs {
+		e, ok := obj.(*corev1.Event)
+		if !ok {
+			return nil, fmt.Errorf("object %q is not a corev1.Event", objutil.CacheName(obj))
+		}
+		events = append(events, *eventsink.FromCoreV1(e))
+	}
+	if len(regarding) > 0 {
+		events = slices.DeleteFunc(events, func(e eventsv1.Event) bool {
+			return !slices.ContainsFunc(regarding, func(ref corev1.ObjectReference) bool { return isRegarding(&e, ref) })
+		})
+	}
+	slices.SortStableFunc(events, func(a, b eventsv1.Event) int {
+		return eventsink.LastObservedTime(&a).Compare(eventsink.LastObservedTime(&b).Time)
+	})
+	return events, nil
+}
+
+// isRegarding returns whether the given event regards the object with the given reference, matching by kind and name,
+// and also by namespace, UID and API version if set in the reference.
+func isRegarding(e *eventsv1.Event, ref corev1.ObjectReference) bool {
+	r := e.Regarding
+	return r.Kind == ref.Kind && r.Name == ref.Name &&
+		(ref.Namespace == "" || r.Namespace == ref.Namespace) &&
+		(ref.UID == "" || r.UID == ref.UID) &&
+		(ref.APIVersion == "" || r.APIVersion == ref.APIVersion)
+}
+
 func asEvents(metaObjects []metav1.Object) (events []eventsv1.Event, maxVersion int64, err error) {
 	events = make([]eventsv1.Event, 0, len(metaObjects))
 	var version int64
@@ -483,6 +633,10 @@ func asEvents(metaObjects []metav1.Object) (events []eventsv1.Event, maxVersion
 
 // combinePrimarySecondary gets a combined slice of metav1.Objects preferring objects in primary over same obj in secondary
 func combinePrimarySecondary(primary []metav1.Object, secondary []metav1.Object) (combined []metav1.Object) {
+	if len(primary) == 0 {
+		return secondary
+	}
+	combined = make([]metav1.Object, 0, len(primary)+len(secondary))
 	found := make(map[cache.ObjectName]bool, len(primary))
 	for _, o := range primary {
 		found[objutil.CacheName(o)] = true
@@ -499,16 +653,35 @@ func combinePrimarySecondary(primary []metav1.Object, secondary []metav1.Object)
 	return
 }
 
+// newEventSink creates the event sink of a view with the given args, persisting its events in the given stores.
+func newEventSink(log logr.Logger, args *minkapi.ViewArgs, stores map[schema.GroupVersionKind]*store.InMemResourceStore) minkapi.EventSink {
+	return eventsink.New(log, args.EventConfig, viewClock(args), eventsink.Stores{
+		Events:     stores[typeinfo.EventsDescriptor.GVK],
+		CoreEvents: stores[typeinfo.CoreEventsDescriptor.GVK],
+	})
+}
+
 func createInMemStore(log logr.Logger, d typeinfo.Descriptor, versionCounter *atomic.Int64, args *minkapi.ViewArgs) *store.InMemResourceStore {
 	return store.NewInMemResourceStore(log, &minkapi.ResourceStoreArgs{
-		Name:           d.GVR.Resource,
-		ObjectGVK:      d.GVK,
-		ObjectListGVK:  d.ListGVK,
-		Scheme:         typeinfo.SupportedScheme,
-		VersionCounter: versionCounter,
-		WatchConfig:    args.WatchConfig,
+		Name:             d.GVR.Resource,
+		ObjectGVK:        d.GVK,
+		ObjectListGVK:    d.ListGVK,
+		Scheme:           typeinfo.SupportedScheme,
+		VersionCounter:   versionCounter,
+		WatchConfig:      args.WatchConfig,
+		SelectableFields: d.SelectableFields,
+		Indexes:          d.IndexesWithLabelKeys(args.IndexedLabelKeys...),
+		Clock:            args.Clock,
 	})
 }
+
+// viewClock returns the clock of a view with the given args, which defaults to the real clock.
+func viewClock(args *minkapi.ViewArgs) clock.WithTickerAndDelayedExecution {
+	if args.Clock == nil {
+		return clock.RealClock{}
+	}
+	return args.Clock
+}
 func closeStores(stores map[schema.GroupVersionKind]*store.InMemResourceStore) error {
 	var errs []error
 	for _, s := range stores {
@@ -517,6 +690,34 @@ func closeStores(stores map[schema.GroupVersionKind]*store.InMemResourceStore) e
 	return errors.Join(errs...)
 }
 
+// replaceStores replaces the objects of the given stores of a view with those of the given restored stores, which are
+// added to the view for custom resources it does not define yet. The stores of the view are retained rather than
+// swapped for the restored ones since its event sink and the sandbox views based on it hold them or their version
+// counters. Stores of custom resources that are not defined by the restored stores are closed and removed.
+func replaceStores(stores, restoredStores map[schema.GroupVersionKind]*store.InMemResourceStore) error {
+	var errs []error
+	for gvk, s := range stores {
+		if _, ok := restoredStores[gvk]; !ok {
+			errs = append(errs, s.Close())
+			delete(stores, gvk)
+		}
+	}
+	for gvk, restored := range restoredStores {
+		s, ok := stores[gvk]
+		if !ok {
+			stores[gvk] = restored
+			continue
+		}
+		objs, _, err := restored.ListMetaObjects(minkapi.MatchCriteria{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, s.Restore(restored.CurrentResourceVersion(), objs))
+	}
+	return errors.Join(errs...)
+}
+
 func resetStores(stores map[schema.GroupVersionKind]*store.InMemResourceStore) {
 	for _, s := range stores {
 		s.Reset()
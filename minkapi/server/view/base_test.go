@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
+	testingclock "k8s.io/utils/clock/testing"
 )
 
 func TestNodeCreation(t *testing.T) {
@@ -179,7 +180,7 @@ func TestEventDeletion(t *testing.T) {
 			t.Logf("Number of Events before deletion is %d", len(events))
 
 			t.Logf("Deleting Event")
-			err = baseView.DeleteObjects(tc.gvk, tc.c)
+			_, err = baseView.DeleteObjects(tc.gvk, tc.c, minkapi.DeleteOptions{})
 			if err != nil {
 				testutil.AssertError(t, err, tc.retErr)
 				return
@@ -195,6 +196,122 @@ func TestEventDeletion(t *testing.T) {
 	}
 }
 
+func TestCreationTimestampFromClock(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	baseView, err := New(logr.Discard(), &minkapi.ViewArgs{
+		Name:        minkapi.DefaultBasePrefix,
+		Scheme:      typeinfo.SupportedScheme,
+		WatchConfig: minkapi.WatchConfig{QueueSize: 100, Timeout: time.Minute},
+		Clock:       fakeClock,
+	})
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() { _ = baseView.Close() })
+	sandboxView, err := NewSandbox(logr.Discard(), baseView, &minkapi.ViewArgs{
+		Name:        "sandbox",
+		Scheme:      typeinfo.SupportedScheme,
+		WatchConfig: minkapi.WatchConfig{QueueSize: 100, Timeout: time.Minute},
+		Clock:       baseView.GetClock(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create sandbox view: %v", err)
+	}
+	t.Cleanup(func() { _ = sandboxView.Close() })
+
+	for _, v := range []minkapi.View{baseView, sandboxView} {
+		fakeClock.Step(time.Hour)
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + v.GetName()}}
+		if err = v.CreateObject(typeinfo.NodesDescriptor.GVK, node); err != nil {
+			t.Fatalf("failed to create node in view %q: %v", v.GetName(), err)
+		}
+		if want := fakeClock.Now(); !node.CreationTimestamp.Time.Equal(want) {
+			t.Errorf("expected creationTimestamp %v of node in view %q, got %v", want, v.GetName(), node.CreationTimestamp.Time)
+		}
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	baseView, err := createBaseView(t)
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() { _ = baseView.Close() })
+	if _, err = createObjectFromFileName[eventsv1.Event](t, baseView, "../testdata/event-a.json", typeinfo.EventsDescriptor.GVK); err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	podRef := corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "a-s8z6g", UID: "8fc81f11-1bac-472e-a12c-cfc0075e172b"}
+	coreEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "a-s8z6g.backoff", Namespace: "default"},
+		InvolvedObject: podRef,
+		Reason:         "BackOff",
+		Source:         corev1.EventSource{Component: "kubelet"},
+		LastTimestamp:  metav1.NewTime(time.Date(2025, time.May, 8, 10, 0, 0, 0, time.UTC)),
+		Count:          1,
+	}
+	if err = baseView.CreateObject(typeinfo.CoreEventsDescriptor.GVK, coreEvent); err != nil {
+		t.Fatalf("failed to create core/v1 event: %v", err)
+	}
+	for _, ev := range []*eventsv1.Event{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-s8z6g.scheduled", Namespace: "default"},
+			EventTime:  metav1.NewMicroTime(time.Date(2025, time.May, 8, 9, 30, 0, 0, time.UTC)),
+			Regarding:  podRef,
+			Reason:     "Scheduled",
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b.scheduled", Namespace: "default"},
+			EventTime:  metav1.NewMicroTime(time.Date(2025, time.May, 8, 9, 0, 0, 0, time.UTC)),
+			Regarding:  corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "b"},
+			Reason:     "Scheduled",
+		},
+	} {
+		if _, err = baseView.GetEventSink().Create(context.Background(), ev); err != nil {
+			t.Fatalf("failed to record event %q: %v", ev.Name, err)
+		}
+	}
+
+	tests := map[string]struct {
+		regarding []corev1.ObjectReference
+		wantNames []string
+	}{
+		"all events": {
+			wantNames: []string{"b.scheduled", "a-bingo.aaabbb", "a-s8z6g.scheduled", "a-s8z6g.backoff"},
+		},
+		"events regarding pod": {
+			regarding: []corev1.ObjectReference{{Kind: "Pod", Name: "a-s8z6g"}},
+			wantNames: []string{"a-bingo.aaabbb", "a-s8z6g.scheduled", "a-s8z6g.backoff"},
+		},
+		"events regarding any of pods": {
+			regarding: []corev1.ObjectReference{{Kind: "Pod", Name: "b"}, {Kind: "Pod", Name: "a-s8z6g", UID: podRef.UID}},
+			wantNames: []string{"b.scheduled", "a-bingo.aaabbb", "a-s8z6g.scheduled", "a-s8z6g.backoff"},
+		},
+		"events regarding recreated pod": {
+			regarding: []corev1.ObjectReference{{Kind: "Pod", Name: "a-s8z6g", UID: "9d3c4f0e-0b4e-4c5a-8f5e-1d2a3b4c5d6e"}},
+			wantNames: nil,
+		},
+		"events regarding node of same name": {
+			regarding: []corev1.ObjectReference{{Kind: "Node", Name: "a-s8z6g"}},
+			wantNames: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			events, err := baseView.ListEvents("default", tc.regarding...)
+			if err != nil {
+				t.Fatalf("failed to list events: %v", err)
+			}
+			var gotNames []string
+			for _, e := range events {
+				gotNames = append(gotNames, e.Name)
+			}
+			if !slices.Equal(gotNames, tc.wantNames) {
+				t.Errorf("expected events %v, got %v", tc.wantNames, gotNames)
+			}
+		})
+	}
+}
+
 func TestCombinePrimarySecondary(t *testing.T) {
 	primary := []metav1.Object{
 		&corev1.Node{
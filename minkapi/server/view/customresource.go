@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/store"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CustomResourceDescriptors returns the descriptors of the custom resources defined by the CustomResourceDefinitions
+// visible through the given view, ordered by their GVR.
+func CustomResourceDescriptors(v minkapi.View) ([]typeinfo.Descriptor, error) {
+	crds, _, err := v.ListMetaObjects(typeinfo.CustomResourceDefinitionsDescriptor.GVK, minkapi.MatchCriteria{})
+	if err != nil {
+		return nil, err
+	}
+	return customResourceDescriptorsOf(crds), nil
+}
+
+func customResourceDescriptorsOf(crds []metav1.Object) (descriptors []typeinfo.Descriptor) {
+	for _, mo := range crds {
+		crd, ok := mo.(*apiextensionsv1.CustomResourceDefinition)
+		if !ok {
+			continue
+		}
+		d, err := typeinfo.NewCustomResourceDescriptor(crd)
+		if err != nil {
+			// invalid CustomResourceDefinitions are rejected by prepareCustomResourceDefinition before being stored.
+			continue
+		}
+		descriptors = append(descriptors, d)
+	}
+	slices.SortFunc(descriptors, func(a, b typeinfo.Descriptor) int {
+		return strings.Compare(a.GVR.String(), b.GVR.String())
+	})
+	return
+}
+
+// prepareCustomResourceDefinition validates the given CustomResourceDefinition before it is stored. Like the
+// kube-apiserver, it marks the names of a valid CustomResourceDefinition as accepted and the CustomResourceDefinition
+// as established, since its custom resource is served as soon as it is stored.
+func prepareCustomResourceDefinition(obj metav1.Object) error {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("object %q of type %T is not a CustomResourceDefinition", obj.GetName(), obj))
+	}
+	d, err := typeinfo.NewCustomResourceDescriptor(crd)
+	if err != nil {
+		return err
+	}
+	crd.Status.AcceptedNames = crd.Spec.Names
+	crd.Status.AcceptedNames.ListKind = string(d.ListKind)
+	crd.Status.AcceptedNames.Singular = d.APIResource.SingularName
+	if !slices.Contains(crd.Status.StoredVersions, d.GVK.Version) {
+		crd.Status.StoredVersions = append(crd.Status.StoredVersions, d.GVK.Version)
+	}
+	for _, condType := range []apiextensionsv1.CustomResourceDefinitionConditionType{apiextensionsv1.NamesAccepted, apiextensionsv1.Established} {
+		i := slices.IndexFunc(crd.Status.Conditions, func(c apiextensionsv1.CustomResourceDefinitionCondition) bool {
+			return c.Type == condType
+		})
+		if i >= 0 && crd.Status.Conditions[i].Status == apiextensionsv1.ConditionTrue {
+			continue
+		}
+		cond := apiextensionsv1.CustomResourceDefinitionCondition{
+			Type:               condType,
+			Status:             apiextensionsv1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(condType),
+		}
+		if i >= 0 {
+			crd.Status.Conditions[i] = cond
+		} else {
+			crd.Status.Conditions = append(crd.Status.Conditions, cond)
+		}
+	}
+	return nil
+}
+
+// syncCustomResourceStores creates the stores of the custom resources with the given descriptors that are missing in
+// the given stores and closes and removes the stores of custom resources that are no longer defined. The
+// versionCounterFn returns the version counter to be used by the store of a newly defined custom resource.
+func syncCustomResourceStores(log logr.Logger, args *minkapi.ViewArgs, stores map[schema.GroupVersionKind]*store.InMemResourceStore, descriptors []typeinfo.Descriptor, versionCounterFn func(gvk schema.GroupVersionKind) *atomic.Int64) error {
+	defined := make(map[schema.GroupVersionKind]bool, len(descriptors))
+	for _, d := range descriptors {
+		defined[d.GVK] = true
+		if _, exists := stores[d.GVK]; exists {
+			continue
+		}
+		stores[d.GVK] = createInMemStore(log, d, versionCounterFn(d.GVK), args)
+		log.V(3).Info("created store for custom resource", "view", args.Name, "gvk", d.GVK)
+	}
+	var errs []error
+	for gvk, s := range stores {
+		if defined[gvk] || typeinfo.SupportedScheme.Recognizes(gvk) {
+			continue
+		}
+		errs = append(errs, s.Close())
+		delete(stores, gvk)
+		log.V(3).Info("removed store for custom resource", "view", args.Name, "gvk", gvk)
+	}
+	return errors.Join(errs...)
+}
+
+func newVersionCounter(schema.GroupVersionKind) *atomic.Int64 {
+	return &atomic.Int64{}
+}
@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+func TestCustomResourceLifecycle(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, v := range []mkapi.View{b, s} {
+		t.Run(v.GetName(), func(t *testing.T) {
+			b.Reset()
+			s.Reset()
+			crd := newWidgetCRD()
+			if err := v.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, crd); err != nil {
+				t.Fatalf("failed to create CRD: %v", err)
+			}
+			if !isEstablished(crd) {
+				t.Errorf("expected CRD to be established, got conditions %v", crd.Status.Conditions)
+			}
+			descriptors, err := CustomResourceDescriptors(v)
+			if err != nil {
+				t.Fatalf("failed to get custom resource descriptors: %v", err)
+			}
+			if len(descriptors) != 1 || descriptors[0].GVK != widgetGVK || !descriptors[0].StatusSubresource {
+				t.Fatalf("unexpected custom resource descriptors: %+v", descriptors)
+			}
+
+			if err = v.CreateObject(widgetGVK, newWidget("w-a", 1)); err != nil {
+				t.Fatalf("failed to create widget: %v", err)
+			}
+			objName := cache.NewObjectName("default", "w-a")
+			if _, _, err = v.PatchObject(widgetGVK, objName, types.MergePatchType, []byte(`{"spec":{"size":2}}`), mkapi.PatchOptions{}); err != nil {
+				t.Fatalf("failed to patch widget: %v", err)
+			}
+			if _, _, err = v.PatchObjectStatus(widgetGVK, objName, types.MergePatchType, []byte(`{"status":{"phase":"Ready"}}`), mkapi.PatchOptions{}); err != nil {
+				t.Fatalf("failed to patch widget status: %v", err)
+			}
+			listObj, err := v.ListObjects(widgetGVK, mkapi.MatchCriteria{Namespace: "default"})
+			if err != nil {
+				t.Fatalf("failed to list widgets: %v", err)
+			}
+			list, ok := listObj.(*unstructured.UnstructuredList)
+			if !ok {
+				t.Fatalf("expected *unstructured.UnstructuredList, got %T", listObj)
+			}
+			if list.GetKind() != "WidgetList" || len(list.Items) != 1 {
+				t.Fatalf("expected WidgetList with 1 item, got %q with %d items", list.GetKind(), len(list.Items))
+			}
+			size, _, _ := unstructured.NestedInt64(list.Items[0].Object, "spec", "size")
+			phase, _, _ := unstructured.NestedString(list.Items[0].Object, "status", "phase")
+			if size != 2 || phase != "Ready" {
+				t.Errorf("expected spec.size 2 and status.phase Ready, got %d and %q", size, phase)
+			}
+
+			if err = v.DeleteObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, cache.NewObjectName("", crd.Name), mkapi.DeleteOptions{}); err != nil {
+				t.Fatalf("failed to delete CRD: %v", err)
+			}
+			if _, err = b.GetResourceStore(widgetGVK); !errors.Is(err, mkapi.ErrStoreNotFound) {
+				t.Errorf("expected widget store to be removed with its CRD, got %v", err)
+			}
+			if _, err = v.GetResourceStore(widgetGVK); !errors.Is(err, mkapi.ErrStoreNotFound) {
+				t.Errorf("expected widget store to be removed from view %q with its CRD, got %v", v.GetName(), err)
+			}
+		})
+	}
+}
+
+func TestCustomResourceDefinedInBaseIsServedBySandbox(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	if err = b.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, newWidgetCRD()); err != nil {
+		t.Fatalf("failed to create CRD: %v", err)
+	}
+	if err = b.CreateObject(widgetGVK, newWidget("w-base", 1)); err != nil {
+		t.Fatalf("failed to create widget in base view: %v", err)
+	}
+	if err = s.CreateObject(widgetGVK, newWidget("w-sandbox", 1)); err != nil {
+		t.Fatalf("failed to create widget in sandbox view: %v", err)
+	}
+	baseStore, err := b.GetResourceStore(widgetGVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sandboxStore, err := s.GetResourceStore(widgetGVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if baseStore.GetVersionCounter() != sandboxStore.GetVersionCounter() {
+		t.Errorf("expected sandbox widget store to share the version counter of the base store")
+	}
+	sandboxObjs, _, err := s.ListMetaObjects(widgetGVK, mkapi.MatchCriteria{})
+	if err != nil {
+		t.Fatalf("failed to list widgets in sandbox view: %v", err)
+	}
+	if len(sandboxObjs) != 2 {
+		t.Errorf("expected 2 widgets in sandbox view, got %d", len(sandboxObjs))
+	}
+	baseObjs, _, err := b.ListMetaObjects(widgetGVK, mkapi.MatchCriteria{})
+	if err != nil {
+		t.Fatalf("failed to list widgets in base view: %v", err)
+	}
+	if len(baseObjs) != 1 {
+		t.Errorf("expected 1 widget in base view, got %d", len(baseObjs))
+	}
+}
+
+func TestCustomResourceDefinedInSandboxOnly(t *testing.T) {
+	_, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	if err = s.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, newWidgetCRD()); err != nil {
+		t.Fatalf("failed to create CRD: %v", err)
+	}
+	if err = s.CreateObject(widgetGVK, newWidget("w-a", 1)); err != nil {
+		t.Fatalf("failed to create widget: %v", err)
+	}
+	objs, _, err := s.ListMetaObjects(widgetGVK, mkapi.MatchCriteria{})
+	if err != nil {
+		t.Fatalf("failed to list widgets: %v", err)
+	}
+	if len(objs) != 1 {
+		t.Errorf("expected 1 widget, got %d", len(objs))
+	}
+	_, err = s.GetObject(widgetGVK, cache.NewObjectName("default", "missing"))
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected NotFound error, got %v", err)
+	}
+	if err = s.DeleteObject(widgetGVK, cache.NewObjectName("default", "w-a"), mkapi.DeleteOptions{}); err != nil {
+		t.Errorf("failed to delete widget: %v", err)
+	}
+}
+
+func TestInvalidCustomResourceDefinition(t *testing.T) {
+	tests := map[string]struct {
+		mutate func(crd *apiextensionsv1.CustomResourceDefinition)
+	}{
+		"name mismatch": {
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Name = "widgets.other.com"
+			},
+		},
+		"missing kind": {
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Names.Kind = ""
+			},
+		},
+		"no storage version": {
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Spec.Versions[0].Storage = false
+			},
+		},
+		"built-in kind": {
+			mutate: func(crd *apiextensionsv1.CustomResourceDefinition) {
+				crd.Name = "deployments.apps"
+				crd.Spec.Group = "apps"
+				crd.Spec.Names.Plural = "deployments"
+				crd.Spec.Names.Kind = "Deployment"
+			},
+		},
+	}
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crd := newWidgetCRD()
+			tc.mutate(crd)
+			err := b.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, crd)
+			if !apierrors.IsInvalid(err) {
+				t.Errorf("expected Invalid error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadCustomResources(t *testing.T) {
+	b, _, err := setup(t)
+	if err != nil {
+		return
+	}
+	if err = b.CreateObject(typeinfo.CustomResourceDefinitionsDescriptor.GVK, newWidgetCRD()); err != nil {
+		t.Fatalf("failed to create CRD: %v", err)
+	}
+	if err = b.CreateObject(widgetGVK, newWidget("w-a", 3)); err != nil {
+		t.Fatalf("failed to create widget: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = b.Save(&buf); err != nil {
+		t.Fatalf("failed to save view: %v", err)
+	}
+	loaded, err := New(log, &baseViewArgs)
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = loaded.Close()
+	})
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatalf("failed to load view: %v", err)
+	}
+	obj, err := loaded.GetObject(widgetGVK, cache.NewObjectName("default", "w-a"))
+	if err != nil {
+		t.Fatalf("failed to get restored widget: %v", err)
+	}
+	size, _, _ := unstructured.NestedInt64(obj.(*unstructured.Unstructured).Object, "spec", "size")
+	if size != 3 {
+		t.Errorf("expected spec.size 3, got %d", size)
+	}
+}
+
+func newWidgetCRD() *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: widgetGVK.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: "widgets",
+				Kind:   widgetGVK.Kind,
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    widgetGVK.Version,
+					Served:  true,
+					Storage: true,
+					Subresources: &apiextensionsv1.CustomResourceSubresources{
+						Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newWidget(name string, size int64) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"size": size},
+	}}
+	u.SetGroupVersionKind(widgetGVK)
+	u.SetName(name)
+	u.SetNamespace("default")
+	return u
+}
+
+func isEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established {
+			return c.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
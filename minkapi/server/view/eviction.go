@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/podutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// disruptedPodTimeout is the duration after which a pod recorded as disrupted in the status of a
+	// PodDisruptionBudget counts towards its healthy pods again if it has not been deleted, like in the disruption
+	// controller of the kube-controller-manager.
+	disruptedPodTimeout = 2 * time.Minute
+	// maxDisruptedPods is the maximum number of pods recorded as disrupted in the status of a PodDisruptionBudget.
+	maxDisruptedPods = 2000
+)
+
+// scalableGVKs are the kinds of the controllers whose replicas determine the pods expected by a PodDisruptionBudget.
+var scalableGVKs = []schema.GroupVersionKind{
+	typeinfo.ReplicaSetDescriptor.GVK,
+	typeinfo.DeploymentDescriptor.GVK,
+	typeinfo.StatefulSetDescriptor.GVK,
+	typeinfo.ReplicationControllersDescriptor.GVK,
+}
+
+// PodEviction holds the objects changed by the eviction of a pod.
+type PodEviction struct {
+	// Pod is the evicted pod as stored before its deletion.
+	Pod *corev1.Pod
+	// PendingPod is the evicted pod marked for deletion, or nil if it was removed.
+	PendingPod *corev1.Pod
+	// PrevBudget is the PodDisruptionBudget of the pod before the eviction was recorded in its status, or nil if the
+	// eviction did not need to be granted by a budget.
+	PrevBudget *policyv1.PodDisruptionBudget
+	// Budget is the PodDisruptionBudget of the pod whose status records the eviction, or nil if the eviction did not
+	// need to be granted by a budget.
+	Budget *policyv1.PodDisruptionBudget
+}
+
+// EvictPod evicts the pod of the given name from the given view like the eviction subresource of the kube-apiserver,
+// deleting it with the given opts if the PodDisruptionBudget stored in the view that selects it allows the disruption.
+// Since views have no disruption controller, the status of the budget is computed from the pods and controllers in
+// the view before the eviction is granted and recorded in it.
+//
+// Like in the kube-apiserver, the budget is ignored for pods that are pending, terminated or already marked for
+// deletion, and a 429 TooManyRequests error with a DisruptionBudget cause is returned if the budget does not allow the
+// disruption.
+func EvictPod(v minkapi.View, podName cache.ObjectName, opts minkapi.DeleteOptions) (eviction PodEviction, err error) {
+	gvk := typeinfo.PodsDescriptor.GVK
+	obj, err := v.GetObject(gvk, podName)
+	if err != nil {
+		return
+	}
+	eviction.Pod = obj.(*corev1.Pod).DeepCopy()
+	if !canIgnoreBudget(eviction.Pod) {
+		eviction.PrevBudget, eviction.Budget, err = reserveDisruption(v, eviction.Pod, v.GetClock().Now())
+		if err != nil {
+			return
+		}
+	}
+	if err = v.DeleteObject(gvk, podName, opts); err != nil {
+		return
+	}
+	if obj, err = v.GetObject(gvk, podName); err == nil {
+		eviction.PendingPod = obj.(*corev1.Pod)
+	}
+	err = nil
+	return
+}
+
+// canIgnoreBudget returns whether the given pod can be evicted without the consent of its PodDisruptionBudget.
+func canIgnoreBudget(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp != nil ||
+		pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// reserveDisruption records the disruption of the given pod in the status of its PodDisruptionBudget if the budget
+// allows it. It returns the budget before and after the disruption was recorded, which are nil if the pod has no
+// budget or the budget need not be decremented for an unhealthy pod. Like in the kube-apiserver, the budget is read
+// and checked again if a concurrent eviction updated it in the meantime.
+func reserveDisruption(v minkapi.View, pod *corev1.Pod, now time.Time) (prevBudget, budget *policyv1.PodDisruptionBudget, err error) {
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		prevBudget, budget, err = tryReserveDisruption(v, pod, now)
+		return err
+	})
+	return
+}
+
+// tryReserveDisruption makes a single attempt of reserveDisruption, failing with a 409 Conflict error if the budget
+// was updated since it was read.
+func tryReserveDisruption(v minkapi.View, pod *corev1.Pod, now time.Time) (prevBudget, budget *policyv1.PodDisruptionBudget, err error) {
+	budgets, err := budgetsOf(v, pod)
+	if err != nil || len(budgets) == 0 {
+		return
+	}
+	if len(budgets) > 1 {
+		err = apierrors.NewInternalError(errors.New("this pod has more than one PodDisruptionBudget, which the eviction subresource does not support"))
+		return
+	}
+	prevBudget = budgets[0]
+	budget = prevBudget.DeepCopy()
+	if err = updateBudgetStatus(v, budget, now); err != nil {
+		return
+	}
+	if !podutil.IsPodReady(pod) {
+		// an unhealthy pod does not count towards the healthy pods, so its eviction does not consume a disruption.
+		if ptr.Deref(budget.Spec.UnhealthyPodEvictionPolicy, policyv1.IfHealthyBudget) == policyv1.AlwaysAllow ||
+			(budget.Status.CurrentHealthy >= budget.Status.DesiredHealthy && budget.Status.DesiredHealthy > 0) {
+			return nil, nil, nil
+		}
+	}
+	if budget.Status.DisruptionsAllowed <= 0 {
+		tooManyErr := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		tooManyErr.ErrStatus.Details.Causes = append(tooManyErr.ErrStatus.Details.Causes, metav1.StatusCause{
+			Type:    policyv1.DisruptionBudgetCause,
+			Message: fmt.Sprintf("The disruption budget %s needs %d healthy pods and has %d currently", budget.Name, budget.Status.DesiredHealthy, budget.Status.CurrentHealthy),
+		})
+		return nil, nil, tooManyErr
+	}
+	if len(budget.Status.DisruptedPods) >= maxDisruptedPods {
+		err = apierrors.NewForbidden(policyv1.Resource(typeinfo.PodDisruptionBudgetDescriptor.GVR.Resource), budget.Name,
+			errors.New("DisruptedPods map too big - too many evictions not confirmed by PDB controller"))
+		return nil, nil, err
+	}
+	budget.Status.DisruptionsAllowed--
+	if budget.Status.DisruptionsAllowed == 0 {
+		setDisruptionAllowedCondition(budget, policyv1.InsufficientPodsReason, "")
+	}
+	if budget.Status.DisruptedPods == nil {
+		budget.Status.DisruptedPods = make(map[string]metav1.Time)
+	}
+	budget.Status.DisruptedPods[pod.Name] = metav1.NewTime(now)
+	if err = v.UpdateObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, budget); err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+// budgetsOf returns the PodDisruptionBudgets in the namespace of the given pod whose selector matches it. Like in the
+// kube-apiserver, budgets with an empty selector match no pods.
+func budgetsOf(v minkapi.View, pod *corev1.Pod) (budgets []*policyv1.PodDisruptionBudget, err error) {
+	objs, _, err := v.ListMetaObjects(typeinfo.PodDisruptionBudgetDescriptor.GVK, minkapi.MatchCriteria{Namespace: pod.Namespace})
+	if err != nil {
+		return
+	}
+	for _, obj := range objs {
+		budget := obj.(*policyv1.PodDisruptionBudget)
+		selector, selectorErr := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if selectorErr != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		budgets = append(budgets, budget)
+	}
+	return
+}
+
+// updateBudgetStatus computes the status of the given PodDisruptionBudget from the pods and controllers in the given
+// view like the disruption controller of the kube-controller-manager.
+func updateBudgetStatus(v minkapi.View, budget *policyv1.PodDisruptionBudget, now time.Time) error {
+	selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	objs, _, err := v.ListMetaObjects(typeinfo.PodsDescriptor.GVK, minkapi.MatchCriteria{Namespace: budget.Namespace, LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	pods := make(map[string]*corev1.Pod, len(objs))
+	for _, obj := range objs {
+		pod := obj.(*corev1.Pod)
+		pods[pod.Name] = pod
+	}
+
+	disruptedPods := make(map[string]metav1.Time)
+	for name, disruptedAt := range budget.Status.DisruptedPods {
+		if pod, ok := pods[name]; ok && pod.DeletionTimestamp == nil && now.Sub(disruptedAt.Time) < disruptedPodTimeout {
+			disruptedPods[name] = disruptedAt
+		}
+	}
+	var currentHealthy int32
+	for name, pod := range pods {
+		if _, disrupted := disruptedPods[name]; !disrupted && pod.DeletionTimestamp == nil && podutil.IsPodReady(pod) {
+			currentHealthy++
+		}
+	}
+	expectedCount, desiredHealthy, countErr := expectedPodCount(v, budget, pods)
+
+	budget.Status.ObservedGeneration = budget.Generation
+	budget.Status.DisruptedPods = disruptedPods
+	budget.Status.CurrentHealthy = currentHealthy
+	budget.Status.DesiredHealthy = desiredHealthy
+	budget.Status.ExpectedPods = expectedCount
+	budget.Status.DisruptionsAllowed = 0
+	switch {
+	case countErr != nil:
+		// like the disruption controller, fail safe by allowing no disruptions.
+		setDisruptionAllowedCondition(budget, policyv1.SyncFailedReason, countErr.Error())
+	case expectedCount > 0 && currentHealthy > desiredHealthy:
+		budget.Status.DisruptionsAllowed = currentHealthy - desiredHealthy
+		setDisruptionAllowedCondition(budget, policyv1.SufficientPodsReason, "")
+	default:
+		setDisruptionAllowedCondition(budget, policyv1.InsufficientPodsReason, "")
+	}
+	return nil
+}
+
+// expectedPodCount returns the number of pods expected to be selected by the given PodDisruptionBudget and the number of
+// them that must be healthy. Unless the budget requires an absolute number of available pods, the expected pods are
+// the replicas of the controllers of the given selected pods.
+func expectedPodCount(v minkapi.View, budget *policyv1.PodDisruptionBudget, pods map[string]*corev1.Pod) (expectedCount, desiredHealthy int32, err error) {
+	switch {
+	case budget.Spec.MaxUnavailable != nil:
+		if expectedCount, err = expectedScale(v, pods); err != nil {
+			return
+		}
+		var maxUnavailable int
+		if maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(budget.Spec.MaxUnavailable, int(expectedCount), true); err != nil {
+			return
+		}
+		desiredHealthy = max(expectedCount-int32(maxUnavailable), 0)
+	case budget.Spec.MinAvailable != nil && budget.Spec.MinAvailable.Type == intstr.Int:
+		desiredHealthy = budget.Spec.MinAvailable.IntVal
+		expectedCount = int32(len(pods))
+	case budget.Spec.MinAvailable != nil:
+		if expectedCount, err = expectedScale(v, pods); err != nil {
+			return
+		}
+		var minAvailable int
+		if minAvailable, err = intstr.GetScaledValueFromIntOrPercent(budget.Spec.MinAvailable, int(expectedCount), true); err != nil {
+			return
+		}
+		desiredHealthy = int32(minAvailable)
+	}
+	return
+}
+
+// expectedScale returns the sum of the replicas of the controllers of the given pods. Like in the disruption controller,
+// an error is returned if a pod has no controller in the view whose replicas are known.
+func expectedScale(v minkapi.View, pods map[string]*corev1.Pod) (int32, error) {
+	controllerScales := make(map[types.UID]int32)
+	for _, pod := range pods {
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			return 0, fmt.Errorf("found no controller ref for pod %q", pod.Name)
+		}
+		if _, found := controllerScales[ref.UID]; found {
+			continue
+		}
+		uid, replicas, found, err := controllerScale(v, pod.Namespace, *ref)
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, fmt.Errorf("found no controllers for pod %q", pod.Name)
+		}
+		controllerScales[uid] = replicas
+	}
+	var scale int32
+	for _, replicas := range controllerScales {
+		scale += replicas
+	}
+	return scale, nil
+}
+
+// controllerScale returns the UID and replicas of the controller referred to by the given ref in the given namespace,
+// if it exists in the given view. Like in the disruption controller, the controller of pods of a ReplicaSet controlled
+// by a Deployment is the Deployment.
+func controllerScale(v minkapi.View, namespace string, ref metav1.OwnerReference) (uid types.UID, replicas int32, found bool, err error) {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	if !slices.Contains(scalableGVKs, gvk) {
+		return
+	}
+	obj, err := v.GetObject(gvk, cache.ObjectName{Namespace: namespace, Name: ref.Name})
+	if apierrors.IsNotFound(err) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return
+	}
+	if obj.(metav1.Object).GetUID() != ref.UID {
+		return
+	}
+	switch o := obj.(type) {
+	case *appsv1.ReplicaSet:
+		if deploymentRef := metav1.GetControllerOf(o); deploymentRef != nil && deploymentRef.Kind == string(typeinfo.DeploymentKind) {
+			return controllerScale(v, namespace, *deploymentRef)
+		}
+		return o.UID, ptr.Deref(o.Spec.Replicas, 1), true, nil
+	case *appsv1.Deployment:
+		return o.UID, ptr.Deref(o.Spec.Replicas, 1), true, nil
+	case *appsv1.StatefulSet:
+		return o.UID, ptr.Deref(o.Spec.Replicas, 1), true, nil
+	case *corev1.ReplicationController:
+		return o.UID, ptr.Deref(o.Spec.Replicas, 1), true, nil
+	}
+	return
+}
+
+// setDisruptionAllowedCondition sets the DisruptionAllowed condition of the given PodDisruptionBudget, which is true
+// for the given reason SufficientPods only.
+func setDisruptionAllowedCondition(budget *policyv1.PodDisruptionBudget, reason, message string) {
+	status := metav1.ConditionFalse
+	if reason == policyv1.SufficientPodsReason {
+		status = metav1.ConditionTrue
+	}
+	apimeta.SetStatusCondition(&budget.Status.Conditions, metav1.Condition{
+		Type:               policyv1.DisruptionAllowedCondition,
+		Status:             status,
+		ObservedGeneration: budget.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+func TestEvictPod(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	minAvailable := func(n int) []policyv1.PodDisruptionBudgetSpec {
+		return []policyv1.PodDisruptionBudgetSpec{{MinAvailable: ptr.To(intstr.FromInt(n))}}
+	}
+	tests := map[string]struct {
+		// readyPods are the readiness of the pods of the replicaset, of which the last one is evicted.
+		readyPods []bool
+		// replicas of the replicaset, which default to the number of its pods.
+		replicas       int32
+		evictedPhase   corev1.PodPhase
+		budgets        []policyv1.PodDisruptionBudgetSpec
+		wantCode       int32
+		wantBudget     bool
+		wantAllowed    int32
+		wantHealthy    int32
+		wantDesired    int32
+		wantPodRemains bool
+	}{
+		"without budget": {
+			readyPods: []bool{true},
+		},
+		"budget with sufficient pods": {
+			readyPods:   []bool{true, true, true},
+			budgets:     minAvailable(2),
+			wantBudget:  true,
+			wantAllowed: 0,
+			wantHealthy: 3,
+			wantDesired: 2,
+		},
+		"budget with insufficient pods": {
+			readyPods:      []bool{true, true},
+			budgets:        minAvailable(2),
+			wantCode:       http.StatusTooManyRequests,
+			wantPodRemains: true,
+		},
+		"budget with percentage of controller replicas": {
+			readyPods: []bool{true, true, true, true},
+			budgets: []policyv1.PodDisruptionBudgetSpec{{
+				MaxUnavailable: ptr.To(intstr.FromString("25%")),
+			}},
+			wantBudget:  true,
+			wantAllowed: 0,
+			wantHealthy: 4,
+			wantDesired: 3,
+		},
+		"budget with percentage of missing controller replicas": {
+			readyPods: []bool{true, true, true},
+			replicas:  6,
+			budgets: []policyv1.PodDisruptionBudgetSpec{{
+				MinAvailable: ptr.To(intstr.FromString("50%")),
+			}},
+			wantCode:       http.StatusTooManyRequests,
+			wantPodRemains: true,
+		},
+		"unready pod of healthy budget": {
+			readyPods: []bool{true, true, false},
+			budgets:   minAvailable(2),
+		},
+		"unready pod of unhealthy budget": {
+			readyPods:      []bool{true, false},
+			budgets:        minAvailable(2),
+			wantCode:       http.StatusTooManyRequests,
+			wantPodRemains: true,
+		},
+		"unready pod of unhealthy budget always allowing eviction": {
+			readyPods: []bool{true, false},
+			budgets: []policyv1.PodDisruptionBudgetSpec{{
+				MinAvailable:               ptr.To(intstr.FromInt(2)),
+				UnhealthyPodEvictionPolicy: ptr.To(policyv1.AlwaysAllow),
+			}},
+		},
+		"pending pod": {
+			readyPods:    []bool{true, false},
+			evictedPhase: corev1.PodPending,
+			budgets:      minAvailable(2),
+		},
+		"multiple budgets": {
+			readyPods:      []bool{true, true, true},
+			budgets:        append(minAvailable(1), minAvailable(1)...),
+			wantCode:       http.StatusInternalServerError,
+			wantPodRemains: true,
+		},
+	}
+	for name, tc := range tests {
+		for _, v := range []mkapi.View{b, s} {
+			t.Run(name+"/"+v.GetName(), func(t *testing.T) {
+				b.Reset()
+				s.Reset()
+				pods := storeReplicaSetPods(t, b, tc.replicas, tc.readyPods)
+				pod := pods[len(pods)-1]
+				if tc.evictedPhase != "" {
+					pod.Status.Phase = tc.evictedPhase
+					if err := b.UpdateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+						t.Fatalf("failed to update pod: %v", err)
+					}
+				}
+				for i, spec := range tc.budgets {
+					spec.Selector = &metav1.LabelSelector{MatchLabels: pod.Labels}
+					budget := &policyv1.PodDisruptionBudget{
+						ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: pod.Namespace},
+						Spec:       spec,
+					}
+					if err := b.CreateObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, budget); err != nil {
+						t.Fatalf("failed to create budget: %v", err)
+					}
+				}
+
+				eviction, err := EvictPod(v, objutil.CacheName(pod), mkapi.DeleteOptions{})
+				if tc.wantCode != 0 {
+					var statusErr *apierrors.StatusError
+					if !errors.As(err, &statusErr) || statusErr.ErrStatus.Code != tc.wantCode {
+						t.Fatalf("expected error with status code %d, got %v", tc.wantCode, err)
+					}
+					if tc.wantCode == http.StatusTooManyRequests && statusErr.ErrStatus.Details.Causes[0].Type != policyv1.DisruptionBudgetCause {
+						t.Errorf("expected cause %q, got %v", policyv1.DisruptionBudgetCause, statusErr.ErrStatus.Details.Causes)
+					}
+				} else if err != nil {
+					t.Fatalf("failed to evict pod: %v", err)
+				}
+				_, getErr := v.GetObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(pod))
+				if podRemains := getErr == nil; podRemains != tc.wantPodRemains {
+					t.Errorf("expected pod to remain %t, got %t", tc.wantPodRemains, podRemains)
+				}
+				if gotBudget := eviction.Budget != nil; gotBudget != tc.wantBudget {
+					t.Fatalf("expected eviction to be recorded in budget %t, got %t", tc.wantBudget, gotBudget)
+				}
+				if !tc.wantBudget {
+					return
+				}
+				obj, err := v.GetObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, objutil.CacheName(eviction.Budget))
+				if err != nil {
+					t.Fatalf("failed to get budget: %v", err)
+				}
+				status := obj.(*policyv1.PodDisruptionBudget).Status
+				if status.DisruptionsAllowed != tc.wantAllowed || status.CurrentHealthy != tc.wantHealthy || status.DesiredHealthy != tc.wantDesired {
+					t.Errorf("expected disruptionsAllowed %d, currentHealthy %d and desiredHealthy %d, got %d, %d and %d",
+						tc.wantAllowed, tc.wantHealthy, tc.wantDesired, status.DisruptionsAllowed, status.CurrentHealthy, status.DesiredHealthy)
+				}
+				if _, ok := status.DisruptedPods[pod.Name]; !ok {
+					t.Errorf("expected evicted pod to be recorded as disrupted, got %v", status.DisruptedPods)
+				}
+			})
+		}
+	}
+}
+
+func TestConcurrentEvictions(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, v := range []mkapi.View{b, s} {
+		t.Run(v.GetName(), func(t *testing.T) {
+			b.Reset()
+			s.Reset()
+			pods := storeReplicaSetPods(t, b, 0, []bool{true, true, true})
+			budget := &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: policyv1.PodDisruptionBudgetSpec{
+					MinAvailable: ptr.To(intstr.FromInt(1)),
+					Selector:     &metav1.LabelSelector{MatchLabels: pods[0].Labels},
+				},
+			}
+			if err := b.CreateObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, budget); err != nil {
+				t.Fatalf("failed to create budget: %v", err)
+			}
+			// the eviction of the second pod is granted by the budget while the eviction of the first pod has read
+			// the budget but not yet recorded its disruption in it.
+			cv := &concurrentlyEvictingView{View: v, t: t, podName: objutil.CacheName(pods[1])}
+			if _, err := EvictPod(cv, objutil.CacheName(pods[0]), mkapi.DeleteOptions{}); err != nil {
+				t.Fatalf("expected eviction conflicting with concurrent eviction to succeed, got: %v", err)
+			}
+			if !cv.evicted {
+				t.Fatalf("expected concurrent eviction to happen")
+			}
+			obj, err := v.GetObject(typeinfo.PodDisruptionBudgetDescriptor.GVK, objutil.CacheName(budget))
+			if err != nil {
+				t.Fatalf("failed to get budget: %v", err)
+			}
+			status := obj.(*policyv1.PodDisruptionBudget).Status
+			// the concurrently evicted pod has been removed, so only the disruption of the first pod is still recorded.
+			if _, ok := status.DisruptedPods[pods[0].Name]; !ok || status.DisruptionsAllowed != 0 {
+				t.Errorf("expected eviction to be recorded in budget, got disruptionsAllowed %d and disruptedPods %v", status.DisruptionsAllowed, status.DisruptedPods)
+			}
+			for _, pod := range pods[:2] {
+				if _, err = v.GetObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(pod)); !apierrors.IsNotFound(err) {
+					t.Errorf("expected evicted pod %q to be removed, got: %v", pod.Name, err)
+				}
+			}
+			if _, err = EvictPod(v, objutil.CacheName(pods[2]), mkapi.DeleteOptions{}); !apierrors.IsTooManyRequests(err) {
+				t.Errorf("expected eviction exceeding budget to be rejected, got: %v", err)
+			}
+		})
+	}
+}
+
+// concurrentlyEvictingView is a minkapi.View that evicts the pod with the given name before the first update of a
+// PodDisruptionBudget through it, as if the pod was evicted concurrently.
+type concurrentlyEvictingView struct {
+	mkapi.View
+	t       *testing.T
+	podName cache.ObjectName
+	evicted bool
+}
+
+func (v *concurrentlyEvictingView) UpdateObject(gvk schema.GroupVersionKind, obj metav1.Object) error {
+	if gvk == typeinfo.PodDisruptionBudgetDescriptor.GVK && !v.evicted {
+		v.evicted = true
+		if _, err := EvictPod(v.View, v.podName, mkapi.DeleteOptions{}); err != nil {
+			v.t.Fatalf("failed to evict pod %q concurrently: %v", v.podName, err)
+		}
+	}
+	return v.View.UpdateObject(gvk, obj)
+}
+
+// storeReplicaSetPods stores a replicaset with the given replicas, or one replica per given pod readiness if zero, and
+// its running pods of the given readiness in the given view.
+func storeReplicaSetPods(t *testing.T, v mkapi.View, replicas int32, readyPods []bool) (pods []*corev1.Pod) {
+	t.Helper()
+	if replicas == 0 {
+		replicas = int32(len(readyPods))
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: uuid.NewUUID()},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: ptr.To(replicas)},
+	}
+	if err := v.CreateObject(typeinfo.ReplicaSetDescriptor.GVK, rs); err != nil {
+		t.Fatalf("failed to create replicaset: %v", err)
+	}
+	for i, ready := range readyPods {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("web-%d", i),
+				Namespace:       rs.Namespace,
+				UID:             uuid.NewUUID(),
+				Labels:          map[string]string{"app": "web"},
+				OwnerReferences: []metav1.OwnerReference{controllerRef("apps/v1", "ReplicaSet", rs.Name, rs.UID)},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+			},
+		}
+		if err := v.CreateObject(typeinfo.PodsDescriptor.GVK, p); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		pods = append(pods, p)
+	}
+	return
+}
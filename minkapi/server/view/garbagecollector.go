@@ -0,0 +1,431 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/store"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// garbageCollector interprets the ownerReferences of the objects in a view like the garbage collector of the
+// kube-controller-manager. Once an owner is removed, its dependents are deleted in the background. An owner deleted
+// with foreground propagation is only removed once its blocking dependents have been deleted, and an owner deleted
+// with orphan propagation is removed once the references to it have been removed from its dependents.
+//
+// The garbage collector is driven by watches on the stores of its view only. In a sandbox view it thus acts only on
+// changes made in the sandbox and deletes or orphans dependents through the sandbox, leaving the delegate view
+// untouched. Unlike the kube garbage collector, it leaves alone dependents of owners that never existed in the view,
+// since views are commonly populated with a subset of the objects of a cluster.
+type garbageCollector struct {
+	log   logr.Logger
+	view  minkapi.View
+	queue workqueue.TypedRateLimitingInterface[gcItem]
+	// ctx is cancelled once the garbage collector is stopped.
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	// watches holds the cancel functions of the watches on the stores of the view.
+	watches map[*store.InMemResourceStore]context.CancelFunc
+	// gvks are the GVKs of the stores of the view that are searched for dependents.
+	gvks []schema.GroupVersionKind
+}
+
+// gcItem is an owner whose dependents are to be processed by the garbageCollector.
+type gcItem struct {
+	gvk  schema.GroupVersionKind
+	name cache.ObjectName
+	uid  types.UID
+	// removed is set if the owner has been removed from the view, in which case its dependents are deleted.
+	removed bool
+}
+
+// dependent is an object with an owner reference to an owner processed by the garbageCollector.
+type dependent struct {
+	gvk schema.GroupVersionKind
+	mo  metav1.Object
+	ref metav1.OwnerReference
+}
+
+func newGarbageCollector(log logr.Logger, view minkapi.View) *garbageCollector {
+	ctx, cancel := context.WithCancel(context.Background())
+	gc := &garbageCollector{
+		log:     log.WithValues("view", view.GetName()),
+		view:    view,
+		queue:   workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[gcItem]()),
+		ctx:     ctx,
+		cancel:  cancel,
+		watches: make(map[*store.InMemResourceStore]context.CancelFunc),
+	}
+	go gc.run()
+	return gc
+}
+
+// stop stops the watches and the processing of the garbage collector.
+func (gc *garbageCollector) stop() {
+	gc.cancel()
+	gc.queue.ShutDown()
+}
+
+// restartWatches restarts watching the given stores of the view. Must be called after the stores have been reset or
+// loaded, which terminates the watches on them.
+func (gc *garbageCollector) restartWatches(stores map[schema.GroupVersionKind]*store.InMemResourceStore) {
+	gc.mu.Lock()
+	for s, cancel := range gc.watches {
+		cancel()
+		delete(gc.watches, s)
+	}
+	gc.mu.Unlock()
+	gc.watchStores(stores)
+}
+
+// watchStores starts watching the given stores of the view that are not watched yet and stops watching the stores
+// that have been removed from the view. Must be called whenever the stores of the view change. It returns once the
+// new watches have been established so that no changes made afterward are missed.
+func (gc *garbageCollector) watchStores(stores map[schema.GroupVersionKind]*store.InMemResourceStore) {
+	gc.mu.Lock()
+	if gc.ctx.Err() != nil {
+		gc.mu.Unlock()
+		return
+	}
+	current := make(map[*store.InMemResourceStore]bool, len(stores))
+	gc.gvks = make([]schema.GroupVersionKind, 0, len(stores))
+	var started []chan struct{}
+	for gvk, s := range stores {
+		current[s] = true
+		gc.gvks = append(gc.gvks, gvk)
+		if _, ok := gc.watches[s]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(gc.ctx)
+		gc.watches[s] = cancel
+		startedC := make(chan struct{})
+		started = append(started, startedC)
+		go gc.watchStore(ctx, gvk, s, startedC)
+	}
+	for s, cancel := range gc.watches {
+		if !current[s] {
+			cancel()
+			delete(gc.watches, s)
+		}
+	}
+	gc.mu.Unlock()
+	for _, startedC := range started {
+		<-startedC
+	}
+}
+
+// watchStore watches the given store until the given context is cancelled, closing the given started channel once the
+// watch has been established. Initial events are requested so that objects marked for deletion before the watch was
+// established are processed. The watch is resumed from the last seen version when it times out, and after a second as
+// measured by the clock of the view when it fails.
+func (gc *garbageCollector) watchStore(ctx context.Context, gvk schema.GroupVersionKind, s *store.InMemResourceStore, started chan<- struct{}) {
+	markStarted := sync.OnceFunc(func() { close(started) })
+	defer markStarted()
+	opts := minkapi.WatchOptions{SendInitialEvents: true, AllowBookmarks: true}
+	var version int64
+	for ctx.Err() == nil {
+		err := s.Watch(ctx, opts, func(event watch.Event) error {
+			mo, err := store.AsMeta(event.Object)
+			if err != nil {
+				return err
+			}
+			if objVersion, err := store.ParseObjectResourceVersion(mo); err == nil {
+				version = max(version, objVersion)
+			}
+			if event.Type == watch.Bookmark {
+				if mo.GetAnnotations()[metav1.InitialEventsAnnotationKey] == "true" {
+					markStarted()
+				}
+				return nil
+			}
+			gc.handleEvent(gvk, event.Type, mo)
+			return nil
+		})
+		markStarted()
+		opts = minkapi.WatchOptions{StartVersion: version, AllowBookmarks: true}
+		switch {
+		case err == nil:
+		case apierrors.IsResourceExpired(err):
+			gc.log.Info("garbage collector missed changes evicted from the history of the store", "gvk", gvk, "version", version)
+			opts.SendInitialEvents = true
+		default:
+			gc.log.Error(err, "garbage collector failed to watch store", "gvk", gvk)
+			select {
+			case <-ctx.Done():
+			case <-gc.view.GetClock().After(time.Second):
+			}
+		}
+	}
+}
+
+// handleEvent enqueues the owners whose dependents are to be processed following the given event for the given object.
+func (gc *garbageCollector) handleEvent(gvk schema.GroupVersionKind, eventType watch.EventType, mo metav1.Object) {
+	switch {
+	case eventType == watch.Deleted:
+		gc.queue.Add(gcItem{gvk: gvk, name: objutil.CacheName(mo), uid: mo.GetUID(), removed: true})
+		gc.enqueueOwnersInForegroundDeletion(mo)
+	case mo.GetDeletionTimestamp() != nil && hasPropagationFinalizer(mo):
+		gc.queue.Add(gcItem{gvk: gvk, name: objutil.CacheName(mo), uid: mo.GetUID()})
+	}
+}
+
+// enqueueOwnersInForegroundDeletion enqueues the owners of the given removed dependent that wait for the removal of
+// their blocking dependents.
+func (gc *garbageCollector) enqueueOwnersInForegroundDeletion(mo metav1.Object) {
+	for _, ref := range mo.GetOwnerReferences() {
+		if ref.BlockOwnerDeletion == nil || !*ref.BlockOwnerDeletion {
+			continue
+		}
+		owner := gc.getOwner(ref, mo.GetNamespace())
+		if owner == nil || owner.GetDeletionTimestamp() == nil || !slices.Contains(owner.GetFinalizers(), metav1.FinalizerDeleteDependents) {
+			continue
+		}
+		gc.queue.Add(gcItem{gvk: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind), name: objutil.CacheName(owner), uid: ref.UID})
+	}
+}
+
+func (gc *garbageCollector) run() {
+	for {
+		item, shutdown := gc.queue.Get()
+		if shutdown {
+			return
+		}
+		if err := gc.process(item); err != nil {
+			gc.log.Error(err, "garbage collector failed to process dependents of owner, retrying", "gvk", item.gvk, "owner", item.name)
+			gc.queue.AddRateLimited(item)
+		} else {
+			gc.queue.Forget(item)
+		}
+		gc.queue.Done(item)
+	}
+}
+
+// process deletes or orphans the dependents of the owner of the given item according to the state of the owner.
+func (gc *garbageCollector) process(item gcItem) error {
+	if item.removed {
+		_, err := gc.deleteDependents(item, metav1.DeletePropagationBackground)
+		return err
+	}
+	obj, err := gc.view.GetObject(item.gvk, item.name)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	owner, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if owner.GetUID() != item.uid || owner.GetDeletionTimestamp() == nil {
+		return nil
+	}
+	switch {
+	case slices.Contains(owner.GetFinalizers(), metav1.FinalizerOrphanDependents):
+		if err = gc.orphanDependents(item); err != nil {
+			return err
+		}
+		return gc.removeFinalizer(item, metav1.FinalizerOrphanDependents)
+	case slices.Contains(owner.GetFinalizers(), metav1.FinalizerDeleteDependents):
+		blocked, err := gc.deleteDependents(item, metav1.DeletePropagationForeground)
+		if err != nil || blocked {
+			// the owner is processed again once its blocking dependents are removed.
+			return err
+		}
+		return gc.removeFinalizer(item, metav1.FinalizerDeleteDependents)
+	}
+	return nil
+}
+
+// deleteDependents deletes the dependents of the owner of the given item with the given propagation policy and
+// returns whether dependents blocking the deletion of the owner remain. Like the kube garbage collector, dependents
+// with other existing owners are not deleted but only released by the owner.
+func (gc *garbageCollector) deleteDependents(item gcItem, policy metav1.DeletionPropagation) (blocked bool, err error) {
+	dependents, err := gc.listDependents(item)
+	if err != nil {
+		return
+	}
+	var errs []error
+	for _, d := range dependents {
+		name := objutil.CacheName(d.mo)
+		if gc.hasOtherOwners(d.mo, item.uid) {
+			errs = append(errs, gc.removeOwnerReference(d.gvk, name, item.uid))
+			continue
+		}
+		if d.mo.GetDeletionTimestamp() == nil {
+			uid := d.mo.GetUID()
+			err = gc.view.DeleteObject(d.gvk, name, minkapi.DeleteOptions{
+				Preconditions:     metav1.Preconditions{UID: &uid},
+				PropagationPolicy: &policy,
+			})
+			if err = ignoreNotFound(err); err != nil && !apierrors.IsConflict(err) {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if d.ref.BlockOwnerDeletion != nil && *d.ref.BlockOwnerDeletion && gc.exists(d.gvk, name, d.mo.GetUID()) {
+			blocked = true
+		}
+	}
+	err = errors.Join(errs...)
+	return
+}
+
+// orphanDependents removes the owner references to the owner of the given item from its dependents.
+func (gc *garbageCollector) orphanDependents(item gcItem) error {
+	dependents, err := gc.listDependents(item)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, d := range dependents {
+		errs = append(errs, gc.removeOwnerReference(d.gvk, objutil.CacheName(d.mo), item.uid))
+	}
+	return errors.Join(errs...)
+}
+
+// listDependents lists the objects referencing the owner of the given item. The dependents of a namespaced owner can
+// only reside in the namespace of the owner.
+func (gc *garbageCollector) listDependents(item gcItem) (dependents []dependent, err error) {
+	gc.mu.Lock()
+	gvks := gc.gvks
+	gc.mu.Unlock()
+	criteria := minkapi.MatchCriteria{Namespace: item.name.Namespace}
+	for _, gvk := range gvks {
+		objs, _, err := gc.view.ListMetaObjects(gvk, criteria)
+		if errors.Is(err, minkapi.ErrStoreNotFound) {
+			// the custom resource is no longer defined.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, mo := range objs {
+			for _, ref := range mo.GetOwnerReferences() {
+				if ref.UID == item.uid {
+					dependents = append(dependents, dependent{gvk: gvk, mo: mo, ref: ref})
+					break
+				}
+			}
+		}
+	}
+	return
+}
+
+// hasOtherOwners returns whether the given dependent has an existing owner other than the one with the given uid.
+func (gc *garbageCollector) hasOtherOwners(mo metav1.Object, uid types.UID) bool {
+	for _, ref := range mo.GetOwnerReferences() {
+		if ref.UID != uid && gc.getOwner(ref, mo.GetNamespace()) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getOwner returns the owner referenced by the given reference of a dependent in the given namespace or nil if it
+// does not exist. Since owner references do not tell the scope of the owner, a cluster-scoped owner is looked up if
+// there is no owner in the namespace of the dependent.
+func (gc *garbageCollector) getOwner(ref metav1.OwnerReference, namespace string) metav1.Object {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	for _, name := range []cache.ObjectName{{Namespace: namespace, Name: ref.Name}, {Name: ref.Name}} {
+		obj, err := gc.view.GetObject(gvk, name)
+		if err != nil {
+			continue
+		}
+		if owner, err := meta.Accessor(obj); err == nil && owner.GetUID() == ref.UID {
+			return owner
+		}
+		if namespace == "" {
+			break
+		}
+	}
+	return nil
+}
+
+// exists returns whether the object with the given name and uid exists in the view.
+func (gc *garbageCollector) exists(gvk schema.GroupVersionKind, name cache.ObjectName, uid types.UID) bool {
+	obj, err := gc.view.GetObject(gvk, name)
+	if err != nil {
+		return false
+	}
+	mo, err := meta.Accessor(obj)
+	return err == nil && mo.GetUID() == uid
+}
+
+// removeOwnerReference removes the owner reference to the owner with the given uid from the object with the given name.
+func (gc *garbageCollector) removeOwnerReference(gvk schema.GroupVersionKind, name cache.ObjectName, uid types.UID) error {
+	return gc.updateObject(gvk, name, func(mo metav1.Object) bool {
+		refs := mo.GetOwnerReferences()
+		remaining := slices.DeleteFunc(slices.Clone(refs), func(ref metav1.OwnerReference) bool {
+			return ref.UID == uid
+		})
+		mo.SetOwnerReferences(remaining)
+		return len(remaining) != len(refs)
+	})
+}
+
+// removeFinalizer removes the given finalizer from the owner of the given item, which removes the owner from the view
+// unless other finalizers remain.
+func (gc *garbageCollector) removeFinalizer(item gcItem, finalizer string) error {
+	return gc.updateObject(item.gvk, item.name, func(mo metav1.Object) bool {
+		if mo.GetUID() != item.uid {
+			return false
+		}
+		finalizers := mo.GetFinalizers()
+		remaining := slices.DeleteFunc(slices.Clone(finalizers), func(f string) bool {
+			return f == finalizer
+		})
+		mo.SetFinalizers(remaining)
+		return len(remaining) != len(finalizers)
+	})
+}
+
+// updateObject updates a copy of the object with the given name mutated by the given function, retrying on conflicts.
+// The object is not updated if it no longer exists or if the mutate function returns false.
+func (gc *garbageCollector) updateObject(gvk schema.GroupVersionKind, name cache.ObjectName, mutate func(mo metav1.Object) bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := gc.view.GetObject(gvk, name)
+		if err != nil {
+			return ignoreNotFound(err)
+		}
+		// the view returns the stored object, which must not be mutated in place.
+		mo, err := meta.Accessor(obj.DeepCopyObject())
+		if err != nil {
+			return err
+		}
+		if !mutate(mo) {
+			return nil
+		}
+		return ignoreNotFound(gc.view.UpdateObject(gvk, mo))
+	})
+}
+
+// hasPropagationFinalizer returns whether the given object has been deleted with foreground or orphan propagation.
+func hasPropagationFinalizer(mo metav1.Object) bool {
+	finalizers := mo.GetFinalizers()
+	return slices.Contains(finalizers, metav1.FinalizerOrphanDependents) || slices.Contains(finalizers, metav1.FinalizerDeleteDependents)
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package view
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	"github.com/gardener/scaling-advisor/common/objutil"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+func TestGarbageCollection(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	tests := map[string]struct {
+		propagationPolicy *metav1.DeletionPropagation
+		wantOrphaned      bool
+	}{
+		"default propagation": {},
+		"background propagation": {
+			propagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+		},
+		"foreground propagation": {
+			propagationPolicy: ptr.To(metav1.DeletePropagationForeground),
+		},
+		"orphan propagation": {
+			propagationPolicy: ptr.To(metav1.DeletePropagationOrphan),
+			wantOrphaned:      true,
+		},
+	}
+	for name, tc := range tests {
+		for _, v := range []mkapi.View{b, s} {
+			t.Run(name+"/"+v.GetName(), func(t *testing.T) {
+				b.Reset()
+				s.Reset()
+				// objects are always stored in the base view so that deletions in the sandbox can be checked to leave it untouched.
+				deploy, rs, pods := storeWorkload(t, b)
+				deployName := objutil.CacheName(deploy)
+				if err := v.DeleteObject(typeinfo.DeploymentDescriptor.GVK, deployName, mkapi.DeleteOptions{PropagationPolicy: tc.propagationPolicy}); err != nil {
+					t.Fatalf("failed to delete deployment: %v", err)
+				}
+				waitForRemoval(t, v, typeinfo.DeploymentDescriptor.GVK, deployName)
+				rsName := objutil.CacheName(rs)
+				if !tc.wantOrphaned {
+					waitForRemoval(t, v, typeinfo.ReplicaSetDescriptor.GVK, rsName)
+					for _, p := range pods {
+						waitForRemoval(t, v, typeinfo.PodsDescriptor.GVK, objutil.CacheName(p))
+					}
+				} else {
+					obj, err := v.GetObject(typeinfo.ReplicaSetDescriptor.GVK, rsName)
+					if err != nil {
+						t.Fatalf("expected orphaned replicaset to remain: %v", err)
+					}
+					if refs := obj.(*appsv1.ReplicaSet).OwnerReferences; len(refs) != 0 {
+						t.Errorf("expected owner references of orphaned replicaset to be removed, got %v", refs)
+					}
+					for _, p := range pods {
+						if _, err = getPod(t, v, p.Namespace, p.Name); err != nil {
+							t.Errorf("expected pod of orphaned replicaset to remain: %v", err)
+						}
+					}
+				}
+				if v == s {
+					assertWorkloadInView(t, b, deploy, rs, pods)
+				}
+			})
+		}
+	}
+}
+
+func TestForegroundDeletionWaitsForBlockingDependents(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, v := range []mkapi.View{b, s} {
+		t.Run(v.GetName(), func(t *testing.T) {
+			b.Reset()
+			s.Reset()
+			_, rs, pods := storeWorkload(t, b)
+			protectedPod := pods[0].DeepCopy()
+			protectedPod.Finalizers = []string{"example.com/protect"}
+			protectedPod.ResourceVersion = ""
+			if err := b.UpdateObject(typeinfo.PodsDescriptor.GVK, protectedPod); err != nil {
+				t.Fatalf("failed to add finalizer to pod: %v", err)
+			}
+			rsName := objutil.CacheName(rs)
+			err := v.DeleteObject(typeinfo.ReplicaSetDescriptor.GVK, rsName, mkapi.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationForeground)})
+			if err != nil {
+				t.Fatalf("failed to delete replicaset: %v", err)
+			}
+			waitForRemoval(t, v, typeinfo.PodsDescriptor.GVK, objutil.CacheName(pods[1]))
+			obj, err := v.GetObject(typeinfo.ReplicaSetDescriptor.GVK, rsName)
+			if err != nil {
+				t.Fatalf("expected replicaset to wait for the removal of its blocking dependents: %v", err)
+			}
+			if got := obj.(*appsv1.ReplicaSet); got.DeletionTimestamp == nil || len(got.Finalizers) != 1 || got.Finalizers[0] != metav1.FinalizerDeleteDependents {
+				t.Errorf("expected replicaset to be marked for deletion with finalizer %q, got %v", metav1.FinalizerDeleteDependents, got.Finalizers)
+			}
+			patch := `{"metadata":{"finalizers":null}}`
+			if _, _, err = v.PatchObject(typeinfo.PodsDescriptor.GVK, objutil.CacheName(protectedPod), types.MergePatchType, []byte(patch), mkapi.PatchOptions{}); err != nil {
+				t.Fatalf("failed to remove finalizer of pod: %v", err)
+			}
+			waitForRemoval(t, v, typeinfo.ReplicaSetDescriptor.GVK, rsName)
+		})
+	}
+}
+
+func TestDependentWithOtherOwnersIsReleased(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, v := range []mkapi.View{b, s} {
+		t.Run(v.GetName(), func(t *testing.T) {
+			b.Reset()
+			s.Reset()
+			_, rs, pods := storeWorkload(t, b)
+			otherOwner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-owner", Namespace: rs.Namespace, UID: uuid.NewUUID()}}
+			if err := b.CreateObject(typeinfo.ConfigMapsDescriptor.GVK, otherOwner); err != nil {
+				t.Fatalf("failed to create other owner: %v", err)
+			}
+			sharedPod := pods[0].DeepCopy()
+			sharedPod.OwnerReferences = append(sharedPod.OwnerReferences, metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: otherOwner.Name, UID: otherOwner.UID})
+			sharedPod.ResourceVersion = ""
+			if err := b.UpdateObject(typeinfo.PodsDescriptor.GVK, sharedPod); err != nil {
+				t.Fatalf("failed to add owner reference to pod: %v", err)
+			}
+			if err := v.DeleteObject(typeinfo.ReplicaSetDescriptor.GVK, objutil.CacheName(rs), mkapi.DeleteOptions{}); err != nil {
+				t.Fatalf("failed to delete replicaset: %v", err)
+			}
+			waitForRemoval(t, v, typeinfo.PodsDescriptor.GVK, objutil.CacheName(pods[1]))
+			waitFor(t, func() bool {
+				got, err := getPod(t, v, sharedPod.Namespace, sharedPod.Name)
+				return err == nil && len(got.OwnerReferences) == 1 && got.OwnerReferences[0].UID == otherOwner.UID
+			})
+		})
+	}
+}
+
+// storeWorkload stores a deployment owning a replicaset owning two pods in the given view.
+func storeWorkload(t *testing.T, v mkapi.View) (deploy *appsv1.Deployment, rs *appsv1.ReplicaSet, pods []*corev1.Pod) {
+	t.Helper()
+	deploy = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: uuid.NewUUID()}}
+	if err := v.CreateObject(typeinfo.DeploymentDescriptor.GVK, deploy); err != nil {
+		t.Fatalf("failed to create deployment: %v", err)
+	}
+	rs = &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "web-1",
+		Namespace:       deploy.Namespace,
+		UID:             uuid.NewUUID(),
+		OwnerReferences: []metav1.OwnerReference{controllerRef("apps/v1", "Deployment", deploy.Name, deploy.UID)},
+	}}
+	if err := v.CreateObject(typeinfo.ReplicaSetDescriptor.GVK, rs); err != nil {
+		t.Fatalf("failed to create replicaset: %v", err)
+	}
+	for _, name := range []string{"web-1-a", "web-1-b"} {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rs.Namespace,
+			UID:             uuid.NewUUID(),
+			OwnerReferences: []metav1.OwnerReference{controllerRef("apps/v1", "ReplicaSet", rs.Name, rs.UID)},
+		}}
+		if err := v.CreateObject(typeinfo.PodsDescriptor.GVK, p); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		pods = append(pods, p)
+	}
+	return
+}
+
+func controllerRef(apiVersion, kind, name string, uid types.UID) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         apiVersion,
+		Kind:               kind,
+		Name:               name,
+		UID:                uid,
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+}
+
+func assertWorkloadInView(t *testing.T, v mkapi.View, deploy *appsv1.Deployment, rs *appsv1.ReplicaSet, pods []*corev1.Pod) {
+	t.Helper()
+	if _, err := v.GetObject(typeinfo.DeploymentDescriptor.GVK, objutil.CacheName(deploy)); err != nil {
+		t.Errorf("expected deployment to remain in view %q: %v", v.GetName(), err)
+	}
+	obj, err := v.GetObject(typeinfo.ReplicaSetDescriptor.GVK, objutil.CacheName(rs))
+	if err != nil {
+		t.Errorf("expected replicaset to remain in view %q: %v", v.GetName(), err)
+	} else if len(obj.(*appsv1.ReplicaSet).OwnerReferences) != 1 {
+		t.Errorf("expected owner reference of replicaset to remain in view %q", v.GetName())
+	}
+	for _, p := range pods {
+		if _, err = getPod(t, v, p.Namespace, p.Name); err != nil {
+			t.Errorf("expected pod to remain in view %q: %v", v.GetName(), err)
+		}
+	}
+}
+
+func waitForRemoval(t *testing.T, v mkapi.View, gvk schema.GroupVersionKind, objName cache.ObjectName) {
+	t.Helper()
+	waitFor(t, func() bool {
+		_, err := v.GetObject(gvk, objName)
+		return apierrors.IsNotFound(err)
+	})
+	if t.Failed() {
+		t.Fatalf("expected %s %q to be removed from view %q", gvk.Kind, objName, v.GetName())
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return condition(), nil
+	})
+	if err != nil {
+		t.Errorf("condition not met: %v", err)
+	}
+}
@@ -10,21 +10,25 @@ import (
 	commontypes "github.com/gardener/scaling-advisor/api/common/types"
 	"github.com/gardener/scaling-advisor/api/minkapi"
 	"github.com/gardener/scaling-advisor/common/objutil"
-	"github.com/gardener/scaling-advisor/minkapi/server/eventsink"
 	"github.com/gardener/scaling-advisor/minkapi/server/store"
 	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
 	"github.com/go-logr/logr"
 	"golang.org/x/sync/errgroup"
+	"io"
 	corev1 "k8s.io/api/core/v1"
 	eventsv1 "k8s.io/api/events/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"math"
+	"slices"
 	"sync"
 	"sync/atomic"
 )
@@ -39,6 +43,10 @@ type sandboxView struct {
 	stores       map[schema.GroupVersionKind]*store.InMemResourceStore
 	eventSink    minkapi.EventSink
 	changeCount  atomic.Int64
+	gc           *garbageCollector
+	// deleted holds per GVK the UIDs by key of the objects of the delegate view that have been deleted in the sandbox.
+	// These objects are hidden by the sandbox so that deletions leave the delegate view untouched.
+	deleted map[schema.GroupVersionKind]map[string]types.UID
 }
 
 // NewSandbox returns a "sandbox" (private) view which holds changes made via its facade into its private store independent of the base view,
@@ -53,31 +61,52 @@ func NewSandbox(log logr.Logger, delegateView minkapi.View, args *minkapi.ViewAr
 		stores[d.GVK] = createInMemStore(log, d, baseStore.GetVersionCounter(), args)
 		//stores[d.GVK] = store.NewInMemResourceStore(d.GVK, d.ListGVK, d.GVR.GroupResource().Resource, args.WatchConfig.QueueSize, args.WatchConfig.Timeout, typeinfo.SupportedScheme, log)
 	}
-	eventSink := eventsink.New(log)
-	return &sandboxView{
+	v := &sandboxView{
 		log:          log,
 		args:         args,
 		stores:       stores,
 		mu:           &sync.RWMutex{},
-		eventSink:    eventSink,
+		eventSink:    newEventSink(log, args, stores),
 		delegateView: delegateView,
-	}, nil
+		deleted:      make(map[schema.GroupVersionKind]map[string]types.UID),
+	}
+	v.gc = newGarbageCollector(log, v)
+	v.gc.watchStores(stores)
+	return v, nil
 }
 
 func (v *sandboxView) Reset() {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	resetStores(v.stores)
+	// stores of custom resources defined in the delegate view are created again on access.
+	if err := syncCustomResourceStores(v.log, v.args, v.stores, nil, newVersionCounter); err != nil {
+		v.log.Error(err, "failed to remove custom resource stores", "view", v.args.Name)
+	}
+	v.gc.restartWatches(v.stores)
+	clear(v.deleted)
 	v.changeCount.Store(0)
 	v.eventSink.Reset()
 }
 
 func (v *sandboxView) Close() error {
+	v.gc.stop()
 	v.mu.RLock()
 	defer v.mu.RUnlock()
 	return closeStores(v.stores)
 }
 
+// Save writes the objects of the sandbox merged with those of the delegate view.
+func (v *sandboxView) Save(w io.Writer) error {
+	return saveView(v, w)
+}
+
+// Load is not supported for sandbox views since a sandbox only holds its changes relative to its delegate view. Load
+// the archive into a base view instead.
+func (v *sandboxView) Load(_ io.Reader) error {
+	return fmt.Errorf("%w: loading is not supported for sandbox view %q", minkapi.ErrLoadView, v.args.Name)
+}
+
 func (v *sandboxView) GetName() string {
 	return v.args.Name
 }
@@ -86,6 +115,10 @@ func (v *sandboxView) GetType() minkapi.ViewType {
 	return minkapi.SandboxViewType
 }
 
+func (v *sandboxView) GetClock() clock.WithTickerAndDelayedExecution {
+	return viewClock(v.args)
+}
+
 func (v *sandboxView) GetObjectChangeCount() int64 {
 	return v.changeCount.Load()
 }
@@ -97,14 +130,21 @@ func (v *sandboxView) GetClientFacades() (clientFacades commontypes.ClientFacade
 		}
 	}()
 	panic("inmem client type to be implemented")
-	return
-	return
 }
 
 func (v *sandboxView) GetResourceStore(gvk schema.GroupVersionKind) (minkapi.ResourceStore, error) {
 	v.mu.RLock()
-	defer v.mu.RUnlock()
 	s, exists := v.stores[gvk]
+	v.mu.RUnlock()
+	if !exists && !typeinfo.SupportedScheme.Recognizes(gvk) {
+		// the custom resource may have been defined in the delegate view since the stores were last synced.
+		if err := v.syncCustomResourceStores(); err != nil {
+			return nil, err
+		}
+		v.mu.RLock()
+		s, exists = v.stores[gvk]
+		v.mu.RUnlock()
+	}
 	if !exists {
 		return nil, fmt.Errorf("%w: store not found for GVK %q in view %q", minkapi.ErrStoreNotFound, gvk, v.args.Name)
 	}
@@ -115,19 +155,133 @@ func (v *sandboxView) GetEventSink() minkapi.EventSink {
 	return v.eventSink
 }
 
+func (v *sandboxView) GetAuditSink() minkapi.AuditSink {
+	return v.args.AuditSink
+}
+
 func (v *sandboxView) CreateObject(gvk schema.GroupVersionKind, obj metav1.Object) error {
-	return storeObject(v, gvk, obj, &v.changeCount)
+	isCRD := gvk == typeinfo.CustomResourceDefinitionsDescriptor.GVK
+	if isCRD {
+		if err := prepareCustomResourceDefinition(obj); err != nil {
+			return err
+		}
+	}
+	if err := storeObject(v, gvk, obj, &v.changeCount); err != nil {
+		return err
+	}
+	if isCRD {
+		return v.syncCustomResourceStores()
+	}
+	return nil
+}
+
+// syncCustomResourceStores creates and removes the stores of custom resources according to the CustomResourceDefinitions
+// in both the sandbox and the delegate view. The store of a custom resource also defined in the delegate view shares the
+// version counter of the delegate store.
+func (v *sandboxView) syncCustomResourceStores() error {
+	descriptors, err := CustomResourceDescriptors(v)
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	err = syncCustomResourceStores(v.log, v.args, v.stores, descriptors, func(gvk schema.GroupVersionKind) *atomic.Int64 {
+		if delegateStore, err := v.delegateView.GetResourceStore(gvk); err == nil {
+			return delegateStore.GetVersionCounter()
+		}
+		return newVersionCounter(gvk)
+	})
+	v.gc.watchStores(v.stores)
+	return err
+}
+
+// delegateServes returns whether the delegate view has a store for the given gvk. It does not for custom resources
+// defined only in the sandbox.
+func (v *sandboxView) delegateServes(gvk schema.GroupVersionKind) bool {
+	_, err := v.delegateView.GetResourceStore(gvk)
+	return err == nil
 }
 
 func (v *sandboxView) GetObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (obj runtime.Object, err error) {
 	obj, err = v.getSandboxObject(gvk, objName)
-	if obj != nil || !apierrors.IsNotFound(err) {
+	if obj != nil || !apierrors.IsNotFound(err) || !v.delegateServes(gvk) {
 		// return if I found the object or get an error other than not found error
 		return
 	}
+	notFoundErr := err
 	obj, err = v.delegateView.GetObject(gvk, objName)
+	if err == nil && v.isDeletedObject(gvk, obj) {
+		return nil, notFoundErr
+	}
 	return
 }
+
+// getDelegateObject returns the object with the given name of the delegate view unless it has been deleted in the
+// sandbox, in which case nil is returned.
+func (v *sandboxView) getDelegateObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (runtime.Object, error) {
+	obj, err := v.delegateView.GetObject(gvk, objName)
+	if err != nil {
+		return nil, ignoreNotFound(err)
+	}
+	if v.isDeletedObject(gvk, obj) {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// isDeletedObject returns whether the given object of the delegate view has been deleted in the sandbox.
+func (v *sandboxView) isDeletedObject(gvk schema.GroupVersionKind, obj runtime.Object) bool {
+	mo, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return v.isDeleted(gvk, mo)
+}
+
+// isOverriddenObject returns whether the given object of the delegate view has been copied into the sandbox, which
+// then holds its state in place of the delegate view.
+func (v *sandboxView) isOverriddenObject(gvk schema.GroupVersionKind, obj runtime.Object) bool {
+	mo, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	_, err = v.getSandboxObject(gvk, objutil.CacheName(mo))
+	return err == nil
+}
+
+// isDeleted returns whether the given object of the delegate view has been deleted in the sandbox. An object recreated
+// in the delegate view under the same name is not deleted in the sandbox.
+func (v *sandboxView) isDeleted(gvk schema.GroupVersionKind, mo metav1.Object) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	uid, ok := v.deleted[gvk][objutil.CacheName(mo).String()]
+	return ok && uid == mo.GetUID()
+}
+
+// withoutDeleted removes the objects of the delegate view that have been deleted in the sandbox from the given items.
+func (v *sandboxView) withoutDeleted(gvk schema.GroupVersionKind, items []metav1.Object) []metav1.Object {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	deleted := v.deleted[gvk]
+	if len(deleted) == 0 {
+		return items
+	}
+	return slices.DeleteFunc(items, func(mo metav1.Object) bool {
+		uid, ok := deleted[objutil.CacheName(mo).String()]
+		return ok && uid == mo.GetUID()
+	})
+}
+
+// markDeleted records that the given object of the delegate view has been deleted in the sandbox.
+func (v *sandboxView) markDeleted(gvk schema.GroupVersionKind, mo metav1.Object) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.deleted[gvk] == nil {
+		v.deleted[gvk] = make(map[string]types.UID)
+	}
+	v.deleted[gvk][objutil.CacheName(mo).String()] = mo.GetUID()
+}
+
 func (v *sandboxView) getSandboxObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (obj runtime.Object, err error) {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
@@ -144,61 +298,81 @@ func (v *sandboxView) UpdateObject(gvk schema.GroupVersionKind, obj metav1.Objec
 		return err
 	}
 	if sandboxObj != nil { //sandbox object is being updated.
-		return updateObject(v, gvk, obj, &v.changeCount)
+		if gvk != typeinfo.CustomResourceDefinitionsDescriptor.GVK {
+			return updateObject(v, gvk, obj, &v.changeCount)
+		}
+		if err = prepareCustomResourceDefinition(obj); err != nil {
+			return err
+		}
+		if err = updateObject(v, gvk, obj, &v.changeCount); err != nil {
+			return err
+		}
+		return v.syncCustomResourceStores()
+	}
+	if !v.delegateServes(gvk) {
+		return err
 	}
 	// The object is in base view and should not be modified - store in sandbox view now.
-	return v.CreateObject(gvk, obj)
-}
-
-func (v *sandboxView) UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (pod *corev1.Pod, err error) {
-	gvk := typeinfo.PodsDescriptor.GVK
-	obj, err := v.getSandboxObject(gvk, podName) // get pod from sandbox first.
+	notFoundErr := err
+	baseObj, err := v.delegateView.GetObject(gvk, objName)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return
+		return err
 	}
-	// TODO: make the below a bit generic later using functional programming
-	var ok bool
-	if obj != nil { // pod is found in sandbox view update pod node binding directly
-		pod, ok = obj.(*corev1.Pod)
-		if !ok {
-			err = fmt.Errorf("%w: cannot update pod node binding in %q view since obj %T for name %q not a corev1.Pod", minkapi.ErrUpdateObject, v.GetName(), obj, podName)
-			return
+	if baseObj != nil && v.isDeletedObject(gvk, baseObj) {
+		return notFoundErr
+	}
+	if baseObj != nil {
+		baseMo, err := meta.Accessor(baseObj)
+		if err != nil {
+			return fmt.Errorf("stored object with key %q is not metav1.Object: %w", objName, err)
+		}
+		v.mu.RLock()
+		s := v.stores[gvk]
+		v.mu.RUnlock()
+		if err = s.CheckResourceVersionPrecondition(obj, baseMo); err != nil {
+			return err
 		}
-		return updatePodNodeBinding(v, pod, binding)
 	}
-	// pod is not found in sandbox. now get from base
-	obj, err = v.delegateView.GetObject(gvk, podName)
+	return v.CreateObject(gvk, obj)
+}
+
+func (v *sandboxView) UpdatePodNodeBinding(podName cache.ObjectName, binding corev1.Binding) (prevPod, pod *corev1.Pod, err error) {
+	obj, err := v.GetObject(typeinfo.PodsDescriptor.GVK, podName) // get pod from sandbox first, otherwise from base.
 	if err != nil {
 		return
 	}
-	pod, ok = obj.(*corev1.Pod)
+	prevPod, ok := obj.(*corev1.Pod)
 	if !ok {
 		err = fmt.Errorf("%w: cannot update pod node binding in %q view since obj %T for name %q not a corev1.Pod", minkapi.ErrUpdateObject, v.GetName(), obj, podName)
-	}
-	// found in base so lets make a copy and store in sandbox
-	sandboxPod := pod.DeepCopy()
-	err = v.CreateObject(gvk, sandboxPod)
-	if err != nil {
 		return
 	}
-	pod = sandboxPod
-	return updatePodNodeBinding(v, pod, binding)
+	// UpdateObject stores the bound copy of a base pod in the sandbox.
+	pod, err = updatePodNodeBinding(v, prevPod.DeepCopy(), binding)
+	return
+}
+
+func (v *sandboxView) PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return patchObject(v, gvk, objName, patchType, patchData, opts)
 }
 
-func (v *sandboxView) PatchObject(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte) (patchedObj runtime.Object, err error) {
-	return patchObject(v, gvk, objName, patchType, patchData)
+func (v *sandboxView) ApplyObject(gvk schema.GroupVersionKind, objName cache.ObjectName, applyData []byte, opts minkapi.ApplyOptions) (prevObj, appliedObj runtime.Object, err error) {
+	return applyObject(v, gvk, objName, applyData, opts)
 }
 
-func (v *sandboxView) PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchData []byte) (patchedObj runtime.Object, err error) {
-	return patchObjectStatus(v, gvk, objName, patchData)
+func (v *sandboxView) PatchObjectStatus(gvk schema.GroupVersionKind, objName cache.ObjectName, patchType types.PatchType, patchData []byte, opts minkapi.PatchOptions) (prevObj, patchedObj runtime.Object, err error) {
+	return patchObjectStatus(v, gvk, objName, patchType, patchData, opts)
 }
 
 func (v *sandboxView) ListMetaObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria) (items []metav1.Object, maxVersion int64, err error) {
 	sandboxItems, myMax, err := listMetaObjects(v, gvk, criteria)
+	if err != nil || !v.delegateServes(gvk) {
+		return sandboxItems, myMax, err
+	}
+	delegateItems, delegateMax, err := v.delegateView.ListMetaObjects(gvk, criteria)
 	if err != nil {
 		return
 	}
-	delegateItems, delegateMax, err := v.delegateView.ListMetaObjects(gvk, criteria)
+	delegateItems, err = v.withoutOverridden(gvk, criteria, delegateItems)
 	if err != nil {
 		return
 	}
@@ -210,65 +384,255 @@ func (v *sandboxView) ListMetaObjects(gvk schema.GroupVersionKind, criteria mink
 	items = combinePrimarySecondary(sandboxItems, delegateItems)
 	return
 }
+
+// withoutOverridden removes the objects of the delegate view that have been modified or deleted in the sandbox from
+// the given items, which were listed from the delegate view with the given criteria. A modified object is listed from
+// the sandbox only, so that its stale delegate copy is not returned when the sandbox copy no longer matches.
+func (v *sandboxView) withoutOverridden(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, items []metav1.Object) ([]metav1.Object, error) {
+	items = v.withoutDeleted(gvk, items)
+	if len(items) == 0 {
+		return items, nil
+	}
+	overridden, _, err := listMetaObjects(v, gvk, minkapi.MatchCriteria{Namespace: criteria.Namespace, Names: criteria.Names})
+	if err != nil || len(overridden) == 0 {
+		return items, err
+	}
+	overriddenNames := make(sets.Set[cache.ObjectName], len(overridden))
+	for _, mo := range overridden {
+		overriddenNames.Insert(objutil.CacheName(mo))
+	}
+	return slices.DeleteFunc(items, func(mo metav1.Object) bool {
+		return overriddenNames.Has(objutil.CacheName(mo))
+	}), nil
+}
+
 func (v *sandboxView) ListObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria) (listObj runtime.Object, err error) {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
 		return
 	}
-	items, maxVersion, err := v.ListMetaObjects(gvk, criteria) // v.ListMetaObjcts already invokes delegate
+	// the sandbox store shares its version counter with the delegate store, so it reflects changes made in either.
+	currVersion := s.GetVersionCounter().Load()
+	page, listMeta, err := store.PageMetaObjects(currVersion, criteria, func() ([]metav1.Object, error) {
+		items, _, err := v.ListMetaObjects(gvk, criteria) // v.ListMetaObjcts already invokes delegate
+		return items, err
+	}, func(version int64) ([]metav1.Object, error) {
+		return v.listMetaObjectsAt(gvk, criteria, version)
+	})
 	if err != nil {
 		return
 	}
 	objGVK, objListKind := s.GetObjAndListGVK()
-	return store.WrapMetaObjectsIntoRuntimeListObject(maxVersion, objGVK, objListKind, items)
+	return store.WrapMetaObjectsIntoRuntimeListObject(listMeta, objGVK, objListKind, page)
 }
 
-func (v *sandboxView) WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, startVersion int64, namespace string, labelSelector labels.Selector, eventCallback minkapi.WatchEventCallback) error {
+// listMetaObjectsAt returns the objects matching the given criteria as they were at the given version like
+// ListMetaObjects, reconstructing the objects of both the sandbox and the delegate view from the history of their
+// stores. An object of the delegate view deleted in the sandbox is only hidden if it had been copied into the sandbox
+// by the given version, since it was not deleted yet otherwise.
+func (v *sandboxView) listMetaObjectsAt(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, version int64) ([]metav1.Object, error) {
+	v.mu.RLock()
+	s, ok := v.stores[gvk]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: store not found for GVK %q in view %q", minkapi.ErrStoreNotFound, gvk, v.args.Name)
+	}
+	sandboxItems, err := s.ListMetaObjectsAt(criteria, version)
+	if err != nil || !v.delegateServes(gvk) {
+		return sandboxItems, err
+	}
+	ds, err := v.delegateView.GetResourceStore(gvk)
+	if err != nil {
+		return nil, err
+	}
+	delegateItems, err := ds.ListMetaObjectsAt(criteria, version)
+	if err != nil {
+		return nil, err
+	}
+	overridden, err := s.ListMetaObjectsAt(minkapi.MatchCriteria{Namespace: criteria.Namespace, Names: criteria.Names}, version)
+	if err != nil {
+		return nil, err
+	}
+	copied, err := s.AddedSince(version)
+	if err != nil {
+		return nil, err
+	}
+	overriddenNames := make(sets.Set[cache.ObjectName], len(overridden))
+	for _, mo := range overridden {
+		overriddenNames.Insert(objutil.CacheName(mo))
+	}
+	v.mu.RLock()
+	deleted := v.deleted[gvk]
+	delegateItems = slices.DeleteFunc(delegateItems, func(mo metav1.Object) bool {
+		if overriddenNames.Has(objutil.CacheName(mo)) {
+			return true
+		}
+		key := objutil.CacheName(mo).String()
+		uid, ok := deleted[key]
+		return ok && uid == mo.GetUID() && copied[key] != uid
+	})
+	v.mu.RUnlock()
+	return combinePrimarySecondary(sandboxItems, delegateItems), nil
+}
+
+// WatchObjects watches objects in both the sandbox and the delegate view. Initial events are computed by the sandbox
+// itself so that objects overridden in the sandbox are sent only once, after which both views are watched from the
+// version of the initial events. Since both watches progress independently, bookmarks are merged and only sent once
+// both watches have reached the bookmarked version.
+func (v *sandboxView) WatchObjects(ctx context.Context, gvk schema.GroupVersionKind, opts minkapi.WatchOptions, eventCallback minkapi.WatchEventCallback) error {
 	s, err := v.GetResourceStore(gvk)
 	if err != nil {
 		return err
 	}
-	var eg errgroup.Group
+	subOpts := opts
+	subOpts.SendInitialEvents = false
+	if opts.SendInitialEvents || opts.StartVersion <= 0 {
+		version := s.GetVersionCounter().Load()
+		items, _, err := v.ListMetaObjects(gvk, opts.MatchCriteria)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			obj, ok := item.(runtime.Object)
+			if !ok {
+				return apierrors.NewInternalError(fmt.Errorf("object %q of type %T is not a runtime.Object", objutil.CacheName(item), item))
+			}
+			if err = eventCallback(watch.Event{Type: watch.Added, Object: obj}); err != nil {
+				return err
+			}
+		}
+		if opts.SendInitialEvents {
+			if err = eventCallback(store.NewBookmarkEvent(gvk, version, true)); err != nil {
+				return err
+			}
+		}
+		subOpts.StartVersion = version
+	}
+
+	// both watches share the callback, so invocations are serialized. The first failing watch (Ex: 410 Expired) ends the other.
+	var (
+		callbackMu                      sync.Mutex
+		sandboxVersion, delegateVersion int64
+		lastBookmarkVersion             int64
+	)
+	mergingCallback := func(progress *int64, fromDelegate bool) minkapi.WatchEventCallback {
+		return func(event watch.Event) error {
+			callbackMu.Lock()
+			defer callbackMu.Unlock()
+			if event.Type != watch.Bookmark {
+				if fromDelegate && (v.isDeletedObject(gvk, event.Object) || v.isOverriddenObject(gvk, event.Object)) {
+					// changes to objects of the delegate view deleted or overridden in the sandbox are not visible in
+					// the sandbox, like in the lists of the sandbox.
+					return nil
+				}
+				return eventCallback(event)
+			}
+			mo, err := store.AsMeta(event.Object)
+			if err != nil {
+				return err
+			}
+			*progress, err = store.ParseObjectResourceVersion(mo)
+			if err != nil {
+				return err
+			}
+			bookmarkVersion := min(sandboxVersion, delegateVersion)
+			if bookmarkVersion <= lastBookmarkVersion {
+				return nil
+			}
+			lastBookmarkVersion = bookmarkVersion
+			return eventCallback(store.NewBookmarkEvent(gvk, bookmarkVersion, false))
+		}
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		v.log.Info("watching sandboxView objects", "gvk", gvk, "startVersion", startVersion, "namespace", namespace, "labelSelector", labelSelector)
-		return s.Watch(ctx, startVersion, namespace, labelSelector, eventCallback)
+		v.log.Info("watching sandboxView objects", "gvk", gvk, "startVersion", subOpts.StartVersion, "criteria", subOpts.MatchCriteria)
+		return s.Watch(egCtx, subOpts, mergingCallback(&sandboxVersion, false))
 	})
+	if !v.delegateServes(gvk) {
+		// custom resources defined only in the sandbox have no delegate watch whose progress holds back bookmarks.
+		delegateVersion = math.MaxInt64
+		return eg.Wait()
+	}
 	eg.Go(func() error {
-		v.log.Info("watching delegateView objects", "gvk", gvk, "startVersion", startVersion, "namespace", namespace, "labelSelector", labelSelector)
-		return v.delegateView.WatchObjects(ctx, gvk, startVersion, namespace, labelSelector, eventCallback)
+		v.log.Info("watching delegateView objects", "gvk", gvk, "startVersion", subOpts.StartVersion, "criteria", subOpts.MatchCriteria)
+		return v.delegateView.WatchObjects(egCtx, gvk, subOpts, mergingCallback(&delegateVersion, true))
 	})
 	return eg.Wait()
 }
 
-func (v *sandboxView) DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName) error {
-	s, err := v.GetResourceStore(gvk)
-	if err != nil {
-		return err
-	}
-	obj, err := s.GetByKey(objName.String())
+// DeleteObject deletes the object with the given name in the sandbox. An object of the delegate view is copied into
+// the sandbox and deleted there, honouring its finalizers and the given opts, and is hidden by the sandbox once removed.
+// The delegate view is left untouched.
+func (v *sandboxView) DeleteObject(gvk schema.GroupVersionKind, objName cache.ObjectName, opts minkapi.DeleteOptions) error {
+	sandboxObj, err := v.getSandboxObject(gvk, objName)
 	if err != nil && !apierrors.IsNotFound(err) {
 		return err
 	}
-	if obj == nil {
-		// delegate to delegateView if obj not found in sandbox
-		return v.delegateView.DeleteObject(gvk, objName)
+	notFoundErr := err
+	var delegateObj runtime.Object
+	if v.delegateServes(gvk) {
+		if delegateObj, err = v.getDelegateObject(gvk, objName); err != nil {
+			return err
+		}
 	}
-	// if found in this views store, delete and return
-	err = s.Delete(objName)
-	if err != nil {
+	if sandboxObj == nil && delegateObj == nil {
+		return notFoundErr
+	}
+	v.mu.RLock()
+	s := v.stores[gvk]
+	v.mu.RUnlock()
+	var delegateMo metav1.Object
+	if delegateObj != nil {
+		if delegateMo, err = meta.Accessor(delegateObj); err != nil {
+			return err
+		}
+	}
+	if sandboxObj == nil {
+		if err = s.CheckDeletePreconditions(delegateMo, opts.Preconditions); err != nil {
+			return err
+		}
+		copied, err := meta.Accessor(delegateObj.DeepCopyObject())
+		if err != nil {
+			return err
+		}
+		if err = s.Add(copied); err != nil {
+			return err
+		}
+		// the copy has a resource version of its own, against which the preconditions must not be checked again.
+		opts.Preconditions = metav1.Preconditions{}
+	}
+	if err = s.Delete(objName, opts); err != nil {
 		return err
 	}
+	if delegateMo != nil {
+		v.markDeleted(gvk, delegateMo)
+	}
 	v.changeCount.Add(1)
+	if gvk == typeinfo.CustomResourceDefinitionsDescriptor.GVK {
+		return v.syncCustomResourceStores()
+	}
 	return nil
-
 }
 
-func (v *sandboxView) DeleteObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria) error {
-	err := deleteObjects(v, gvk, criteria, &v.changeCount)
+// DeleteObjects deletes the objects matching the given criteria in the sandbox like DeleteObject, leaving the delegate
+// view untouched.
+func (v *sandboxView) DeleteObjects(gvk schema.GroupVersionKind, criteria minkapi.MatchCriteria, opts minkapi.DeleteOptions) (delObjs []metav1.Object, err error) {
+	items, _, err := v.ListMetaObjects(gvk, criteria)
 	if err != nil {
-		return err
+		return
 	}
-	return v.delegateView.DeleteObjects(gvk, criteria)
+	for _, item := range items {
+		if err = v.DeleteObject(gvk, objutil.CacheName(item), opts); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			err = fmt.Errorf("%w: %w", minkapi.ErrDeleteObject, err)
+			return
+		}
+		delObjs = append(delObjs, item)
+	}
+	err = nil
+	return
 }
 
 func (v *sandboxView) ListNodes(matchingNodeNames ...string) (nodes []corev1.Node, err error) {
@@ -296,16 +660,8 @@ func (v *sandboxView) ListPods(namespace string, matchingPodNames ...string) (po
 	return
 }
 
-func (v *sandboxView) ListEvents(namespace string) (events []eventsv1.Event, err error) {
-	metaObjs, _, err := v.ListMetaObjects(typeinfo.EventsDescriptor.GVK, minkapi.MatchCriteria{
-		Namespace: namespace,
-	})
-	if err != nil {
-		return
-	}
-	events, _, err = asEvents(metaObjs)
-	return
-
+func (v *sandboxView) ListEvents(namespace string, regarding ...corev1.ObjectReference) ([]eventsv1.Event, error) {
+	return listEvents(v, namespace, regarding)
 }
 
 func (v *sandboxView) GetKubeConfigPath() string {
@@ -5,6 +5,7 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
 	"github.com/gardener/scaling-advisor/common/objutil"
@@ -13,10 +14,21 @@ import (
 	gocmp "github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"maps"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -293,6 +305,158 @@ func TestBasePodSandboxNodeBinding(t *testing.T) {
 	}
 
 }
+func TestSandboxWatchInitialEvents(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	nA := testNodes[0]
+	nB := *nA.DeepCopy()
+	nB.Name = nA.Name + "-b"
+	if err = storeNode(t, b, &nA); err != nil {
+		return
+	}
+	if err = storeNode(t, b, &nB); err != nil {
+		return
+	}
+	nAWithLabel := *nA.DeepCopy()
+	nAWithLabel.Labels = map[string]string{"sandbox": "true"}
+	if err = s.UpdateObject(typeinfo.NodesDescriptor.GVK, &nAWithLabel); err != nil {
+		t.Fatalf("in view %q, failed to update node: %v", s.GetName(), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	opts := mkapi.WatchOptions{SendInitialEvents: true, AllowBookmarks: true}
+	var gotEvents []string
+	err = s.WatchObjects(ctx, typeinfo.NodesDescriptor.GVK, opts, func(event watch.Event) error {
+		mo, err := meta.Accessor(event.Object)
+		if err != nil {
+			return err
+		}
+		switch {
+		case event.Type != watch.Bookmark:
+			gotEvents = append(gotEvents, fmt.Sprintf("%s %s sandbox=%s", event.Type, mo.GetName(), mo.GetLabels()["sandbox"]))
+		case mo.GetAnnotations()[metav1.InitialEventsAnnotationKey] == "true":
+			gotEvents = append(gotEvents, "BOOKMARK initial-events-end")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to watch nodes in view %q: %v", s.GetName(), err)
+	}
+
+	// node A is overridden in the sandbox and must only be sent once.
+	wantEvents := []string{
+		fmt.Sprintf("ADDED %s sandbox=", nB.Name),
+		fmt.Sprintf("ADDED %s sandbox=true", nA.Name),
+		"BOOKMARK initial-events-end",
+	}
+	if diff := gocmp.Diff(wantEvents, gotEvents); diff != "" {
+		t.Errorf("watch events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestSandboxConcurrentReadersAndWriters uses views concurrently from in-process readers, writers and watchers that
+// mutate the objects they pass to and receive from the views. Run with -race.
+func TestSandboxConcurrentReadersAndWriters(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	const numPods, numUpdates = 4, 20
+	for i := range numPods {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default", Labels: map[string]string{"gen": "0"}}}
+		if err = b.CreateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		// the base view keeps its own copy of the created pod.
+		pod.Labels["creator"] = "mutated"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var readers, writers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		_ = s.WatchObjects(ctx, typeinfo.PodsDescriptor.GVK, mkapi.WatchOptions{}, func(event watch.Event) error {
+			mo, err := meta.Accessor(event.Object)
+			if err != nil {
+				return err
+			}
+			mo.SetLabels(map[string]string{"watcher": "mutated"})
+			return nil
+		})
+	}()
+	for _, v := range []mkapi.View{b, s} {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for ctx.Err() == nil {
+				pods, err := v.ListPods("default")
+				if err != nil {
+					t.Errorf("failed to list pods in view %q: %v", v.GetName(), err)
+					return
+				}
+				for _, p := range pods {
+					p.Labels["reader"] = "mutated"
+				}
+				obj, err := v.GetObject(typeinfo.PodsDescriptor.GVK, cache.NewObjectName("default", "pod-0"))
+				if err != nil {
+					t.Errorf("failed to get pod in view %q: %v", v.GetName(), err)
+					return
+				}
+				obj.(*corev1.Pod).Labels["reader"] = "mutated"
+			}
+		}()
+	}
+	for i := range numPods {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			name := cache.NewObjectName("default", fmt.Sprintf("pod-%d", i))
+			for gen := 1; gen <= numUpdates; gen++ {
+				obj, err := s.GetObject(typeinfo.PodsDescriptor.GVK, name)
+				if err != nil {
+					t.Errorf("failed to get pod %q: %v", name, err)
+					return
+				}
+				pod := obj.(*corev1.Pod)
+				pod.Labels = map[string]string{"gen": strconv.Itoa(gen)}
+				if err = s.UpdateObject(typeinfo.PodsDescriptor.GVK, pod); err != nil {
+					t.Errorf("failed to update pod %q: %v", name, err)
+					return
+				}
+				// the sandbox view keeps its own copy of the updated pod.
+				pod.Labels["writer"] = "mutated"
+			}
+		}()
+	}
+	writers.Wait()
+	cancel()
+	readers.Wait()
+
+	for _, tc := range []struct {
+		v       mkapi.View
+		wantGen int
+	}{{v: b, wantGen: 0}, {v: s, wantGen: numUpdates}} {
+		pods, err := tc.v.ListPods("default")
+		if err != nil {
+			t.Fatalf("failed to list pods in view %q: %v", tc.v.GetName(), err)
+		}
+		if len(pods) != numPods {
+			t.Errorf("expected %d pods in view %q, got %d", numPods, tc.v.GetName(), len(pods))
+		}
+		for _, p := range pods {
+			if want := map[string]string{"gen": strconv.Itoa(tc.wantGen)}; !maps.Equal(p.Labels, want) {
+				t.Errorf("expected pod %q in view %q to have labels %v, got %v", p.Name, tc.v.GetName(), want, p.Labels)
+			}
+		}
+	}
+}
+
 func setup(t *testing.T) (b mkapi.View, s mkapi.View, err error) {
 	t.Helper()
 	err = loadTestNodes(t)
@@ -363,7 +527,7 @@ func createViews(t *testing.T) (b mkapi.View, s mkapi.View, err error) {
 func updateBinding(t *testing.T, v mkapi.View, p *corev1.Pod, n *corev1.Node) (*corev1.Pod, error) {
 	t.Helper()
 	binding := createBinding(p, n)
-	pMod, err := v.UpdatePodNodeBinding(objutil.CacheName(p), binding)
+	_, pMod, err := v.UpdatePodNodeBinding(objutil.CacheName(p), binding)
 	if err != nil {
 		t.Fatalf("failed to update pod node binding: %v", err)
 		return nil, err
@@ -461,3 +625,417 @@ func checkNodeInViewIsSame(t *testing.T, v mkapi.View, n *corev1.Node) (diff str
 	}
 	return diff
 }
+
+func TestOptimisticConcurrencyConflicts(t *testing.T) {
+	tests := map[string]struct {
+		// change makes a change to the given node previously stored in the base view through the given view.
+		change func(v mkapi.View, n *corev1.Node, p *corev1.Pod) error
+	}{
+		"update with stale resourceVersion": {
+			change: func(v mkapi.View, n *corev1.Node, _ *corev1.Pod) error {
+				stale := n.DeepCopy()
+				stale.ResourceVersion = "1000"
+				stale.Labels = map[string]string{"stale": "true"}
+				return v.UpdateObject(typeinfo.NodesDescriptor.GVK, stale)
+			},
+		},
+		"patch with stale resourceVersion": {
+			change: func(v mkapi.View, n *corev1.Node, _ *corev1.Pod) error {
+				patch := `{"metadata":{"resourceVersion":"1000","labels":{"stale":"true"}}}`
+				_, _, err := v.PatchObject(typeinfo.NodesDescriptor.GVK, objutil.CacheName(n), types.MergePatchType, []byte(patch), mkapi.PatchOptions{})
+				return err
+			},
+		},
+		"bind already bound pod": {
+			change: func(v mkapi.View, n *corev1.Node, p *corev1.Pod) error {
+				if _, _, err := v.UpdatePodNodeBinding(objutil.CacheName(p), createBinding(p, n)); err != nil {
+					return fmt.Errorf("first binding must succeed: %w", err)
+				}
+				_, _, err := v.UpdatePodNodeBinding(objutil.CacheName(p), createBinding(p, n))
+				return err
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, s, err := setup(t)
+			if err != nil {
+				return
+			}
+			for _, v := range []mkapi.View{b, s} {
+				b.Reset()
+				s.Reset()
+				n := testNodes[0].DeepCopy()
+				if err = storeNode(t, b, n); err != nil {
+					return
+				}
+				p := testPods[0].DeepCopy()
+				p.Spec.NodeName = ""
+				if err = storePod(t, b, p); err != nil {
+					return
+				}
+				err = tc.change(v, n, p)
+				if !apierrors.IsConflict(err) {
+					t.Errorf("in view %q, expected conflict error, got: %v", v.GetName(), err)
+				}
+				if got, _ := getNode(t, v, n.Name); got.Labels["stale"] == "true" {
+					t.Errorf("in view %q, expected stale change to be rejected", v.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestPatchConflictingWithConcurrentWrite(t *testing.T) {
+	tests := map[string]struct {
+		patch        string
+		wantConflict bool
+	}{
+		"patch without resourceVersion": {
+			patch: `{"metadata":{"labels":{"patched":"true"}}}`,
+		},
+		"patch with resourceVersion": {
+			patch:        `{"metadata":{"resourceVersion":"%s","labels":{"patched":"true"}}}`,
+			wantConflict: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, s, err := setup(t)
+			if err != nil {
+				return
+			}
+			for _, v := range []mkapi.View{b, s} {
+				b.Reset()
+				s.Reset()
+				n := testNodes[0].DeepCopy()
+				if err = storeNode(t, b, n); err != nil {
+					return
+				}
+				patch := tc.patch
+				if tc.wantConflict {
+					patch = fmt.Sprintf(tc.patch, n.ResourceVersion)
+				}
+				cv := &concurrentlyWrittenView{View: v, t: t}
+				prevObj, _, err := patchObject(cv, typeinfo.NodesDescriptor.GVK, objutil.CacheName(n), types.MergePatchType, []byte(patch), mkapi.PatchOptions{})
+				if gotConflict := apierrors.IsConflict(err); gotConflict != tc.wantConflict {
+					t.Fatalf("in view %q, expected conflict %t, got: %v", v.GetName(), tc.wantConflict, err)
+				}
+				if !tc.wantConflict && prevObj.(*corev1.Node).Labels["concurrent"] != "true" {
+					t.Errorf("in view %q, expected previous object to include the concurrent write, got labels %v", v.GetName(), prevObj.(*corev1.Node).Labels)
+				}
+				got, err := getNode(t, v, n.Name)
+				if err != nil {
+					return
+				}
+				if got.Labels["concurrent"] != "true" {
+					t.Errorf("in view %q, expected concurrent write to be retained, got labels %v", v.GetName(), got.Labels)
+				}
+				if patched := got.Labels["patched"] == "true"; patched == tc.wantConflict {
+					t.Errorf("in view %q, expected patch to be applied %t, got labels %v", v.GetName(), !tc.wantConflict, got.Labels)
+				}
+			}
+		})
+	}
+}
+
+// concurrentlyWrittenView is a minkapi.View that updates the object it returns the first time it is read, as if it
+// was concurrently written by another client after the read.
+type concurrentlyWrittenView struct {
+	mkapi.View
+	t       *testing.T
+	written bool
+}
+
+func (v *concurrentlyWrittenView) GetObject(gvk schema.GroupVersionKind, objName cache.ObjectName) (runtime.Object, error) {
+	obj, err := v.View.GetObject(gvk, objName)
+	if err != nil || v.written {
+		return obj, err
+	}
+	v.written = true
+	mo, err := meta.Accessor(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	mo.SetLabels(map[string]string{"concurrent": "true"})
+	if err = v.UpdateObject(gvk, mo); err != nil {
+		v.t.Fatalf("failed to write object %q concurrently: %v", objName, err)
+	}
+	return obj, nil
+}
+
+func TestDeleteObjectWithFinalizers(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	for _, v := range []mkapi.View{b, s} {
+		t.Run(v.GetName(), func(t *testing.T) {
+			b.Reset()
+			s.Reset()
+			p := testPods[0].DeepCopy()
+			p.Finalizers = []string{"example.com/protect"}
+			if err := storePod(t, v, p); err != nil {
+				return
+			}
+			podName := objutil.CacheName(p)
+			if err := v.DeleteObject(typeinfo.PodsDescriptor.GVK, podName, mkapi.DeleteOptions{}); err != nil {
+				t.Fatalf("failed to delete pod: %v", err)
+			}
+			got, err := getPod(t, v, p.Namespace, p.Name)
+			if err != nil {
+				t.Fatalf("expected pod with finalizer to remain, got %v", err)
+			}
+			if got.DeletionTimestamp == nil {
+				t.Errorf("expected deletionTimestamp of pod to be set")
+			}
+			patch := `{"metadata":{"finalizers":null}}`
+			if _, _, err = v.PatchObject(typeinfo.PodsDescriptor.GVK, podName, types.MergePatchType, []byte(patch), mkapi.PatchOptions{}); err != nil {
+				t.Fatalf("failed to remove finalizer: %v", err)
+			}
+			if _, err = v.GetObject(typeinfo.PodsDescriptor.GVK, podName); !apierrors.IsNotFound(err) {
+				t.Errorf("expected pod to be removed once its finalizer is cleared, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSandboxListExcludesStaleDelegateObjects(t *testing.T) {
+	tests := map[string]struct {
+		criteria mkapi.MatchCriteria
+	}{
+		"field selector no longer matching sandbox copy": {
+			criteria: mkapi.MatchCriteria{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", "")},
+		},
+		"label selector no longer matching sandbox copy": {
+			criteria: mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "web"})},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, s, err := setup(t)
+			if err != nil {
+				return
+			}
+			n := testNodes[0].DeepCopy()
+			if err = storeNode(t, b, n); err != nil {
+				return
+			}
+			p := testPods[0].DeepCopy()
+			p.Spec.NodeName = ""
+			p.Labels = map[string]string{"app": "web"}
+			if err = storePod(t, b, p); err != nil {
+				return
+			}
+			pSandbox, err := updateBinding(t, s, p, n)
+			if err != nil {
+				return
+			}
+			pSandbox.Labels = map[string]string{"app": "db"}
+			if err = s.UpdateObject(typeinfo.PodsDescriptor.GVK, pSandbox); err != nil {
+				t.Fatalf("failed to update pod in sandbox: %v", err)
+			}
+
+			items, _, err := s.ListMetaObjects(typeinfo.PodsDescriptor.GVK, tc.criteria)
+			if err != nil {
+				t.Fatalf("failed to list pods in sandbox: %v", err)
+			}
+			for _, item := range items {
+				if objutil.CacheName(item) == objutil.CacheName(p) {
+					t.Errorf("expected pod %q modified in sandbox to not match, got %+v", objutil.CacheName(p), item)
+				}
+			}
+			items, _, err = b.ListMetaObjects(typeinfo.PodsDescriptor.GVK, tc.criteria)
+			if err != nil {
+				t.Fatalf("failed to list pods in base: %v", err)
+			}
+			if len(items) != 1 {
+				t.Errorf("expected unmodified pod %q to match in base view, got %d items", objutil.CacheName(p), len(items))
+			}
+		})
+	}
+}
+
+func TestSandboxIndexedListExcludesStaleDelegateObjects(t *testing.T) {
+	if err := loadTestPods(t); err != nil {
+		return
+	}
+	indexedBaseArgs, indexedSandboxArgs := baseViewArgs, sandboxViewArgs
+	indexedBaseArgs.IndexedLabelKeys = []string{"app"}
+	indexedSandboxArgs.IndexedLabelKeys = []string{"app"}
+	b, err := New(log, &indexedBaseArgs)
+	if err != nil {
+		t.Fatalf("failed to create base view: %v", err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+	s, err := NewSandbox(log, b, &indexedSandboxArgs)
+	if err != nil {
+		t.Fatalf("failed to create sandbox view: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	p := testPods[0].DeepCopy()
+	p.Spec.NodeName = ""
+	p.Labels = map[string]string{"app": "web"}
+	if err = storePod(t, b, p); err != nil {
+		return
+	}
+	pSandbox := p.DeepCopy()
+	pSandbox.Spec.NodeName = "node-a"
+	pSandbox.Labels = map[string]string{"app": "db"}
+	if err = s.UpdateObject(typeinfo.PodsDescriptor.GVK, pSandbox); err != nil {
+		t.Fatalf("failed to update pod in sandbox: %v", err)
+	}
+
+	webSelector, err := labels.Parse("app in (web)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both selectors are served by the spec.nodeName field index and the app label index of the stores.
+	for _, criteria := range []mkapi.MatchCriteria{
+		{FieldSelector: fields.OneTermEqualSelector("spec.nodeName", "")},
+		{LabelSelector: webSelector},
+	} {
+		items, _, err := s.ListMetaObjects(typeinfo.PodsDescriptor.GVK, criteria)
+		if err != nil {
+			t.Fatalf("failed to list pods in sandbox: %v", err)
+		}
+		if len(items) != 0 {
+			t.Errorf("expected no pods matching %+v in sandbox, got %d", criteria, len(items))
+		}
+	}
+	items, _, err := s.ListMetaObjects(typeinfo.PodsDescriptor.GVK, mkapi.MatchCriteria{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "db"})})
+	if err != nil {
+		t.Fatalf("failed to list pods in sandbox: %v", err)
+	}
+	if len(items) != 1 || items[0].GetLabels()["app"] != "db" {
+		t.Errorf("expected only sandbox copy of pod %q, got %v", objutil.CacheName(p), items)
+	}
+}
+
+func TestSandboxWatchExcludesDelegateEventsOfOverriddenObjects(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	n := testNodes[0].DeepCopy()
+	if err = storeNode(t, b, n); err != nil {
+		return
+	}
+	p := testPods[0].DeepCopy()
+	p.Spec.NodeName = ""
+	if err = storePod(t, b, p); err != nil {
+		return
+	}
+	q := testPods[0].DeepCopy()
+	q.Name = p.Name + "-q"
+	q.Spec.NodeName = ""
+	if err = storePod(t, b, q); err != nil {
+		return
+	}
+	if _, err = updateBinding(t, s, p, n); err != nil {
+		return
+	}
+	podStore, err := s.GetResourceStore(typeinfo.PodsDescriptor.GVK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	startVersion := podStore.GetVersionCounter().Load()
+
+	// the base view changes both pods after pod p has been bound in the sandbox, like in mirror mode.
+	for _, name := range []string{p.Name, q.Name} {
+		pBase, err := getPod(t, b, p.Namespace, name)
+		if err != nil {
+			return
+		}
+		pBase.Labels = map[string]string{"changed": "base"}
+		if err = b.UpdateObject(typeinfo.PodsDescriptor.GVK, pBase); err != nil {
+			t.Fatalf("failed to update pod %q in base view: %v", name, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var gotEvents []string
+	err = s.WatchObjects(ctx, typeinfo.PodsDescriptor.GVK, mkapi.WatchOptions{StartVersion: startVersion}, func(event watch.Event) error {
+		mo, err := meta.Accessor(event.Object)
+		if err != nil {
+			return err
+		}
+		gotEvents = append(gotEvents, fmt.Sprintf("%s %s", event.Type, mo.GetName()))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to watch pods in view %q: %v", s.GetName(), err)
+	}
+
+	// the unbound base copy of pod p must not overwrite its bound sandbox copy in the caches of sandbox clients.
+	wantEvents := []string{fmt.Sprintf("MODIFIED %s", q.Name)}
+	if diff := gocmp.Diff(wantEvents, gotEvents); diff != "" {
+		t.Errorf("watch events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListEventsWithoutNamespace(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []mkapi.View{b, s} {
+		if _, err = v.ListEvents(""); !apierrors.IsBadRequest(err) {
+			t.Errorf("expected bad request error when listing events without namespace in %s view, got %v", v.GetType(), err)
+		}
+	}
+}
+
+func TestListObjectsChunksConsistentAcrossSandboxChanges(t *testing.T) {
+	b, s, err := setup(t)
+	if err != nil {
+		return
+	}
+	gvk := typeinfo.PodsDescriptor.GVK
+	pods := make([]*corev1.Pod, 3)
+	for i := range pods {
+		pods[i] = testPods[0].DeepCopy()
+		pods[i].Name = fmt.Sprintf("%s-%d", pods[i].Name, i)
+		pods[i].ResourceVersion = ""
+		if err = storePod(t, b, pods[i]); err != nil {
+			return
+		}
+	}
+	c := mkapi.MatchCriteria{Namespace: pods[0].Namespace, Limit: 2}
+	listObj, err := s.ListObjects(gvk, c)
+	if err != nil {
+		t.Fatalf("failed to list first chunk: %v", err)
+	}
+	firstChunk := listObj.(*corev1.PodList)
+
+	// deleting a pod of the base view in the sandbox and creating another pod does not affect the remaining chunk.
+	if err = s.DeleteObject(gvk, objutil.CacheName(pods[2]), mkapi.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete pod in sandbox: %v", err)
+	}
+	p := pods[0].DeepCopy()
+	p.Name, p.UID, p.ResourceVersion = testPods[0].Name+"-9", "", ""
+	if err = storePod(t, s, p); err != nil {
+		return
+	}
+	c.Continue = firstChunk.Continue
+	listObj, err = s.ListObjects(gvk, c)
+	if err != nil {
+		t.Fatalf("failed to list second chunk: %v", err)
+	}
+	secondChunk := listObj.(*corev1.PodList)
+	var got []string
+	for _, list := range []*corev1.PodList{firstChunk, secondChunk} {
+		for _, item := range list.Items {
+			got = append(got, item.Name)
+		}
+	}
+	want := []string{pods[0].Name, pods[1].Name, pods[2].Name}
+	if diff := gocmp.Diff(want, got); diff != "" {
+		t.Errorf("list chunks mismatch (-want +got):\n%s", diff)
+	}
+	if secondChunk.ResourceVersion != firstChunk.ResourceVersion || secondChunk.Continue != "" {
+		t.Errorf("expected last chunk at resourceVersion %q, got resourceVersion %q and continue %q", firstChunk.ResourceVersion, secondChunk.ResourceVersion, secondChunk.Continue)
+	}
+}
@@ -18,12 +18,15 @@ type Generator struct {
 	ctx               context.Context
 	log               logr.Logger
 	args              *Args
-	minKAPIConfig     mkapi.Config
 	minKAPIServer     mkapi.Server
 	schedulerLauncher svcapi.SchedulerLauncher
 }
 
 type Args struct {
+	// MinKAPIServer is the started MinKAPI server whose base view has been populated for the Request and in whose
+	// sandbox views the simulations run.
+	MinKAPIServer     mkapi.Server
+	SchedulerLauncher svcapi.SchedulerLauncher
 	Pricer            svcapi.InstanceTypeInfoAccess
 	WeightsFn         svcapi.GetWeightsFunc
 	Scorer            svcapi.NodeScorer
@@ -36,16 +39,13 @@ type Args struct {
 
 func New(ctx context.Context, args *Args) *Generator {
 	return &Generator{
-		ctx:  ctx,
-		log:  logr.FromContextOrDiscard(ctx),
-		args: args,
+		ctx:               ctx,
+		log:               logr.FromContextOrDiscard(ctx),
+		args:              args,
+		minKAPIServer:     args.MinKAPIServer,
+		schedulerLauncher: args.SchedulerLauncher,
 	}
 }
-func (g *Generator) populateBaseView() error {
-	baseView := g.minKAPIServer.GetBaseView()
-	baseView.Reset()
-	return nil
-}
 
 func (g *Generator) Generate() {
 	err := g.doGenerate()
@@ -58,10 +58,6 @@ func (g *Generator) Generate() {
 }
 
 func (g *Generator) doGenerate() (err error) {
-	if err = g.populateBaseView(); err != nil {
-		return
-	}
-
 	groups, err := g.createSimulationGroups()
 	if err != nil {
 		return
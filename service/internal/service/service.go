@@ -17,10 +17,17 @@ import (
 	"github.com/gardener/scaling-advisor/service/internal/service/generator"
 	"github.com/gardener/scaling-advisor/service/internal/service/simulation"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+	"time"
 )
 
 var _ svcapi.ScalingAdvisorService = (*defaultScalingAdvisor)(nil)
 
+// baseViewSyncPollInterval is the interval at which populateBaseView checks whether the base view has been synced in
+// mirror mode.
+const baseViewSyncPollInterval = 100 * time.Millisecond
+
 type defaultScalingAdvisor struct {
 	minKAPIConfig     mkapi.Config
 	minKAPIServer     mkapi.Server
@@ -40,6 +47,12 @@ func New(config svcapi.ScalingAdvisorServiceConfig,
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", svcapi.ErrInitFailed, err)
 	}
+	if config.Clock == nil {
+		config.Clock = clock.RealClock{}
+	}
+	if config.MinKAPIConfig.Clock == nil {
+		config.MinKAPIConfig.Clock = config.Clock
+	}
 	return &defaultScalingAdvisor{
 		minKAPIConfig:     config.MinKAPIConfig,
 		schedulerLauncher: schedulerLauncher,
@@ -67,9 +80,18 @@ func (d *defaultScalingAdvisor) Start(ctx context.Context) (err error) {
 	return nil
 }
 
-func populateBaseView(view mkapi.View, cs *svcapi.ClusterSnapshot) error {
+// populateBaseView populates the base view of the given server with the objects of the given cluster snapshot. In
+// mirror mode, the base view is kept in sync with the cluster by the server itself, so that it is neither reset nor
+// populated, but populateBaseView waits until it has been synced or the given ctx is cancelled.
+func populateBaseView(ctx context.Context, server mkapi.Server, mirrorConfig mkapi.MirrorConfig, cs *svcapi.ClusterSnapshot) error {
+	if mirrorConfig.Enabled() {
+		return wait.PollUntilContextCancel(ctx, baseViewSyncPollInterval, true, func(context.Context) (bool, error) {
+			return server.HasBaseViewSynced(), nil
+		})
+	}
 	// TODO implement delta cluster snapshot to update the base view before every simulation run which will synchronize
 	// the base view with the current state of the target cluster.
+	view := server.GetBaseView()
 	view.Reset()
 	for _, nodeInfo := range cs.Nodes {
 		if err := view.CreateObject(typeinfo.NodesDescriptor.GVK, nodeutil.AsNode(nodeInfo)); err != nil {
@@ -102,8 +124,16 @@ func (d *defaultScalingAdvisor) GenerateAdvice(ctx context.Context, request svca
 			}
 			return
 		}
+		if err := populateBaseView(ctx, d.minKAPIServer, d.minKAPIConfig.MirrorConfig, &request.Snapshot); err != nil {
+			eventCh <- svcapi.ScalingAdviceEvent{
+				Err: svcapi.AsGenerateError(request.ID, request.CorrelationID, err),
+			}
+			return
+		}
 		genCtx := logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithValues("requestID", request.ID, "correlationID", request.CorrelationID))
 		g := generator.New(genCtx, &generator.Args{
+			MinKAPIServer:     d.minKAPIServer,
+			SchedulerLauncher: d.schedulerLauncher,
 			Pricer:            d.pricer,
 			WeightsFn:         d.weighsFn,
 			Scorer:            d.scorer,
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	mkapi "github.com/gardener/scaling-advisor/api/minkapi"
+	svcapi "github.com/gardener/scaling-advisor/api/service"
+	"github.com/gardener/scaling-advisor/minkapi/server/typeinfo"
+	"github.com/gardener/scaling-advisor/minkapi/server/view"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeServer is a mkapi.Server serving only the given base view, which is synced according to synced.
+type fakeServer struct {
+	mkapi.Server
+	baseView mkapi.View
+	synced   bool
+}
+
+func (s *fakeServer) GetBaseView() mkapi.View {
+	return s.baseView
+}
+
+func (s *fakeServer) HasBaseViewSynced() bool {
+	return s.synced
+}
+
+func TestPopulateBaseView(t *testing.T) {
+	snapshot := &svcapi.ClusterSnapshot{
+		Nodes: []svcapi.NodeInfo{{ResourceMeta: svcapi.ResourceMeta{NamespacedName: types.NamespacedName{Name: "snapshot-node"}}}},
+		Pods:  []svcapi.PodInfo{{ResourceMeta: svcapi.ResourceMeta{NamespacedName: types.NamespacedName{Namespace: "default", Name: "snapshot-pod"}}}},
+	}
+	tests := map[string]struct {
+		mirrorConfig mkapi.MirrorConfig
+		synced       bool
+		wantErr      error
+		wantNodes    []string
+		wantPods     []string
+	}{
+		"snapshot replaces base view": {
+			wantNodes: []string{"snapshot-node"},
+			wantPods:  []string{"snapshot-pod"},
+		},
+		"mirrored base view is retained": {
+			mirrorConfig: mkapi.MirrorConfig{SourceKubeConfigPath: "source.yaml"},
+			synced:       true,
+			wantNodes:    []string{"mirrored-node"},
+		},
+		"mirrored base view is awaited": {
+			mirrorConfig: mkapi.MirrorConfig{SourceKubeConfigPath: "source.yaml"},
+			wantErr:      context.DeadlineExceeded,
+			wantNodes:    []string{"mirrored-node"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			baseView, err := view.New(logr.Discard(), &mkapi.ViewArgs{
+				Name:           mkapi.DefaultBasePrefix,
+				KubeConfigPath: filepath.Join(t.TempDir(), "minkapi.yaml"),
+				Scheme:         typeinfo.SupportedScheme,
+				WatchConfig:    mkapi.WatchConfig{QueueSize: mkapi.DefaultWatchQueueSize, Timeout: mkapi.DefaultWatchTimeout},
+			})
+			if err != nil {
+				t.Fatalf("failed to create base view: %v", err)
+			}
+			t.Cleanup(func() {
+				_ = baseView.Close()
+			})
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "mirrored-node"}}
+			if err = baseView.CreateObject(typeinfo.NodesDescriptor.GVK, node); err != nil {
+				t.Fatalf("failed to create node: %v", err)
+			}
+			ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+			defer cancel()
+
+			err = populateBaseView(ctx, &fakeServer{baseView: baseView, synced: tc.synced}, tc.mirrorConfig, snapshot)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			nodes, err := baseView.ListNodes()
+			if err != nil {
+				t.Fatalf("failed to list nodes: %v", err)
+			}
+			pods, err := baseView.ListPods("default")
+			if err != nil {
+				t.Fatalf("failed to list pods: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantNodes, namesOf(nodes, func(n corev1.Node) string { return n.Name })); diff != "" {
+				t.Errorf("nodes of base view mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPods, namesOf(pods, func(p corev1.Pod) string { return p.Name })); diff != "" {
+				t.Errorf("pods of base view mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func namesOf[T any](objs []T, nameFn func(T) string) (names []string) {
+	for _, o := range objs {
+		names = append(names, nameFn(o))
+	}
+	slices.Sort(names)
+	return
+}